@@ -0,0 +1,262 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Address is a Thai postal address split into its parts. Parts missing from
+// the text are empty.
+type Address struct {
+	HouseNumber string `json:"house_number"` // e.g. 123/45
+	Village     string `json:"village"`      // หมู่บ้าน, a housing estate
+	Moo         string `json:"moo"`          // หมู่, the village number of rural addresses
+	Soi         string `json:"soi"`          // ซอย, a lane
+	Road        string `json:"road"`         // ถนน
+	Subdistrict string `json:"subdistrict"`  // ตำบล, or แขวง in Bangkok
+	District    string `json:"district"`     // อำเภอ, or เขต in Bangkok
+	Province    string `json:"province"`     // In Thai, as in LocalProvinces when it is one of them
+	Postcode    string `json:"postcode"`
+
+	// Unparsed is what was left over, such as a building or a name
+	Unparsed string `json:"unparsed,omitempty"`
+}
+
+// addressField selects a field of Address
+type addressField int
+
+const (
+	fieldVillage addressField = iota
+	fieldMoo
+	fieldSoi
+	fieldRoad
+	fieldSubdistrict
+	fieldDistrict
+	fieldProvince
+)
+
+// addressMarkers are the words introducing each part of an address, longest
+// first where one is the prefix of another
+var addressMarkers = []struct {
+	marker string
+	field  addressField
+}{
+	{"หมู่บ้าน", fieldVillage},
+	{"หมู่ที่", fieldMoo}, {"หมู่", fieldMoo}, {"ม.", fieldMoo},
+	{"ซอย", fieldSoi}, {"ซ.", fieldSoi},
+	{"ถนน", fieldRoad}, {"ถ.", fieldRoad},
+	{"ตำบล", fieldSubdistrict}, {"แขวง", fieldSubdistrict}, {"ต.", fieldSubdistrict},
+	{"อำเภอ", fieldDistrict}, {"เขต", fieldDistrict}, {"อ.", fieldDistrict},
+	{"จังหวัด", fieldProvince}, {"จ.", fieldProvince},
+}
+
+var (
+	postcodeRe    = regexp.MustCompile(`(?:^|[^0-9๐-๙])([1-9๑-๙][0-9๐-๙]{4})(?:$|[^0-9๐-๙/])`)
+	houseNumberRe = regexp.MustCompile(`^(?:บ้านเลขที่|เลขที่)?\s*([0-9๐-๙]+(?:/[0-9๐-๙]+)?)`)
+)
+
+// ParseAddress splits a free-form Thai address into its parts, e.g.
+// "99/1 ม.3 ต.สุเทพ อ.เมือง จ.เชียงใหม่ 50200" or, without the usual
+// markers, "123 สุขุมวิท 21 คลองเตย วัฒนา กรุงเทพฯ 10110".
+//
+// Parts introduced by a marker (ถนน or ถ., ตำบล, ต. or แขวง, อำเภอ, อ. or
+// เขต, ...) are recognized anywhere, with or without spaces; the text is
+// tokenized so that a marker is only taken at the start of a word, and
+// หมู่บ้าน is not read as หมู่. The province is also found without a marker
+// by its name, the postcode as the last five-digit number, and the house
+// number at the beginning. Remaining space-separated parts are assigned from
+// the right, in the usual order: road, subdistrict, district.
+func (pm *PyThaiNLPManager) ParseAddress(ctx context.Context, text string) (*Address, error) {
+	text = strings.TrimSpace(text)
+	result, err := pm.Tokenize(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("address parsing failed: %w", err)
+	}
+	wordStarts := make(map[int]bool, len(result.Tokens))
+	for _, t := range result.Tokens {
+		wordStarts[t.Start] = true
+	}
+	return parseAddress(text, wordStarts), nil
+}
+
+func parseAddress(text string, wordStarts map[int]bool) *Address {
+	addr := &Address{}
+
+	// The postcode goes first, so that it is not taken for part of a value
+	if locs := postcodeRe.FindAllStringSubmatchIndex(text, -1); locs != nil {
+		loc := locs[len(locs)-1]
+		addr.Postcode = ToArabicDigits(text[loc[2]:loc[3]])
+		// Blank it out without moving the words after it
+		text = text[:loc[2]] + strings.Repeat(" ", loc[3]-loc[2]) + text[loc[3]:]
+	}
+
+	type marked struct {
+		start, end int
+		field      addressField
+	}
+	var marks []marked
+	for i := 0; i < len(text); i++ {
+		if !wordStarts[i] && i > 0 && text[i-1] != ' ' {
+			continue
+		}
+		for _, m := range addressMarkers {
+			if strings.HasPrefix(text[i:], m.marker) {
+				marks = append(marks, marked{i, i + len(m.marker), m.field})
+				i += len(m.marker) - 1
+				break
+			}
+		}
+	}
+
+	head := text
+	if len(marks) > 0 {
+		head = text[:marks[0].start]
+	}
+	var tail string
+	for k, m := range marks {
+		end := len(text)
+		if k+1 < len(marks) {
+			end = marks[k+1].start
+		}
+		value := trimAddressPart(text[m.end:end])
+		switch {
+		case m.field == fieldProvince:
+			// What follows the province name, if anything, is left over
+			if p, rest, ok := splitProvince(value); ok {
+				value, tail = p, rest
+			}
+		case k == len(marks)-1:
+			// The last part ends at its first word and the numbers after it,
+			// and may have the province written right after it
+			words := strings.Fields(value)
+			n := 1
+			for n < len(words) && isAddressNumber(words[n]) {
+				n++
+			}
+			if len(words) > 0 {
+				value, tail = strings.Join(words[:n], " "), strings.Join(words[n:], " ")
+			}
+			if p, before, ok := splitProvinceSuffix(value); ok && tail == "" {
+				value = before
+				addr.Province = p
+			}
+		}
+		addr.set(m.field, value)
+	}
+
+	if m := houseNumberRe.FindStringSubmatch(head); m != nil {
+		addr.HouseNumber = ToArabicDigits(m[1])
+		head = head[len(m[0]):]
+	}
+
+	// Parts without a marker: the province by its name, then from the right.
+	// With markers, what comes before them, such as a building, is left over.
+	var leftover []string
+	parts := addressParts(head)
+	if len(marks) > 0 {
+		leftover, parts = parts, addressParts(tail)
+	}
+	if addr.Province == "" && len(parts) > 0 {
+		if p, rest, ok := splitProvince(parts[len(parts)-1]); ok {
+			addr.Province = p
+			parts = parts[:len(parts)-1]
+			if rest != "" {
+				parts = append(parts, rest)
+			}
+		} else if p, before, ok := splitProvinceSuffix(parts[len(parts)-1]); ok {
+			addr.Province = p
+			parts[len(parts)-1] = before
+		}
+	}
+	for _, field := range []*string{&addr.District, &addr.Subdistrict, &addr.Road} {
+		if *field != "" || len(parts) == 0 {
+			continue
+		}
+		*field = parts[len(parts)-1]
+		parts = parts[:len(parts)-1]
+	}
+	parts = append(leftover, parts...)
+	addr.Unparsed = strings.Join(parts, " ")
+	return addr
+}
+
+func (a *Address) set(field addressField, value string) {
+	switch field {
+	case fieldVillage:
+		a.Village = value
+	case fieldMoo:
+		a.Moo = ToArabicDigits(value)
+	case fieldSoi:
+		a.Soi = value
+	case fieldRoad:
+		a.Road = value
+	case fieldSubdistrict:
+		a.Subdistrict = value
+	case fieldDistrict:
+		a.District = value
+	case fieldProvince:
+		a.Province = value
+		if p, ok := LookupProvince(value); ok {
+			a.Province = p.Thai
+		}
+	}
+}
+
+// splitProvince recognizes a province name at the start of s, written alone
+// or followed by a space and more text
+func splitProvince(s string) (province, rest string, ok bool) {
+	name, rest, _ := strings.Cut(s, " ")
+	if p, found := LookupProvince(name); found {
+		return p.Thai, strings.TrimSpace(rest), true
+	}
+	if p, found := LookupProvince(s); found {
+		return p.Thai, "", true
+	}
+	return "", "", false
+}
+
+// splitProvinceSuffix recognizes a province name written at the end of s
+// without a space: after เมือง, the district of the provincial capital, as in
+// เมืองเชียงใหม่, or Bangkok anywhere. Other district names may end with a
+// province name, as บ้านตาก does with ตาก, so they are left whole.
+func splitProvinceSuffix(s string) (province, before string, ok bool) {
+	if name, found := strings.CutPrefix(s, "เมือง"); found {
+		if p, found := LookupProvince(name); found {
+			return p.Thai, "เมือง", true
+		}
+	}
+	for _, name := range []string{"กรุงเทพฯ", "กทม."} {
+		if b, found := strings.CutSuffix(s, name); found && b != "" {
+			return "กรุงเทพมหานคร", b, true
+		}
+	}
+	return "", "", false
+}
+
+// addressParts splits s at spaces, keeping a number with the name before it,
+// as in สุขุมวิท 21
+func addressParts(s string) []string {
+	var parts []string
+	for _, word := range strings.Fields(s) {
+		word = trimAddressPart(word)
+		if word == "" {
+			continue
+		}
+		if n := len(parts); n > 0 && isAddressNumber(word) {
+			parts[n-1] += " " + word
+			continue
+		}
+		parts = append(parts, word)
+	}
+	return parts
+}
+
+func isAddressNumber(s string) bool {
+	return strings.Trim(s, "0123456789๐๑๒๓๔๕๖๗๘๙/") == ""
+}
+
+func trimAddressPart(s string) string {
+	return strings.Trim(s, " ,\t\n")
+}
@@ -0,0 +1,63 @@
+package pythainlp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	cases := []struct {
+		// tokens are the text as the tokenizer splits it
+		tokens []string
+		want   Address
+	}{
+		{
+			[]string{"99/1", " ", "ม.", "3", " ", "ต.", "สุเทพ", " ", "อ.", "เมือง", " ", "จ.", "เชียงใหม่", " ", "50200"},
+			Address{HouseNumber: "99/1", Moo: "3", Subdistrict: "สุเทพ", District: "เมือง", Province: "เชียงใหม่", Postcode: "50200"},
+		},
+		{
+			[]string{"123", " ", "สุขุมวิท", " ", "21", " ", "คลองเตย", " ", "วัฒนา", " ", "กรุงเทพฯ", " ", "10110"},
+			Address{HouseNumber: "123", Road: "สุขุมวิท 21", Subdistrict: "คลองเตย", District: "วัฒนา", Province: "กรุงเทพมหานคร", Postcode: "10110"},
+		},
+		{
+			// Bangkok's แขวง and เขต, without spaces
+			[]string{"55", " ", "ถนน", "พระราม", "4", " ", "แขวง", "ปทุมวัน", "เขต", "ปทุมวัน", " ", "กรุงเทพมหานคร", " ", "10330"},
+			Address{HouseNumber: "55", Road: "พระราม4", Subdistrict: "ปทุมวัน", District: "ปทุมวัน", Province: "กรุงเทพมหานคร", Postcode: "10330"},
+		},
+		{
+			[]string{"10", " ", "แขวง", "ลุมพินี", " ", "เขต", "ปทุมวัน", " ", "กทม.", " ", "10330"},
+			Address{HouseNumber: "10", Subdistrict: "ลุมพินี", District: "ปทุมวัน", Province: "กรุงเทพมหานคร", Postcode: "10330"},
+		},
+		{
+			// A district whose name ends with that of its province
+			[]string{"12", " ", "ต.", "สามเงา", " ", "อ.", "บ้านตาก", " ", "จ.", "ตาก", " ", "63130"},
+			Address{HouseNumber: "12", Subdistrict: "สามเงา", District: "บ้านตาก", Province: "ตาก", Postcode: "63130"},
+		},
+		{
+			[]string{"12", " ", "สามเงา", " ", "บ้านตาก", " ", "ตาก", " ", "63130"},
+			Address{HouseNumber: "12", Subdistrict: "สามเงา", District: "บ้านตาก", Province: "ตาก", Postcode: "63130"},
+		},
+		{
+			// The province written right after the district of its capital
+			[]string{"7", " ", "ต.", "ในเมือง", " ", "อ.", "เมือง", "ขอนแก่น", " ", "40000"},
+			Address{HouseNumber: "7", Subdistrict: "ในเมือง", District: "เมือง", Province: "ขอนแก่น", Postcode: "40000"},
+		},
+		{
+			// หมู่บ้าน is not read as หมู่
+			[]string{"8/2", " ", "หมู่บ้าน", "สวนทอง", " ", "หมู่", "5", " ", "ต.", "บางพลีใหญ่", " ", "อ.", "บางพลี", " ", "จ.", "สมุทรปราการ"},
+			Address{HouseNumber: "8/2", Village: "สวนทอง", Moo: "5", Subdistrict: "บางพลีใหญ่", District: "บางพลี", Province: "สมุทรปราการ"},
+		},
+	}
+	for _, c := range cases {
+		text := strings.Join(c.tokens, "")
+		starts := make(map[int]bool, len(c.tokens))
+		offset := 0
+		for _, tok := range c.tokens {
+			starts[offset] = true
+			offset += len(tok)
+		}
+		if got := parseAddress(text, starts); *got != c.want {
+			t.Errorf("parseAddress(%q) =\n%+v, want\n%+v", text, *got, c.want)
+		}
+	}
+}
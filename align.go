@@ -0,0 +1,50 @@
+package pythainlp
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// AlignedSegment maps a segment of romanized or transliterated output back to
+// the input text, e.g. to overlay the output on rendered source text. The
+// segments of an alignment cover the whole input, in order.
+type AlignedSegment struct {
+	Input  string // Segment of the input text
+	Output string // Its romanization or transcription; Input itself for spaces, punctuation and other non-Thai text
+	Start  int    // Rune offset of Input in the input text
+	End    int
+}
+
+// alignSegments pairs the tokens of text with their outputs. Text the
+// tokenizer dropped is kept as segments of its own, so that no spacing or
+// punctuation is lost. From the first token not found in text, e.g. because
+// the engine normalized it, the rest of the text is left as one segment.
+func alignSegments(text string, tokens, outputs []string) []AlignedSegment {
+	var segments []AlignedSegment
+	pos, char := 0, 0 // byte and rune position in text
+	add := func(input, output string) {
+		n := utf8.RuneCountInString(input)
+		segments = append(segments, AlignedSegment{Input: input, Output: output, Start: char, End: char + n})
+		pos += len(input)
+		char += n
+	}
+	for i, token := range tokens {
+		j := strings.Index(text[pos:], token)
+		if token == "" || j < 0 {
+			break
+		}
+		if j > 0 {
+			gap := text[pos : pos+j]
+			add(gap, gap)
+		}
+		output := token
+		if i < len(outputs) && isThaiText(token) {
+			output = outputs[i]
+		}
+		add(token, output)
+	}
+	if pos < len(text) {
+		add(text[pos:], text[pos:])
+	}
+	return segments
+}
@@ -1,32 +1,63 @@
 package pythainlp
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 )
 
-// AnalyzeText performs combined analysis with tokenization and romanization
-func (pm *PyThaiNLPManager) AnalyzeText(ctx context.Context, text string) (*AnalyzeResult, error) {
-	opts := AnalyzeOptions{
-		Features: []string{"tokenize", "romanize"},
-	}
-	return pm.AnalyzeWithOptions(ctx, text, opts)
+// AnalyzeText performs combined analysis with tokenization and romanization,
+// unless opts say otherwise, e.g. WithFeatures
+func (pm *PyThaiNLPManager) AnalyzeText(ctx context.Context, text string, opts ...CallOption) (*AnalyzeResult, error) {
+	return pm.AnalyzeWithOptions(ctx, text, NewAnalyzeOptions(opts...))
 }
 
 // AnalyzeWithOptions performs combined analysis with specified options
 func (pm *PyThaiNLPManager) AnalyzeWithOptions(ctx context.Context, text string, opts AnalyzeOptions) (*AnalyzeResult, error) {
-	if !pm.IsReady() {
-		return nil, fmt.Errorf("service not ready")
+	// A handler cannot be part of the cache key, and its output may change
+	if opts.NonThai != nil {
+		return pm.analyze(ctx, text, opts)
+	}
+	return cached(pm, "analyze", text, opts, func() (*AnalyzeResult, error) {
+		return pm.analyze(ctx, text, opts)
+	})
+}
+
+// analyze is AnalyzeWithOptions without the cache
+func (pm *PyThaiNLPManager) analyze(ctx context.Context, text string, opts AnalyzeOptions) (*AnalyzeResult, error) {
+	if err := errors.Join(
+		opts.TokenizeEngine.Validate(),
+		opts.RomanizeEngine.Validate(),
+		opts.TransliterateEngine.Validate(),
+		opts.SyllableEngine.Validate(),
+		opts.LemmaEngine.Validate(),
+		opts.Granularity.Validate(),
+	); err != nil {
+		return nil, err
+	}
+	if opts.ThaiOnly {
+		return pm.analyzeThaiSpans(ctx, text, opts)
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+	if err := pm.client.checkText(text); err != nil {
+		return nil, err
 	}
 
 	// Prepare request
 	req := &AnalyzeRequest{
 		Text:                text,
 		Features:            opts.Features,
-		TokenizeEngine:      opts.TokenizeEngine,
-		RomanizeEngine:      opts.RomanizeEngine,
-		TransliterateEngine: opts.TransliterateEngine,
-		SyllableEngine:      opts.SyllableEngine,
+		TokenizeEngine:      string(opts.TokenizeEngine),
+		RomanizeEngine:      string(opts.RomanizeEngine),
+		TransliterateEngine: string(opts.TransliterateEngine),
+		SyllableEngine:      string(opts.SyllableEngine),
+		LemmaEngine:         string(opts.LemmaEngine),
+		Granularity:         string(opts.Granularity),
+		BatchSize:           pm.romanizeBatchSize,
+		Preprocess:          opts.Preprocess,
 	}
 
 	// Set default features if not specified
@@ -40,10 +71,10 @@ func (pm *PyThaiNLPManager) AnalyzeWithOptions(ctx context.Context, text string,
 		return nil, fmt.Errorf("analysis failed: %w", err)
 	}
 
-	// Extract processing time
-	var processingTime float64
-	if v, ok := resp.Metadata["processing_time_ms"].(float64); ok {
-		processingTime = v
+	// The tokenization engine stands for the analysis, as chosen for EngineAuto
+	meta := newMetadata(resp.Metadata, string(cmp.Or(opts.TokenizeEngine, EngineNewMM)))
+	if v, ok := meta.Extra["tokenize_engine"].(string); ok {
+		meta.Engine = v
 	}
 
 	// Build result
@@ -52,9 +83,11 @@ func (pm *PyThaiNLPManager) AnalyzeWithOptions(ctx context.Context, text string,
 		Romanized:      resp.Data.Romanized,
 		RomanizedParts: resp.Data.RomanizedTokens,
 		Phonetic:       resp.Data.Phonetic,
+		PhoneticParts:  resp.Data.PhoneticTokens,
 		Syllables:      resp.Data.Syllables,
+		Text:           resp.Data.Text,
 		Features:       req.Features,
-		ProcessingTime: processingTime,
+		Meta:           meta,
 	}
 
 	// Create Token objects
@@ -70,6 +103,18 @@ func (pm *PyThaiNLPManager) AnalyzeWithOptions(ctx context.Context, text string,
 			if len(resp.Data.RomanizedTokens) > i {
 				t.Romanization = resp.Data.RomanizedTokens[i]
 			}
+			if len(resp.Data.PhoneticTokens) > i {
+				t.IPA = resp.Data.PhoneticTokens[i]
+			}
+			if len(resp.Data.FrequencyRanks) > i {
+				t.FrequencyRank = resp.Data.FrequencyRanks[i]
+			}
+			if len(resp.Data.Frequencies) > i {
+				t.Frequency = resp.Data.Frequencies[i]
+			}
+			if len(resp.Data.Lemmas) > i {
+				t.Lemma = resp.Data.Lemmas[i]
+			}
 			
 			result.Tokens[i] = t
 		}
@@ -83,24 +128,45 @@ func (pm *PyThaiNLPManager) TokenizeAndRomanize(ctx context.Context, text string
 	return pm.AnalyzeText(ctx, text)
 }
 
-// GetSupportedEngines returns the list of supported engines for each operation
-func (pm *PyThaiNLPManager) GetSupportedEngines(ctx context.Context) (map[string][]string, error) {
-	if !pm.IsReady() {
-		return nil, fmt.Errorf("service not ready")
+// GetSupportedEngines reports every known engine with its availability in
+// the running container and the state of its model
+func (pm *PyThaiNLPManager) GetSupportedEngines(ctx context.Context) (*EngineReport, error) {
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
 	}
 
-	health, err := pm.client.Health(ctx)
+	engines, err := pm.client.Engines(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get engine info: %w", err)
 	}
 
-	return health.Engines, nil
+	return &EngineReport{Engines: engines}, nil
+}
+
+// ProbeEngine checks in the running container whether an engine can be used,
+// e.g. before offering it in a settings screen. operation is one of those of
+// EngineInfo, such as "tokenize" or "romanize". The service imports the
+// engine's package and, if its model is already on disk, loads it, so that
+// the next call does not wait; it never downloads a model. Engines fetching
+// their model from the Hugging Face hub (wtp, thaig2p_v2,
+// sentence_transformers, esupar) are not loaded. An unknown engine gets an error
+// wrapping ErrEngineUnavailable.
+func (pm *PyThaiNLPManager) ProbeEngine(ctx context.Context, operation, engine string) (*EngineProbe, error) {
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+
+	probe, err := pm.client.ProbeEngine(ctx, operation, engine)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe engine: %w", err)
+	}
+	return probe, nil
 }
 
 // GetVersion returns the PyThaiNLP version
 func (pm *PyThaiNLPManager) GetVersion(ctx context.Context) (string, error) {
-	if !pm.IsReady() {
-		return "", fmt.Errorf("service not ready")
+	if err := pm.ensureReady(ctx); err != nil {
+		return "", err
 	}
 
 	health, err := pm.client.Health(ctx)
@@ -111,16 +177,31 @@ func (pm *PyThaiNLPManager) GetVersion(ctx context.Context) (string, error) {
 	return health.Version, nil
 }
 
+// GetRestartCount returns how many times the in-container supervisor had to
+// restart a crashed Python service
+func (pm *PyThaiNLPManager) GetRestartCount(ctx context.Context) (int, error) {
+	if err := pm.ensureReady(ctx); err != nil {
+		return 0, err
+	}
+
+	health, err := pm.client.Health(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get restart count: %w", err)
+	}
+
+	return health.Restarts, nil
+}
+
 // Package-level convenience functions
 
 // AnalyzeText performs combined analysis with tokenization and romanization
-func AnalyzeText(text string) (*AnalyzeResult, error) {
+func AnalyzeText(text string, opts ...CallOption) (*AnalyzeResult, error) {
 	ctx := context.Background()
 	mgr, err := getOrCreateDefaultManager(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return mgr.AnalyzeText(ctx, text)
+	return mgr.AnalyzeText(ctx, text, opts...)
 }
 
 // AnalyzeWithOptions performs combined analysis with specified options
@@ -138,8 +219,8 @@ func TokenizeAndRomanize(text string) (*AnalyzeResult, error) {
 	return AnalyzeText(text)
 }
 
-// GetSupportedEngines returns the list of supported engines
-func GetSupportedEngines() (map[string][]string, error) {
+// GetSupportedEngines reports the engines of the default instance
+func GetSupportedEngines() (*EngineReport, error) {
 	ctx := context.Background()
 	mgr, err := getOrCreateDefaultManager(ctx)
 	if err != nil {
@@ -158,6 +239,16 @@ func GetVersion() (string, error) {
 	return mgr.GetVersion(ctx)
 }
 
+// GetRestartCount returns how many times the Python service was restarted
+func GetRestartCount() (int, error) {
+	ctx := context.Background()
+	mgr, err := getOrCreateDefaultManager(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return mgr.GetRestartCount(ctx)
+}
+
 // Utility functions for working with results
 
 // JoinTokens joins tokens into a single string
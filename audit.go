@@ -0,0 +1,210 @@
+package pythainlp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditRecord is one request to the service and its response
+type AuditRecord struct {
+	Time     time.Time       `json:"time"`
+	Method   string          `json:"method"`
+	Path     string          `json:"path"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Status   int             `json:"status"` // 0 when no response was received
+	Response json.RawMessage `json:"response,omitempty"`
+	Duration time.Duration   `json:"duration"`
+	Err      string          `json:"error,omitempty"` // Why no response was received
+}
+
+// AuditSink receives the records of WithAuditLog. Record is called from the
+// goroutine making the request, so it must be safe for concurrent use and
+// should not block.
+type AuditSink interface {
+	Record(rec AuditRecord)
+}
+
+// AuditSinkFunc adapts a function to an AuditSink
+type AuditSinkFunc func(rec AuditRecord)
+
+func (f AuditSinkFunc) Record(rec AuditRecord) {
+	f(rec)
+}
+
+// jsonlAuditSink writes one JSON object per line
+type jsonlAuditSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLAuditSink returns a sink writing each record to w as a line of
+// JSON, e.g. to an *os.File
+func NewJSONLAuditSink(w io.Writer) AuditSink {
+	return &jsonlAuditSink{w: w}
+}
+
+func (s *jsonlAuditSink) Record(rec AuditRecord) {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		Logger.Warn().Err(err).Msg("Failed to encode audit record")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(append(line, '\n')); err != nil {
+		Logger.Warn().Err(err).Msg("Failed to write audit record")
+	}
+}
+
+// Redaction selects how WithAuditLog records the texts sent and received
+type Redaction int
+
+const (
+	RedactNone     Redaction = iota // Record texts as they are
+	RedactHash                      // Replace each text by a hash, the same for the same text
+	RedactTruncate                  // Keep the beginning of each text
+)
+
+// defaultAuditTextLength is how many characters RedactTruncate keeps by default
+const defaultAuditTextLength = 16
+
+// AuditOptions configures WithAuditLog
+type AuditOptions struct {
+	Redaction Redaction
+	// MaxTextLength is how many characters RedactTruncate keeps (default 16)
+	MaxTextLength int
+}
+
+// WithAuditLog records every request to the service and its response in
+// sink, to find the inputs breaking an engine in production. With a
+// Redaction, texts and tokens are hashed or truncated; engine names, options
+// naming an engine, metadata and errors are kept as they are. Health and
+// stats queries are not recorded.
+func WithAuditLog(sink AuditSink, opts AuditOptions) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.audit = &auditTransport{sink: sink, opts: opts}
+	}
+}
+
+// auditTransport records the requests passing through it
+type auditTransport struct {
+	sink AuditSink
+	opts AuditOptions
+	next http.RoundTripper
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet && (req.URL.Path == "/health" || req.URL.Path == "/stats") {
+		return t.next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	rec := AuditRecord{
+		Time:    time.Now(),
+		Method:  req.Method,
+		Path:    req.URL.Path,
+		Request: t.redact(body),
+	}
+	resp, err := t.next.RoundTrip(req)
+	rec.Duration = time.Since(rec.Time)
+	if err != nil {
+		rec.Err = err.Error()
+		t.sink.Record(rec)
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		rec.Err = err.Error()
+		t.sink.Record(rec)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	rec.Status = resp.StatusCode
+	rec.Response = t.redact(respBody)
+	t.sink.Record(rec)
+	return resp, nil
+}
+
+// redact returns body as a JSON value with its texts redacted, or nil if it
+// is not JSON
+func (t *auditTransport) redact(body []byte) json.RawMessage {
+	raw := rawJSON(body)
+	if raw == nil || t.opts.Redaction == RedactNone {
+		return raw
+	}
+	var v any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil
+	}
+	redacted, err := json.Marshal(t.redactValue(v))
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+func (t *auditTransport) redactValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		return t.redactText(v)
+	case []any:
+		for i := range v {
+			v[i] = t.redactValue(v[i])
+		}
+	case map[string]any:
+		for key, value := range v {
+			if !auditKeepsKey(key) {
+				v[key] = t.redactValue(value)
+			}
+		}
+	}
+	return v
+}
+
+func (t *auditTransport) redactText(s string) string {
+	if t.opts.Redaction == RedactHash {
+		sum := sha256.Sum256([]byte(s))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	}
+	n := t.opts.MaxTextLength
+	if n <= 0 {
+		n = defaultAuditTextLength
+	}
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n]) + "…"
+	}
+	return s
+}
+
+// auditKeepsKey reports whether the value of a request or response field is
+// recorded without redaction
+func auditKeepsKey(key string) bool {
+	switch key {
+	case "metadata", "error", "lookup_fallback", "lang", "model":
+		return true
+	}
+	return strings.HasSuffix(key, "engine")
+}
@@ -0,0 +1,232 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/api/types/volume"
+)
+
+// Backend identifies the product providing the Docker daemon
+type Backend string
+
+const (
+	BackendDockerEngine  Backend = "Docker Engine"
+	BackendDockerDesktop Backend = "Docker Desktop"
+	BackendColima        Backend = "Colima"
+	BackendOrbStack      Backend = "OrbStack"
+)
+
+// BackendInfo describes the container backend and the quirks that affect the service
+type BackendInfo struct {
+	Backend     Backend
+	Rootless    bool
+	VM          bool     // The daemon runs in a VM whose memory and file sharing are limited
+	SocketPath  string   // Daemon address, e.g. unix:///Users/me/.colima/default/docker.sock
+	MemoryTotal int64    // Memory available to the daemon
+	SharedPaths []string // Host paths the VM can bind mount, nil if unrestricted
+	NamedVolume bool     // The data directory is not shared, so a named volume is used instead
+	Warnings    []string // Guidance to show the user
+}
+
+// Backend detects the container backend. The result is cached after the
+// first successful call; Init calls it to adjust the compose project.
+func (pm *PyThaiNLPManager) Backend(ctx context.Context) (*BackendInfo, error) {
+	pm.mu.Lock()
+	cached := pm.backend
+	pm.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	info, err := dockerClient.Info(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daemon info: %w", err)
+	}
+
+	backend := detectBackend(dockerClient.DaemonHost(), info, runtime.GOOS)
+	pm.checkBackendQuirks(backend, info)
+
+	pm.mu.Lock()
+	pm.backend = backend
+	pm.mu.Unlock()
+	return backend, nil
+}
+
+// detectBackend identifies the backend from the daemon address and info
+func detectBackend(host string, info system.Info, goos string) *BackendInfo {
+	b := &BackendInfo{
+		Backend:     BackendDockerEngine,
+		SocketPath:  host,
+		MemoryTotal: info.MemTotal,
+	}
+
+	osName := strings.ToLower(info.OperatingSystem)
+	name := strings.ToLower(info.Name)
+	lowerHost := strings.ToLower(host)
+
+	switch {
+	case strings.Contains(lowerHost, "orbstack") || strings.Contains(osName, "orbstack"):
+		b.Backend = BackendOrbStack
+	case strings.Contains(lowerHost, ".colima") || name == "colima" || strings.HasPrefix(name, "colima-"):
+		b.Backend = BackendColima
+	case strings.Contains(osName, "docker desktop"):
+		b.Backend = BackendDockerDesktop
+	}
+
+	for _, opt := range info.SecurityOptions {
+		if strings.Contains(opt, "name=rootless") {
+			b.Rootless = true
+		}
+	}
+
+	home, _ := os.UserHomeDir()
+	switch b.Backend {
+	case BackendColima:
+		// Colima mounts only the home directory and /tmp/colima by default
+		b.VM = true
+		b.SharedPaths = []string{home, "/tmp/colima"}
+	case BackendDockerDesktop:
+		b.VM = true
+		switch goos {
+		case "darwin":
+			b.SharedPaths = []string{"/Users", "/Volumes", "/private", "/tmp", "/var/folders"}
+		case "linux":
+			b.SharedPaths = []string{home}
+		}
+	case BackendOrbStack:
+		b.VM = true
+	}
+
+	return b
+}
+
+// checkBackendQuirks records guidance for the known limitations of the backend
+func (pm *PyThaiNLPManager) checkBackendQuirks(b *BackendInfo, info system.Info) {
+	if !isSharedPath(pm.dataDir, b.SharedPaths) {
+		b.NamedVolume = true
+		b.Warnings = append(b.Warnings, fmt.Sprintf(
+			"%s cannot bind mount %s, so corpora are kept in a Docker volume instead. "+
+				"Share the directory with the VM or set XDG_CONFIG_HOME to a shared path to keep them on the host",
+			b.Backend, pm.dataDir))
+	}
+
+	if b.VM && !pm.lightweightMode && b.MemoryTotal > 0 && b.MemoryTotal < minMemoryFull {
+		b.Warnings = append(b.Warnings, fmt.Sprintf(
+			"the %s VM has %d MB of memory, full mode needs about %d MB: raise the VM memory limit",
+			b.Backend, b.MemoryTotal>>20, minMemoryFull>>20))
+	}
+
+	if b.Rootless && info.CgroupVersion != "2" {
+		b.Warnings = append(b.Warnings,
+			"rootless Docker without cgroup v2 cannot report container stats: ResourceUsage will return zeros")
+	}
+}
+
+// applyBackend adjusts the compose project to the detected backend
+func (pm *PyThaiNLPManager) applyBackend(b *BackendInfo) {
+	service := pm.project.Services["pythainlp"]
+	if len(service.Volumes) == 0 {
+		return
+	}
+
+	if b.NamedVolume {
+		if pm.project.Volumes == nil {
+			pm.project.Volumes = types.Volumes{}
+		}
+		// External, since compose removes project volumes on Down and the
+		// corpora must survive Close and recreation; only Purge deletes it
+		pm.project.Volumes["data"] = types.VolumeConfig{Name: pm.dataVolume(), External: true}
+		service.Volumes[0].Type = types.VolumeTypeVolume
+		service.Volumes[0].Source = "data"
+	} else if b.VM {
+		// The container is the only writer, so relaxed consistency is safe and
+		// speeds up corpus loading through the VM's file sharing
+		service.Volumes[0].Consistency = "delegated"
+	}
+
+	pm.project.Services["pythainlp"] = service
+}
+
+// prepareBackend detects the backend and adapts the compose project before
+// the containers are created. Detection failures only disable the adjustments.
+func (pm *PyThaiNLPManager) prepareBackend(ctx context.Context) {
+	b, err := pm.Backend(ctx)
+	if err != nil {
+		Logger.Debug().Err(err).Msg("Failed to detect container backend")
+		return
+	}
+
+	Logger.Debug().
+		Str("backend", string(b.Backend)).
+		Bool("rootless", b.Rootless).
+		Bool("vm", b.VM).
+		Str("host", b.SocketPath).
+		Msg("Detected container backend")
+	for _, w := range b.Warnings {
+		Logger.Warn().Str("backend", string(b.Backend)).Msg(w)
+	}
+
+	pm.applyBackend(b)
+	if b.NamedVolume {
+		pm.createDataVolume(ctx)
+	}
+}
+
+// dataVolume returns the name of the Docker volume used in place of the data
+// directory when the backend cannot bind mount it
+func (pm *PyThaiNLPManager) dataVolume() string {
+	return filepath.Base(pm.dataDir) + "_data"
+}
+
+// usesDataVolume reports whether the data directory is replaced by a volume
+func (pm *PyThaiNLPManager) usesDataVolume() bool {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	return pm.backend != nil && pm.backend.NamedVolume
+}
+
+// createDataVolume creates the data volume, which compose expects to exist
+// since it is external. Creating an existing volume is a no-op.
+func (pm *PyThaiNLPManager) createDataVolume(ctx context.Context) {
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		Logger.Debug().Err(err).Msg("Failed to get Docker client")
+		return
+	}
+	defer dockerClient.Close()
+
+	if _, err := dockerClient.VolumeCreate(ctx, volume.CreateOptions{Name: pm.dataVolume()}); err != nil {
+		Logger.Warn().Err(err).Str("volume", pm.dataVolume()).Msg("Failed to create data volume")
+	}
+}
+
+// isSharedPath reports whether path lies under one of shared, or whether
+// sharing is unrestricted
+func isSharedPath(path string, shared []string) bool {
+	if shared == nil {
+		return true
+	}
+	for _, root := range shared {
+		if root == "" {
+			continue
+		}
+		rel, err := filepath.Rel(root, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
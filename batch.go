@@ -0,0 +1,328 @@
+package pythainlp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Input formats for ProcessFile
+const (
+	FormatLines = "lines" // One text per line
+	FormatJSONL = "jsonl" // One JSON object per line, the text in ProcessOptions.TextField
+)
+
+const (
+	defaultBatchConcurrency = 4
+	checkpointEvery         = 100
+)
+
+// ProcessOptions configures ProcessFile
+type ProcessOptions struct {
+	Analyze     AnalyzeOptions // Features and engines applied to every record
+	Format      string         // FormatLines (default) or FormatJSONL
+	TextField   string         // JSONL field holding the text (default "text")
+	Concurrency int            // Records analyzed at once (default 4)
+
+	// Checkpoint is the progress file used to resume an interrupted run
+	// (default: the output path with ".checkpoint" appended). It is removed
+	// once the whole input has been processed.
+	Checkpoint string
+
+	// Progress, if set, is called with the number of records written so far
+	Progress func(records int)
+}
+
+// ProcessStats summarizes a ProcessFile run
+type ProcessStats struct {
+	Records int // Records written by this run
+	Failed  int // Records written with an error instead of an analysis
+	Resumed int // Records skipped because a previous run already wrote them
+}
+
+// batchRecord is one input record on its way through the workers
+type batchRecord struct {
+	index  int
+	fields map[string]json.RawMessage // JSONL only
+	text   string
+	result *AnalyzeResult
+	err    error
+}
+
+// checkpoint is the progress file format
+type checkpoint struct {
+	Records int   `json:"records"` // Input records fully written
+	Offset  int64 `json:"offset"`  // Output size after those records
+}
+
+// ProcessFile analyzes every record of the input file and writes one JSON
+// object per record to the output file, in input order. Records are analyzed
+// concurrently; a record the service rejects is written with an "error"
+// field and does not stop the run.
+//
+// Progress is checkpointed regularly. If the run is interrupted (ctx
+// canceled, crash, service failure), calling ProcessFile again with the same
+// arguments truncates the output to the last checkpoint and resumes from
+// there, so multi-hour jobs do not start over.
+func (pm *PyThaiNLPManager) ProcessFile(ctx context.Context, in, out string, opts ProcessOptions) (*ProcessStats, error) {
+	if opts.Format == "" {
+		opts.Format = FormatLines
+	}
+	if opts.Format != FormatLines && opts.Format != FormatJSONL {
+		return nil, fmt.Errorf("unknown input format %q", opts.Format)
+	}
+	if opts.TextField == "" {
+		opts.TextField = "text"
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultBatchConcurrency
+	}
+	if opts.Checkpoint == "" {
+		opts.Checkpoint = out + ".checkpoint"
+	}
+
+	start, err := readCheckpoint(opts.Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	inFile, err := os.Open(in)
+	if err != nil {
+		return nil, err
+	}
+	defer inFile.Close()
+
+	outFile, err := os.OpenFile(out, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer outFile.Close()
+	if err := outFile.Truncate(start.Offset); err != nil {
+		return nil, fmt.Errorf("failed to truncate output to checkpoint: %w", err)
+	}
+	if _, err := outFile.Seek(start.Offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if start.Records > 0 {
+		Logger.Info().Int("records", start.Records).Str("output", out).Msg("Resuming from checkpoint")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// window bounds the records read ahead of the writer, so that a slow
+	// record does not let the others pile up in memory
+	window := make(chan struct{}, opts.Concurrency*4)
+	jobs := make(chan *batchRecord, opts.Concurrency)
+	results := make(chan *batchRecord, opts.Concurrency)
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(jobs)
+		readErr <- readRecords(ctx, inFile, start.Records, opts, window, jobs)
+	}()
+
+	var wg sync.WaitGroup
+	for range opts.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rec := range jobs {
+				if rec.err == nil && rec.text != "" {
+					rec.result, rec.err = pm.AnalyzeWithOptions(ctx, rec.text, opts.Analyze)
+				}
+				results <- rec
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	stats := &ProcessStats{Resumed: start.Records}
+	w := &countingWriter{w: bufio.NewWriter(outFile), n: start.Offset}
+	pending := make(map[int]*batchRecord)
+	next := start.Records
+	var runErr error
+
+	save := func() error {
+		if err := w.w.Flush(); err != nil {
+			return err
+		}
+		return writeCheckpoint(opts.Checkpoint, checkpoint{Records: next, Offset: w.n})
+	}
+
+	for rec := range results {
+		if runErr != nil {
+			continue // drain so the workers can exit
+		}
+		pending[rec.index] = rec
+		for pending[next] != nil {
+			rec := pending[next]
+			delete(pending, next)
+			if isFatalBatchError(rec.err) {
+				runErr = rec.err
+				cancel()
+				break
+			}
+			if err := writeRecord(w, rec, opts); err != nil {
+				runErr = err
+				cancel()
+				break
+			}
+			<-window
+			next++
+			stats.Records++
+			if rec.err != nil {
+				stats.Failed++
+			}
+			if opts.Progress != nil {
+				opts.Progress(next)
+			}
+			if next%checkpointEvery == 0 {
+				if err := save(); err != nil {
+					runErr = fmt.Errorf("failed to save checkpoint: %w", err)
+					cancel()
+					break
+				}
+			}
+		}
+	}
+
+	if err := save(); err != nil && runErr == nil {
+		runErr = fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if err := <-readErr; err != nil && runErr == nil {
+		runErr = err
+	}
+	if runErr == nil {
+		runErr = ctx.Err()
+	}
+	if runErr != nil {
+		return stats, fmt.Errorf("processing stopped after %d records, run again to resume: %w", next, runErr)
+	}
+
+	if err := os.Remove(opts.Checkpoint); err != nil && !os.IsNotExist(err) {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// readRecords sends the records after the first skip ones to jobs
+func readRecords(ctx context.Context, r io.Reader, skip int, opts ProcessOptions, window chan struct{}, jobs chan<- *batchRecord) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for index := 0; scanner.Scan(); index++ {
+		if index < skip {
+			continue
+		}
+		rec := &batchRecord{index: index}
+		if opts.Format == FormatJSONL {
+			if err := json.Unmarshal(scanner.Bytes(), &rec.fields); err != nil {
+				rec.err = fmt.Errorf("invalid JSON: %w", err)
+			} else if raw, ok := rec.fields[opts.TextField]; ok {
+				if err := json.Unmarshal(raw, &rec.text); err != nil {
+					rec.err = fmt.Errorf("field %q is not a string", opts.TextField)
+				}
+			}
+		} else {
+			rec.text = scanner.Text()
+		}
+
+		select {
+		case window <- struct{}{}:
+		case <-ctx.Done():
+			return nil
+		}
+		select {
+		case jobs <- rec:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// writeRecord writes the output line of rec. JSONL records keep their fields
+// and gain "analysis" or "error"; lines become {"line", "text", ...}.
+func writeRecord(w io.Writer, rec *batchRecord, opts ProcessOptions) error {
+	fields := rec.fields
+	if opts.Format == FormatLines {
+		fields = map[string]json.RawMessage{}
+		fields["line"], _ = json.Marshal(rec.index + 1)
+		fields["text"], _ = json.Marshal(rec.text)
+	} else if fields == nil {
+		fields = map[string]json.RawMessage{}
+	}
+
+	var err error
+	if rec.err != nil {
+		fields["error"], err = json.Marshal(rec.err.Error())
+	} else if rec.result != nil {
+		fields["analysis"], err = json.Marshal(rec.result)
+	}
+	if err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(line, '\n'))
+	return err
+}
+
+// isFatalBatchError reports whether err means the service is gone, in which
+// case every following record would fail too
+func isFatalBatchError(err error) bool {
+	return errors.Is(err, ErrServiceNotReady) || errors.Is(err, ErrContainerCrashed) ||
+		errors.Is(err, context.Canceled)
+}
+
+func readCheckpoint(path string) (checkpoint, error) {
+	var cp checkpoint
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cp, nil
+	}
+	if err != nil {
+		return cp, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return cp, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	return cp, nil
+}
+
+// writeCheckpoint replaces the checkpoint atomically, so that a crash while
+// writing it leaves the previous one
+func writeCheckpoint(path string, cp checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// countingWriter tracks the output size for checkpoints
+type countingWriter struct {
+	w *bufio.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
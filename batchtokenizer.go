@@ -0,0 +1,120 @@
+package pythainlp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	batchWindow    = 2 * time.Millisecond
+	maxBatchTexts  = 64
+	batchCacheSize = 4096
+)
+
+// BatchTokenizer tokenizes texts for callers that handle one text at a time,
+// such as search engine analyzers. Calls made concurrently are coalesced into
+// a single TokenizeBatch request, and results are cached, since indexed
+// fields often repeat (titles, tags, categories).
+type BatchTokenizer struct {
+	pm   *PyThaiNLPManager
+	opts TokenizeOptions
+
+	mu      sync.Mutex
+	pending []*batchCall
+	timer   *time.Timer
+	cache   map[string][]string
+	order   []string // cache keys, oldest first
+}
+
+type batchCall struct {
+	text   string
+	tokens []string
+	err    error
+	done   chan struct{}
+}
+
+// NewBatchTokenizer returns a BatchTokenizer using opts, which caches the
+// tokens of the last 4096 distinct texts
+func (pm *PyThaiNLPManager) NewBatchTokenizer(opts TokenizeOptions) *BatchTokenizer {
+	return &BatchTokenizer{pm: pm, opts: opts, cache: make(map[string][]string)}
+}
+
+// Tokenize returns the tokens of text. The returned slice is shared with the
+// cache and must not be modified.
+func (b *BatchTokenizer) Tokenize(ctx context.Context, text string) ([]string, error) {
+	b.mu.Lock()
+	if tokens, ok := b.cache[text]; ok {
+		b.mu.Unlock()
+		return tokens, nil
+	}
+	call := &batchCall{text: text, done: make(chan struct{})}
+	b.pending = append(b.pending, call)
+	switch {
+	case len(b.pending) >= maxBatchTexts:
+		b.flushLocked(ctx)
+	case len(b.pending) == 1:
+		b.timer = time.AfterFunc(batchWindow, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.flushLocked(ctx)
+		})
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.tokens, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// flushLocked sends the pending texts in the background. The request
+// outlives the caller whose context is used, which may stop waiting.
+func (b *BatchTokenizer) flushLocked(ctx context.Context) {
+	calls := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(calls) == 0 {
+		return
+	}
+	go b.send(context.WithoutCancel(ctx), calls)
+}
+
+func (b *BatchTokenizer) send(ctx context.Context, calls []*batchCall) {
+	texts := make([]string, len(calls))
+	for i, call := range calls {
+		texts[i] = call.text
+	}
+	tokens, err := b.pm.TokenizeBatch(ctx, texts, b.opts)
+
+	b.mu.Lock()
+	for i, call := range calls {
+		if err != nil {
+			call.err = err
+			continue
+		}
+		call.tokens = tokens[i]
+		b.addLocked(call.text, tokens[i])
+	}
+	b.mu.Unlock()
+	for _, call := range calls {
+		close(call.done)
+	}
+}
+
+func (b *BatchTokenizer) addLocked(text string, tokens []string) {
+	if _, ok := b.cache[text]; ok {
+		return
+	}
+	if len(b.order) >= batchCacheSize {
+		delete(b.cache, b.order[0])
+		b.order = b.order[1:]
+	}
+	b.cache[text] = tokens
+	b.order = append(b.order, text)
+}
@@ -0,0 +1,334 @@
+// Package bench measures the latency and throughput of PyThaiNLP engines
+// against a running service, across payload sizes and concurrency levels, to
+// compare engines on the hardware they will run on.
+//
+//	results, err := bench.Run(ctx, manager, bench.Config{
+//		Operations:  []string{bench.Tokenize},
+//		Concurrency: []int{1, 8},
+//	})
+//	err = bench.WriteCSV(os.Stdout, results)
+//
+// The same text is sent repeatedly, so the manager must not have a shared
+// result cache (WithSharedCache) or request coalescing enabled, which would
+// measure the Go side instead of the engine.
+package bench
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp"
+)
+
+// Operations that can be measured
+const (
+	Tokenize      = "tokenize"
+	Romanize      = "romanize"
+	Transliterate = "transliterate"
+	Syllable      = "syllable"
+)
+
+// sampleText is repeated and cut to build payloads of the requested sizes
+const sampleText = "ภาษาไทยเป็นภาษาที่มีระดับเสียงของคำแน่นอนหรือวรรณยุกต์เช่นเดียวกับภาษาจีน " +
+	"และออกเสียงแยกคำต่อคำ ประเทศไทยมีประชากรประมาณเจ็ดสิบล้านคน " +
+	"กรุงเทพมหานครเป็นเมืองหลวงและเป็นศูนย์กลางทางเศรษฐกิจของประเทศ "
+
+// Config selects what to measure. Every engine of every operation is
+// measured at every payload size and concurrency level.
+type Config struct {
+	Operations []string // Default: tokenize and romanize
+
+	// Engines lists the engines to measure per operation. Operations missing
+	// from it get every engine available in the container.
+	Engines map[string][]string
+
+	PayloadSizes []int // In characters; default 16, 256 and 4096
+	Concurrency  []int // Requests in flight; default 1, 4 and 16
+	Requests     int   // Measured requests per combination, default 100
+
+	// Warmup requests are sent before measuring each engine so that loading
+	// its model is not counted (default 3, negative for none)
+	Warmup int
+
+	// Text is the sample the payloads are cut from, repeated as needed
+	// (default: a Thai paragraph)
+	Text string
+
+	// OnResult, if set, is called with each result as soon as it is measured
+	OnResult func(Result)
+}
+
+// Result is the measurement of one engine at one payload size and
+// concurrency level
+type Result struct {
+	Operation   string        `json:"operation"`
+	Engine      string        `json:"engine"`
+	PayloadSize int           `json:"payload_size"` // Characters per request
+	Concurrency int           `json:"concurrency"`
+	Requests    int           `json:"requests"`
+	Errors      int           `json:"errors"`
+	Error       string        `json:"error,omitempty"` // The first error, if any
+	Mean        time.Duration `json:"mean"`
+	P50         time.Duration `json:"p50"`
+	P95         time.Duration `json:"p95"`
+	P99         time.Duration `json:"p99"`
+	Max         time.Duration `json:"max"`
+	Throughput  float64       `json:"throughput"`       // Successful requests per second
+	CharsPerSec float64       `json:"chars_per_second"` // Characters processed per second
+}
+
+func (c *Config) defaults() {
+	if len(c.Operations) == 0 {
+		c.Operations = []string{Tokenize, Romanize}
+	}
+	if len(c.PayloadSizes) == 0 {
+		c.PayloadSizes = []int{16, 256, 4096}
+	}
+	if len(c.Concurrency) == 0 {
+		c.Concurrency = []int{1, 4, 16}
+	}
+	if c.Requests <= 0 {
+		c.Requests = 100
+	}
+	if c.Warmup < 0 {
+		c.Warmup = 0
+	} else if c.Warmup == 0 {
+		c.Warmup = 3
+	}
+	if c.Text == "" {
+		c.Text = sampleText
+	}
+}
+
+// Run measures the engines selected by cfg with the manager's service, which
+// must be ready. It returns the results measured so far with the error when
+// ctx is done.
+func Run(ctx context.Context, manager *pythainlp.PyThaiNLPManager, cfg Config) ([]Result, error) {
+	cfg.defaults()
+
+	var caps *pythainlp.Capabilities
+	var results []Result
+	for _, op := range cfg.Operations {
+		engines, ok := cfg.Engines[op]
+		if !ok {
+			if caps == nil {
+				var err error
+				if caps, err = manager.Capabilities(ctx); err != nil {
+					return nil, fmt.Errorf("failed to list engines: %w", err)
+				}
+			}
+			engines = available(caps, op)
+		}
+
+		for _, engine := range engines {
+			call, err := caller(manager, op, engine)
+			if err != nil {
+				return results, err
+			}
+			warm := payload(cfg.Text, cfg.PayloadSizes[0])
+			for range cfg.Warmup {
+				call(ctx, warm)
+			}
+
+			for _, size := range cfg.PayloadSizes {
+				text := payload(cfg.Text, size)
+				for _, concurrency := range cfg.Concurrency {
+					if err := ctx.Err(); err != nil {
+						return results, err
+					}
+					result := measure(ctx, call, text, cfg.Requests, max(concurrency, 1))
+					result.Operation = op
+					result.Engine = engine
+					result.PayloadSize = size
+					results = append(results, result)
+					if cfg.OnResult != nil {
+						cfg.OnResult(result)
+					}
+				}
+			}
+		}
+	}
+	return results, nil
+}
+
+// available returns the engines of op the container can import
+func available(caps *pythainlp.Capabilities, op string) []string {
+	var engines []string
+	switch op {
+	case Tokenize:
+		for _, e := range caps.Tokenize {
+			engines = append(engines, string(e))
+		}
+	case Romanize:
+		for _, e := range caps.Romanize {
+			engines = append(engines, string(e))
+		}
+	case Transliterate:
+		for _, e := range caps.Transliterate {
+			engines = append(engines, string(e))
+		}
+	case Syllable:
+		for _, e := range caps.Syllable {
+			engines = append(engines, string(e))
+		}
+	}
+	return engines
+}
+
+// caller returns a function sending text to engine of op
+func caller(manager *pythainlp.PyThaiNLPManager, op, engine string) (func(ctx context.Context, text string) error, error) {
+	switch op {
+	case Tokenize:
+		return func(ctx context.Context, text string) error {
+			_, err := manager.TokenizeWithEngine(ctx, text, pythainlp.TokenizeEngine(engine))
+			return err
+		}, nil
+	case Romanize:
+		return func(ctx context.Context, text string) error {
+			_, err := manager.RomanizeWithEngine(ctx, text, pythainlp.RomanizeEngine(engine))
+			return err
+		}, nil
+	case Transliterate:
+		return func(ctx context.Context, text string) error {
+			_, err := manager.TransliterateWithEngine(ctx, text, pythainlp.TransliterateEngine(engine))
+			return err
+		}, nil
+	case Syllable:
+		return func(ctx context.Context, text string) error {
+			_, err := manager.SyllableTokenizeWithEngine(ctx, text, pythainlp.SyllableEngine(engine))
+			return err
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q, expected one of %s, %s, %s or %s", op, Tokenize, Romanize, Transliterate, Syllable)
+}
+
+// payload returns the first size characters of text repeated
+func payload(text string, size int) string {
+	runes := []rune(text)
+	out := make([]rune, 0, size)
+	for len(out) < size {
+		out = append(out, runes[:min(len(runes), size-len(out))]...)
+	}
+	return string(out)
+}
+
+// measure sends requests calls of text, concurrency at a time
+func measure(ctx context.Context, call func(ctx context.Context, text string) error, text string, requests, concurrency int) Result {
+	var (
+		mu        sync.Mutex
+		latencies = make([]time.Duration, 0, requests)
+		errs      int
+		firstErr  error
+	)
+	jobs := make(chan struct{})
+	var wg sync.WaitGroup
+	start := time.Now()
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				began := time.Now()
+				err := call(ctx, text)
+				elapsed := time.Since(began)
+				mu.Lock()
+				if err != nil {
+					errs++
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else {
+					latencies = append(latencies, elapsed)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for range requests {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- struct{}{}
+	}
+	close(jobs)
+	wg.Wait()
+	wall := time.Since(start)
+
+	result := Result{Concurrency: concurrency, Requests: len(latencies) + errs, Errors: errs}
+	if firstErr != nil {
+		result.Error = firstErr.Error()
+	}
+	if len(latencies) == 0 {
+		return result
+	}
+	slices.Sort(latencies)
+	var total time.Duration
+	for _, l := range latencies {
+		total += l
+	}
+	result.Mean = total / time.Duration(len(latencies))
+	result.P50 = percentile(latencies, 0.50)
+	result.P95 = percentile(latencies, 0.95)
+	result.P99 = percentile(latencies, 0.99)
+	result.Max = latencies[len(latencies)-1]
+	result.Throughput = float64(len(latencies)) / wall.Seconds()
+	result.CharsPerSec = result.Throughput * float64(len([]rune(text)))
+	return result
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(i, 0)]
+}
+
+// WriteJSON writes the results as an indented JSON array, with durations in
+// nanoseconds
+func WriteJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+// csvHeader names the columns of WriteCSV
+var csvHeader = []string{
+	"operation", "engine", "payload_size", "concurrency", "requests", "errors",
+	"mean_ms", "p50_ms", "p95_ms", "p99_ms", "max_ms", "throughput", "chars_per_second", "error",
+}
+
+// WriteCSV writes the results as CSV with a header row, with durations in
+// milliseconds
+func WriteCSV(w io.Writer, results []Result) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	ms := func(d time.Duration) string {
+		return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+	}
+	for _, r := range results {
+		record := []string{
+			r.Operation, r.Engine,
+			strconv.Itoa(r.PayloadSize), strconv.Itoa(r.Concurrency),
+			strconv.Itoa(r.Requests), strconv.Itoa(r.Errors),
+			ms(r.Mean), ms(r.P50), ms(r.P95), ms(r.P99), ms(r.Max),
+			strconv.FormatFloat(r.Throughput, 'f', 2, 64),
+			strconv.FormatFloat(r.CharsPerSec, 'f', 0, 64),
+			strings.ReplaceAll(r.Error, "\n", " "),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,104 @@
+// Package bleveanalyzer plugs PyThaiNLP word segmentation into Bleve, so
+// that Thai text is indexed by word rather than as one term per run of
+// letters.
+//
+//	manager, _ := pythainlp.NewManager(ctx)
+//	bleveanalyzer.Register(manager, pythainlp.NewTokenizeOptions())
+//
+//	indexMapping := bleve.NewIndexMapping()
+//	indexMapping.DefaultAnalyzer = bleveanalyzer.Name
+//
+// It is a separate module so that the main package does not depend on Bleve.
+package bleveanalyzer
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/registry"
+
+	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp"
+)
+
+// Name is the name of both the tokenizer and the analyzer in Bleve's registry
+const Name = "pythainlp"
+
+// Tokenizer is a Bleve tokenizer backed by a pythainlp.BatchTokenizer, so
+// that documents analyzed concurrently during batch indexing share requests
+// and repeated field values are tokenized once
+type Tokenizer struct {
+	tokenizer *pythainlp.BatchTokenizer
+}
+
+var _ analysis.Tokenizer = (*Tokenizer)(nil)
+
+// NewTokenizer returns a tokenizer segmenting text with manager
+func NewTokenizer(manager *pythainlp.PyThaiNLPManager, opts pythainlp.TokenizeOptions) *Tokenizer {
+	return &Tokenizer{tokenizer: manager.NewBatchTokenizer(opts)}
+}
+
+// Tokenize implements analysis.Tokenizer. Bleve tokenizers cannot fail, so
+// if the service does, the error is logged and the text yields no terms.
+func (t *Tokenizer) Tokenize(input []byte) analysis.TokenStream {
+	words, err := t.tokenizer.Tokenize(context.Background(), string(input))
+	if err != nil {
+		pythainlp.Logger.Error().Err(err).Msg("Bleve tokenization failed, indexing no terms")
+		return nil
+	}
+
+	stream := make(analysis.TokenStream, 0, len(words))
+	text := string(input)
+	pos := 0
+	for _, word := range words {
+		if strings.TrimSpace(word) == "" {
+			continue
+		}
+		i := strings.Index(text[pos:], word)
+		if i < 0 {
+			// The engine changed the text; the remaining offsets are unknown
+			break
+		}
+		start := pos + i
+		pos = start + len(word)
+		stream = append(stream, &analysis.Token{
+			Start:    start,
+			End:      pos,
+			Term:     input[start:pos],
+			Position: len(stream) + 1,
+			Type:     tokenType(word),
+		})
+	}
+	return stream
+}
+
+func tokenType(word string) analysis.TokenType {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return analysis.AlphaNumeric
+		}
+	}
+	return analysis.Numeric
+}
+
+// Register adds the tokenizer and an analyzer to Bleve's registry under Name.
+// The analyzer lowercases the tokens, for mixed Thai and English text. Call
+// it once, before opening or creating an index that uses them.
+func Register(manager *pythainlp.PyThaiNLPManager, opts pythainlp.TokenizeOptions) {
+	tokenizer := NewTokenizer(manager, opts)
+	registry.RegisterTokenizer(Name, func(map[string]interface{}, *registry.Cache) (analysis.Tokenizer, error) {
+		return tokenizer, nil
+	})
+	registry.RegisterAnalyzer(Name, func(_ map[string]interface{}, cache *registry.Cache) (analysis.Analyzer, error) {
+		toLower, err := cache.TokenFilterNamed(lowercase.Name)
+		if err != nil {
+			return nil, err
+		}
+		return &analysis.DefaultAnalyzer{
+			Tokenizer:    tokenizer,
+			TokenFilters: []analysis.TokenFilter{toLower},
+		}, nil
+	})
+}
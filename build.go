@@ -0,0 +1,155 @@
+package pythainlp
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/build"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+const (
+	// Local tags for images built from source, one per dependency set
+	localImageLight = "go-pythainlp-local:light"
+	localImageFull  = "go-pythainlp-local:full"
+)
+
+//go:embed Dockerfile
+var dockerfile []byte
+
+// buildStepRe matches the step counter of the classic builder output
+var buildStepRe = regexp.MustCompile(`^Step (\d+)/(\d+)`)
+
+// WithBuildFromSource builds the image locally from the embedded Dockerfile,
+// service files and requirements instead of pulling it from GHCR. Use it when
+// the registry is unreachable or no image is published for the host architecture.
+// Build output is streamed to the download progress callback.
+func WithBuildFromSource() ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.buildFromSource = true
+	}
+}
+
+// localImage returns the tag of the locally built image for the current mode
+func (pm *PyThaiNLPManager) localImage() string {
+	if pm.lightweightMode {
+		return localImageLight
+	}
+	return localImageFull
+}
+
+// BuildImage builds the service image from source, skipping the build when the
+// image already exists unless noCache is set
+func (pm *PyThaiNLPManager) BuildImage(ctx context.Context, noCache bool) error {
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to get Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	tag := pm.localImage()
+	if !noCache {
+		if _, err := dockerClient.ImageInspect(ctx, tag); err == nil {
+			Logger.Debug().Str("image", tag).Msg("Image already built, skipping build")
+			return nil
+		}
+	}
+
+	buildContext, err := pm.buildContext()
+	if err != nil {
+		return fmt.Errorf("failed to create build context: %w", err)
+	}
+
+	// Proxy variables are predefined build args, no ARG needed in the Dockerfile
+	buildArgs := make(map[string]*string)
+	for k, v := range pm.proxy.env() {
+		buildArgs[k] = ptr(v)
+	}
+
+	Logger.Info().Str("image", tag).Bool("lightweight", pm.lightweightMode).Msg("Building image from source")
+
+	resp, err := dockerClient.ImageBuild(ctx, buildContext, build.ImageBuildOptions{
+		Tags:        []string{tag},
+		Dockerfile:  "Dockerfile",
+		NoCache:     noCache,
+		Remove:      true,
+		ForceRemove: true,
+		PullParent:  noCache,
+		BuildArgs:   buildArgs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start image build: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var step, steps int64
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return fmt.Errorf("failed to decode build output: %w", err)
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("image build failed: %s", msg.Error.Message)
+		}
+
+		line := strings.TrimSpace(msg.Stream)
+		if line == "" {
+			continue
+		}
+		if m := buildStepRe.FindStringSubmatch(line); m != nil {
+			step, _ = strconv.ParseInt(m[1], 10, 64)
+			steps, _ = strconv.ParseInt(m[2], 10, 64)
+		}
+		Logger.Debug().Str("output", line).Msg("Image build")
+		pm.startupProgress(StartupBuild, line, step, steps)
+		if pm.downloadProgressCallback != nil {
+			pm.downloadProgressCallback(step, steps, line)
+		}
+	}
+
+	if _, err := dockerClient.ImageInspect(ctx, tag); err != nil {
+		return fmt.Errorf("image verification failed after build: %w", err)
+	}
+
+	Logger.Info().Str("image", tag).Msg("Image build complete")
+	return nil
+}
+
+// buildContext creates the tar archive sent to the daemon: the Dockerfile,
+// the requirements file for the current mode and the service directory
+func (pm *PyThaiNLPManager) buildContext() (io.Reader, error) {
+	requirements := lightRequirements
+	if !pm.lightweightMode {
+		requirements = fullRequirements
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	if err := addTarFile(tw, "Dockerfile", dockerfile, 0644); err != nil {
+		return nil, err
+	}
+	if err := addTarFile(tw, "docker_requirements.txt", requirements, 0644); err != nil {
+		return nil, err
+	}
+	if err := addServiceTree(tw); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
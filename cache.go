@@ -0,0 +1,123 @@
+package pythainlp
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+)
+
+// ResultCache holds the results of recent calls, keyed by operation, options
+// and text. Give the same cache to several managers with WithSharedCache so
+// that a text one of them has processed is not sent to the service again by
+// the others. It is safe for concurrent use.
+type ResultCache struct {
+	mu    sync.Mutex
+	size  int
+	items map[string]*list.Element
+	order *list.List // most recently used first
+}
+
+type cacheEntry struct {
+	key   string
+	value any
+}
+
+// NewResultCache returns a cache keeping the results of the last size
+// distinct calls (at least one)
+func NewResultCache(size int) *ResultCache {
+	return &ResultCache{
+		size:  max(size, 1),
+		items: make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+// Len returns the number of cached results
+func (c *ResultCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Purge removes all cached results
+func (c *ResultCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.items)
+	c.order.Init()
+}
+
+func (c *ResultCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).value, true
+}
+
+func (c *ResultCache) add(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*cacheEntry).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.order.PushFront(&cacheEntry{key: key, value: value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// WithSharedCache makes the manager look up the results of Tokenize,
+// Romanize, Transliterate, SyllableTokenize and AnalyzeText calls in cache
+// before calling the service, and store them there. Cached results are
+// shared between callers and must not be modified. Managers sharing a cache
+// should run the same PyThaiNLP version. Calls are not cached while an engine
+// fallback policy is set, since their result then depends on what is loaded,
+// nor are AnalyzeText calls with a NonThai handler.
+func WithSharedCache(cache *ResultCache) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.cache = cache
+	}
+}
+
+// cached returns the cached result of operation on text with opts, or calls
+// call and caches its result. Options that cannot be encoded as a key bypass
+// the cache.
+func cached[R any](pm *PyThaiNLPManager, operation, text string, opts any, call func() (R, error)) (R, error) {
+	if pm.cache == nil || pm.fallback != nil {
+		return call()
+	}
+	key, err := cacheKey(operation, text, opts)
+	if err != nil {
+		Logger.Debug().Err(err).Str("operation", operation).Msg("Options cannot be cached")
+		return call()
+	}
+
+	if value, ok := pm.cache.get(key); ok {
+		if result, ok := value.(R); ok {
+			return result, nil
+		}
+	}
+	result, err := call()
+	if err != nil {
+		return result, err
+	}
+	pm.cache.add(key, result)
+	return result, nil
+}
+
+// cacheKey returns the key of the result of operation on text with opts
+func cacheKey(operation, text string, opts any) (string, error) {
+	encoded, err := json.Marshal(opts)
+	if err != nil {
+		return "", err
+	}
+	return operation + "\x00" + string(encoded) + "\x00" + text, nil
+}
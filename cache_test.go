@@ -0,0 +1,52 @@
+package pythainlp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCacheKey(t *testing.T) {
+	pm := &PyThaiNLPManager{overrides: map[string]string{"กรุงเทพ": "Krung Thep"}}
+	nonThai := func(ctx context.Context, segment string) ([]Token, error) { return nil, nil }
+
+	// The options of every cached call, each with a variant that must not
+	// share its key
+	cases := []struct {
+		operation     string
+		opts, variant any
+	}{
+		{"tokenize", NewTokenizeOptions(), NewTokenizeOptions(WithKeepWhitespace(false))},
+		{"tokenize", TokenizeOptions{Engine: EngineNewMM}, TokenizeOptions{Engine: EngineNewMM, Granularity: GranularityFine}},
+		{"romanize", pm.romanizeCacheKey(RomanizeOptions{}), pm.romanizeCacheKey(RomanizeOptions{Overrides: map[string]string{"ไทย": "Thai"}})},
+		{"transliterate", TransliterateOptions{}, TransliterateOptions{Align: true}},
+		{"syllable_tokenize", SyllableTokenizeOptions{}, SyllableTokenizeOptions{KeepWhitespace: true}},
+		{"analyze", NewAnalyzeOptions(), NewAnalyzeOptions(WithFeatures("tokenize"))},
+		{"analyze", AnalyzeOptions{ThaiOnly: true, NonThai: nonThai}, AnalyzeOptions{ThaiOnly: true, Features: []string{"lemma"}, NonThai: nonThai}},
+	}
+	for _, c := range cases {
+		key, err := cacheKey(c.operation, "ภาษาไทย", c.opts)
+		if err != nil {
+			t.Errorf("%s %+v: %v", c.operation, c.opts, err)
+			continue
+		}
+		again, _ := cacheKey(c.operation, "ภาษาไทย", c.opts)
+		if again != key {
+			t.Errorf("%s: key is not stable: %q, %q", c.operation, key, again)
+		}
+		variant, err := cacheKey(c.operation, "ภาษาไทย", c.variant)
+		if err != nil {
+			t.Errorf("%s %+v: %v", c.operation, c.variant, err)
+		} else if variant == key {
+			t.Errorf("%s: %+v and %+v share the key %q", c.operation, c.opts, c.variant, key)
+		}
+		if other, _ := cacheKey(c.operation, "ภาษา", c.opts); other == key {
+			t.Errorf("%s: texts share the key %q", c.operation, key)
+		}
+	}
+
+	tokenize, _ := cacheKey("tokenize", "ภาษาไทย", TokenizeOptions{})
+	syllable, _ := cacheKey("syllable_tokenize", "ภาษาไทย", TokenizeOptions{})
+	if tokenize == syllable {
+		t.Errorf("operations share the key %q", tokenize)
+	}
+}
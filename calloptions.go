@@ -0,0 +1,257 @@
+package pythainlp
+
+// CallOption configures a single call to Tokenize, Romanize, Transliterate,
+// SyllableTokenize or AnalyzeText:
+//
+//	result, err := manager.Tokenize(ctx, text, pythainlp.WithEngine(pythainlp.EngineLongest), pythainlp.WithKeepWhitespace(false))
+//
+// An option that does not apply to a call, such as a romanization engine
+// given to Tokenize, is ignored.
+type CallOption func(*callTarget)
+
+// callTarget holds the options of the call being configured; only one of
+// its fields is set
+type callTarget struct {
+	tokenize      *TokenizeOptions
+	romanize      *RomanizeOptions
+	transliterate *TransliterateOptions
+	syllable      *SyllableTokenizeOptions
+	analyze       *AnalyzeOptions
+}
+
+// Engine is any of the engine types
+type Engine interface {
+	TokenizeEngine | RomanizeEngine | TransliterateEngine | SyllableEngine | LemmaEngine
+}
+
+// WithEngine selects the engine of the calls of its type: a TokenizeEngine
+// for Tokenize, a RomanizeEngine for Romanize, and so on. AnalyzeText takes
+// one of each.
+func WithEngine[E Engine](engine E) CallOption {
+	return func(t *callTarget) {
+		switch e := any(engine).(type) {
+		case TokenizeEngine:
+			if t.tokenize != nil {
+				t.tokenize.Engine = e
+			}
+			if t.analyze != nil {
+				t.analyze.TokenizeEngine = e
+			}
+		case RomanizeEngine:
+			if t.romanize != nil {
+				t.romanize.Engine = e
+			}
+			if t.analyze != nil {
+				t.analyze.RomanizeEngine = e
+			}
+		case TransliterateEngine:
+			if t.transliterate != nil {
+				t.transliterate.Engine = e
+			}
+			if t.analyze != nil {
+				t.analyze.TransliterateEngine = e
+			}
+		case SyllableEngine:
+			if t.syllable != nil {
+				t.syllable.Engine = e
+			}
+			if t.analyze != nil {
+				t.analyze.SyllableEngine = e
+			}
+		case LemmaEngine:
+			if t.analyze != nil {
+				t.analyze.LemmaEngine = e
+			}
+		}
+	}
+}
+
+// WithKeepWhitespace keeps whitespace tokens (Tokenize, SyllableTokenize)
+func WithKeepWhitespace(keep bool) CallOption {
+	return func(t *callTarget) {
+		if t.tokenize != nil {
+			t.tokenize.DropWhitespace = !keep
+		}
+		if t.syllable != nil {
+			t.syllable.KeepWhitespace = keep
+		}
+	}
+}
+
+// WithCustomDict adds dictionary entries for the call (Tokenize)
+func WithCustomDict(words ...string) CallOption {
+	return func(t *callTarget) {
+		if t.tokenize != nil {
+			t.tokenize.CustomDict = append(t.tokenize.CustomDict, words...)
+		}
+	}
+}
+
+// WithGranularity merges or splits compounds after tokenization, see
+// Granularity (Tokenize, AnalyzeText)
+func WithGranularity(g Granularity) CallOption {
+	return func(t *callTarget) {
+		if t.tokenize != nil {
+			t.tokenize.Granularity = g
+		}
+		if t.analyze != nil {
+			t.analyze.Granularity = g
+		}
+	}
+}
+
+// WithJoinBrokenNum joins numbers split by the tokenizer (Tokenize)
+func WithJoinBrokenNum(join bool) CallOption {
+	return func(t *callTarget) {
+		if t.tokenize != nil {
+			t.tokenize.SplitBrokenNum = !join
+		}
+	}
+}
+
+// WithProtect keeps every match of the patterns a single token, see
+// TokenizeOptions.Protect (Tokenize)
+func WithProtect(patterns ...string) CallOption {
+	return func(t *callTarget) {
+		if t.tokenize != nil {
+			t.tokenize.Protect = append(t.tokenize.Protect, patterns...)
+		}
+	}
+}
+
+// WithTokenizeFirst tokenizes before romanizing (Romanize)
+func WithTokenizeFirst(tokenize bool) CallOption {
+	return func(t *callTarget) {
+		if t.romanize != nil {
+			t.romanize.TokenizeFirst = tokenize
+		}
+	}
+}
+
+// WithOverrides sets romanizations of words that take precedence over the
+// engine, see RomanizeOptions.Overrides (Romanize)
+func WithOverrides(overrides map[string]string) CallOption {
+	return func(t *callTarget) {
+		if t.romanize != nil {
+			t.romanize.Overrides = overrides
+		}
+	}
+}
+
+// WithProperNouns romanizes person and place names as such, see
+// RomanizeOptions.ProperNouns (Romanize)
+func WithProperNouns(properNouns bool) CallOption {
+	return func(t *callTarget) {
+		if t.romanize != nil {
+			t.romanize.ProperNouns = properNouns
+		}
+	}
+}
+
+// WithConsistent romanizes every occurrence of a word the same way, see
+// RomanizeOptions.Consistent (Romanize)
+func WithConsistent(consistent bool) CallOption {
+	return func(t *callTarget) {
+		if t.romanize != nil {
+			t.romanize.Consistent = consistent
+		}
+	}
+}
+
+// WithAlign fills the Alignment of the result (Romanize, Transliterate)
+func WithAlign(align bool) CallOption {
+	return func(t *callTarget) {
+		if t.romanize != nil {
+			t.romanize.Align = align
+		}
+		if t.transliterate != nil {
+			t.transliterate.Align = align
+		}
+	}
+}
+
+// WithFeatures sets the features to extract, replacing the default tokenize
+// and romanize (AnalyzeText)
+func WithFeatures(features ...string) CallOption {
+	return func(t *callTarget) {
+		if t.analyze != nil {
+			t.analyze.Features = features
+		}
+	}
+}
+
+// WithThaiOnly analyzes only the Thai spans of the text, see
+// AnalyzeOptions.ThaiOnly (AnalyzeText)
+func WithThaiOnly(thaiOnly bool) CallOption {
+	return func(t *callTarget) {
+		if t.analyze != nil {
+			t.analyze.ThaiOnly = thaiOnly
+		}
+	}
+}
+
+// WithPreprocess cleans up the text on the service side first, see
+// AnalyzeOptions.Preprocess (Tokenize, Romanize, Transliterate,
+// SyllableTokenize, AnalyzeText)
+func WithPreprocess(opts PreprocessOptions) CallOption {
+	return func(t *callTarget) {
+		if t.tokenize != nil {
+			t.tokenize.Preprocess = &opts
+		}
+		if t.romanize != nil {
+			t.romanize.Preprocess = &opts
+		}
+		if t.transliterate != nil {
+			t.transliterate.Preprocess = &opts
+		}
+		if t.syllable != nil {
+			t.syllable.Preprocess = &opts
+		}
+		if t.analyze != nil {
+			t.analyze.Preprocess = &opts
+		}
+	}
+}
+
+// NewTokenizeOptions returns the TokenizeOptions set by opts. It lets other
+// implementations of ThaiNLP, such as pythainlptest.Fake, accept CallOptions.
+func NewTokenizeOptions(opts ...CallOption) TokenizeOptions {
+	var o TokenizeOptions
+	applyCallOptions(callTarget{tokenize: &o}, opts)
+	return o
+}
+
+// NewRomanizeOptions returns the RomanizeOptions set by opts
+func NewRomanizeOptions(opts ...CallOption) RomanizeOptions {
+	var o RomanizeOptions
+	applyCallOptions(callTarget{romanize: &o}, opts)
+	return o
+}
+
+// NewTransliterateOptions returns the TransliterateOptions set by opts
+func NewTransliterateOptions(opts ...CallOption) TransliterateOptions {
+	var o TransliterateOptions
+	applyCallOptions(callTarget{transliterate: &o}, opts)
+	return o
+}
+
+// NewSyllableTokenizeOptions returns the SyllableTokenizeOptions set by opts
+func NewSyllableTokenizeOptions(opts ...CallOption) SyllableTokenizeOptions {
+	var o SyllableTokenizeOptions
+	applyCallOptions(callTarget{syllable: &o}, opts)
+	return o
+}
+
+// NewAnalyzeOptions returns the AnalyzeOptions set by opts, extracting the
+// tokenize and romanize features unless WithFeatures says otherwise
+func NewAnalyzeOptions(opts ...CallOption) AnalyzeOptions {
+	o := AnalyzeOptions{Features: []string{"tokenize", "romanize"}}
+	applyCallOptions(callTarget{analyze: &o}, opts)
+	return o
+}
+
+func applyCallOptions(t callTarget, opts []CallOption) {
+	for _, opt := range opts {
+		opt(&t)
+	}
+}
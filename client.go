@@ -6,14 +6,29 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// bufferPool holds response body buffers for reuse between requests
+var bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// maxPooledBuffer is the capacity above which a buffer is left to the garbage
+// collector, so that one huge response does not stay in the pool
+const maxPooledBuffer = 1 << 20
+
 // Client handles HTTP communication with the Python service
 type Client struct {
 	baseURL    string
+	token      string
 	httpClient *http.Client
+	limits     atomic.Pointer[ServiceLimits] // Advertised by the last health check
+	active     atomic.Int64                  // Requests awaiting a response
 }
 
 // NewClient creates a new HTTP client for the PyThaiNLP service
@@ -31,32 +46,110 @@ func NewClient(baseURL string, timeout time.Duration) *Client {
 	}
 }
 
+// SetToken sets the bearer token sent with every request
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
+// setHeaders adds the protocol version and, if set, the bearer token to req
+func (c *Client) setHeaders(req *http.Request) {
+	req.Header.Set(protocolHeader, strconv.Itoa(protocolVersion))
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+}
+
 // ServiceError represents an error returned by the Python service
 type ServiceError struct {
 	Code    string                 `json:"code"`
 	Message string                 `json:"message"`
 	Details map[string]interface{} `json:"details,omitempty"`
+
+	// RequestID is the ID of the failed request, from the response metadata
+	RequestID string `json:"-"`
 }
 
 func (e ServiceError) Error() string {
+	if exc := e.Exception(); exc != nil {
+		return fmt.Sprintf("%s: %s", e.Code, exc)
+	}
 	return fmt.Sprintf("%s: %s", e.Code, e.Message)
 }
 
 // ServiceResponse is the common response structure from all endpoints
 type ServiceResponse struct {
-	Data     json.RawMessage        `json:"data"`
-	Metadata map[string]interface{} `json:"metadata"`
-	Error    *ServiceError          `json:"error"`
+	Data     json.RawMessage `json:"data"`
+	Metadata ResponseMeta    `json:"metadata"`
+	Error    *ServiceError   `json:"error"`
+}
+
+// ResponseMeta is the metadata of a service response. Keys without a field of
+// their own, such as the tokenize_engine of an analysis, are kept in Extra.
+type ResponseMeta struct {
+	ProcessingTimeMS float64 `json:"processing_time_ms"`
+	Engine           string  `json:"engine,omitempty"`      // Engine that ran, e.g. the one EngineAuto chose
+	ModelVersion     string  `json:"version,omitempty"`     // PyThaiNLP version
+	CacheHit         bool    `json:"cache_hit"`             // Whether the engines used were already loaded
+	RequestID        string  `json:"request_id,omitempty"`  // Also in the X-Request-ID response header
+	Deterministic    bool    `json:"deterministic"`         // Reproducible output, see WithSeed
+	Granularity      string  `json:"granularity,omitempty"` // Compound segmentation, if not the engine's
+
+	Extra map[string]interface{} `json:"-"`
+}
+
+// responseMetaFields is ResponseMeta without its methods
+type responseMetaFields ResponseMeta
+
+// responseMetaKeys are the keys of the fields of ResponseMeta
+var responseMetaKeys = []string{"processing_time_ms", "engine", "version", "cache_hit", "request_id", "deterministic", "granularity"}
+
+// UnmarshalJSON reads the known keys into their fields and the others into Extra
+func (m *ResponseMeta) UnmarshalJSON(b []byte) error {
+	var fields responseMetaFields
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal(b, &all); err != nil {
+		return err
+	}
+	for _, key := range responseMetaKeys {
+		delete(all, key)
+	}
+	if len(all) > 0 {
+		fields.Extra = all
+	}
+	*m = ResponseMeta(fields)
+	return nil
+}
+
+// MarshalJSON writes Extra alongside the known keys
+func (m ResponseMeta) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(responseMetaFields(m))
+	if err != nil || len(m.Extra) == 0 {
+		return b, err
+	}
+	all := maps.Clone(m.Extra)
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, err
+	}
+	return json.Marshal(all)
 }
 
 // doRequest performs an HTTP request and handles the response
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) (*ServiceResponse, error) {
+	c.active.Add(1)
+	defer c.active.Add(-1)
+
 	var reqBody io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
+		if max := c.Limits().MaxRequestBytes; max > 0 && int64(len(jsonBody)) > max {
+			return nil, &LimitError{Limit: "request_bytes", Size: int64(len(jsonBody)), Max: max}
+		}
 		reqBody = bytes.NewReader(jsonBody)
 	}
 
@@ -68,25 +161,34 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	c.setHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("request failed: %w", classifyRequestError(err))
 	}
 	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			bufferPool.Put(buf)
+		}
+	}()
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	// Data is copied out of buf, which may then be reused
 	var serviceResp ServiceResponse
-	if err := json.Unmarshal(respBody, &serviceResp); err != nil {
+	if err := json.Unmarshal(buf.Bytes(), &serviceResp); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
 	if serviceResp.Error != nil {
-		return nil, serviceResp.Error
+		serviceResp.Error.RequestID = serviceResp.Metadata.RequestID
+		return nil, serviceErr(serviceResp.Error)
 	}
 
 	return &serviceResp, nil
@@ -94,49 +196,118 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 
 // Health checks the service health status
 func (c *Client) Health(ctx context.Context) (*HealthResponse, error) {
-	// Health endpoint returns plain JSON, not wrapped
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/health", nil)
+	var health HealthResponse
+	if err := c.getJSON(ctx, "/health", &health); err != nil {
+		return nil, err
+	}
+	if health.Limits != (ServiceLimits{}) {
+		c.limits.Store(&health.Limits)
+	}
+	return &health, nil
+}
+
+// Stats returns the request counters the service keeps per endpoint
+func (c *Client) Stats(ctx context.Context) (*StatsResponse, error) {
+	var stats StatsResponse
+	if err := c.getJSON(ctx, "/stats", &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// getJSON decodes the response of a GET endpoint into v. These endpoints
+// return plain JSON, not wrapped.
+func (c *Client) getJSON(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
+	c.setHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return fmt.Errorf("request failed: %w", classifyRequestError(err))
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("service rejected the token: another instance may be using port %s", req.URL.Port())
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	var health HealthResponse
-	if err := json.Unmarshal(body, &health); err != nil {
-		return nil, fmt.Errorf("failed to parse health response: %w", err)
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", strings.TrimPrefix(path, "/"), err)
 	}
+	return nil
+}
 
-	return &health, nil
+// CallCustom posts payload to path, an endpoint added by a plugin (see
+// WithPlugins), and returns the response. The endpoint must answer like the
+// built-in ones, with an object holding "data", "metadata" and "error"; a
+// non-null error is returned as a *ServiceError.
+func (c *Client) CallCustom(ctx context.Context, path string, payload any) (*ServiceResponse, error) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return c.doRequest(ctx, http.MethodPost, path, payload)
 }
 
 // Tokenize performs word tokenization
 func (c *Client) Tokenize(ctx context.Context, req *TokenizeRequest) (*TokenizeResponse, error) {
+	return c.tokenizeInto(ctx, req, nil)
+}
+
+// tokenizeInto performs word tokenization, decoding the tokens into the
+// memory of dst
+func (c *Client) tokenizeInto(ctx context.Context, req *TokenizeRequest, dst []string) (*TokenizeResponse, error) {
 	resp, err := c.doRequest(ctx, http.MethodPost, "/tokenize", req)
 	if err != nil {
 		return nil, err
 	}
 
+	data := TokenizeResponse{Tokens: dst[:0]}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse tokenize response: %w", err)
+	}
+	data.Metadata = resp.Metadata
+
+	return &data, nil
+}
+
+// TokenizeBatch tokenizes many texts in one request
+func (c *Client) TokenizeBatch(ctx context.Context, req *TokenizeBatchRequest) ([][]string, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/tokenize_batch", req)
+	if err != nil {
+		return nil, err
+	}
+
 	var data struct {
-		Tokens []string `json:"tokens"`
+		Tokens [][]string `json:"tokens"`
 	}
 	if err := json.Unmarshal(resp.Data, &data); err != nil {
-		return nil, fmt.Errorf("failed to parse tokenize response: %w", err)
+		return nil, fmt.Errorf("failed to parse tokenize batch response: %w", err)
 	}
+	return data.Tokens, nil
+}
 
-	return &TokenizeResponse{
-		Tokens:   data.Tokens,
-		Metadata: resp.Metadata,
-	}, nil
+// RomanizeBatch romanizes many texts in one request
+func (c *Client) RomanizeBatch(ctx context.Context, req *RomanizeBatchRequest) ([]string, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/romanize_batch", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Romanized []string `json:"romanized"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse romanize batch response: %w", err)
+	}
+	return data.Romanized, nil
 }
 
 // Romanize performs romanization
@@ -147,9 +318,11 @@ func (c *Client) Romanize(ctx context.Context, req *RomanizeRequest) (*RomanizeR
 	}
 
 	var data struct {
-		Romanized       string   `json:"romanized"`
-		Tokens          []string `json:"tokens,omitempty"`
-		RomanizedTokens []string `json:"romanized_tokens,omitempty"`
+		Romanized       string            `json:"romanized"`
+		Tokens          []string          `json:"tokens,omitempty"`
+		RomanizedTokens []string          `json:"romanized_tokens,omitempty"`
+		Entities        []RomanizedEntity `json:"entities,omitempty"`
+		Text            string            `json:"text,omitempty"`
 	}
 	if err := json.Unmarshal(resp.Data, &data); err != nil {
 		return nil, fmt.Errorf("failed to parse romanize response: %w", err)
@@ -159,6 +332,8 @@ func (c *Client) Romanize(ctx context.Context, req *RomanizeRequest) (*RomanizeR
 		Romanized:       data.Romanized,
 		Tokens:          data.Tokens,
 		RomanizedTokens: data.RomanizedTokens,
+		Entities:        data.Entities,
+		Text:            data.Text,
 		Metadata:        resp.Metadata,
 	}, nil
 }
@@ -171,15 +346,21 @@ func (c *Client) Transliterate(ctx context.Context, req *TransliterateRequest) (
 	}
 
 	var data struct {
-		Phonetic string `json:"phonetic"`
+		Phonetic       string   `json:"phonetic"`
+		Tokens         []string `json:"tokens"`
+		PhoneticTokens []string `json:"phonetic_tokens"`
+		Text           string   `json:"text"`
 	}
 	if err := json.Unmarshal(resp.Data, &data); err != nil {
 		return nil, fmt.Errorf("failed to parse transliterate response: %w", err)
 	}
 
 	return &TransliterateResponse{
-		Phonetic: data.Phonetic,
-		Metadata: resp.Metadata,
+		Phonetic:       data.Phonetic,
+		Tokens:         data.Tokens,
+		PhoneticTokens: data.PhoneticTokens,
+		Text:           data.Text,
+		Metadata:       resp.Metadata,
 	}, nil
 }
 
@@ -192,6 +373,7 @@ func (c *Client) SyllableTokenize(ctx context.Context, req *SyllableTokenizeRequ
 
 	var data struct {
 		Syllables []string `json:"syllables"`
+		Text      string   `json:"text"`
 	}
 	if err := json.Unmarshal(resp.Data, &data); err != nil {
 		return nil, fmt.Errorf("failed to parse syllable tokenize response: %w", err)
@@ -199,10 +381,58 @@ func (c *Client) SyllableTokenize(ctx context.Context, req *SyllableTokenizeRequ
 
 	return &SyllableTokenizeResponse{
 		Syllables: data.Syllables,
+		Text:      data.Text,
 		Metadata:  resp.Metadata,
 	}, nil
 }
 
+// Engines reports every known engine with its availability and model state
+func (c *Client) Engines(ctx context.Context) ([]EngineInfo, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/engines", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Engines []EngineInfo `json:"engines"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse engines response: %w", err)
+	}
+
+	return data.Engines, nil
+}
+
+// ProbeEngine imports an engine and loads its model if it is downloaded
+func (c *Client) ProbeEngine(ctx context.Context, operation, engine string) (*EngineProbe, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/probe_engine", map[string]string{"operation": operation, "engine": engine})
+	if err != nil {
+		return nil, err
+	}
+
+	var probe EngineProbe
+	if err := json.Unmarshal(resp.Data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse probe response: %w", err)
+	}
+	return &probe, nil
+}
+
+// Provinces lists the provinces of PyThaiNLP's corpus
+func (c *Client) Provinces(ctx context.Context) ([]CorpusProvince, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/provinces", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Provinces []CorpusProvince `json:"provinces"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse provinces response: %w", err)
+	}
+	return data.Provinces, nil
+}
+
 // Analyze performs combined analysis
 func (c *Client) Analyze(ctx context.Context, req *AnalyzeRequest) (*AnalyzeResponse, error) {
 	resp, err := c.doRequest(ctx, http.MethodPost, "/analyze", req)
@@ -221,6 +451,234 @@ func (c *Client) Analyze(ctx context.Context, req *AnalyzeRequest) (*AnalyzeResp
 	}, nil
 }
 
+// Preprocess cleans up text without analyzing it
+func (c *Client) Preprocess(ctx context.Context, req *PreprocessRequest) (string, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/preprocess", req)
+	if err != nil {
+		return "", err
+	}
+
+	var data struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return "", fmt.Errorf("failed to parse preprocess response: %w", err)
+	}
+	return data.Text, nil
+}
+
+// SyllablePhonetics returns the IPA, tone and romanization of each syllable
+func (c *Client) SyllablePhonetics(ctx context.Context, req *SyllablePhoneticsRequest) (*SyllablePhoneticsResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/syllable_phonetics", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Syllables []SyllablePhonetics `json:"syllables"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse syllable phonetics response: %w", err)
+	}
+
+	return &SyllablePhoneticsResponse{
+		Syllables: data.Syllables,
+		Metadata:  resp.Metadata,
+	}, nil
+}
+
+// AnalyzeDocument splits text into paragraphs, sentences, words and syllables
+func (c *Client) AnalyzeDocument(ctx context.Context, req *AnalyzeDocumentRequest) (*AnalyzeDocumentResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/analyze_document", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Paragraphs []struct {
+			Text      string `json:"text"`
+			Sentences []struct {
+				Text  string `json:"text"`
+				Words []struct {
+					Text      string   `json:"text"`
+					Syllables []string `json:"syllables"`
+				} `json:"words"`
+			} `json:"sentences"`
+		} `json:"paragraphs"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse analyze document response: %w", err)
+	}
+
+	paragraphs := make([]Paragraph, 0, len(data.Paragraphs))
+	for _, p := range data.Paragraphs {
+		paragraph := Paragraph{Text: p.Text, Sentences: make([]Sentence, 0, len(p.Sentences))}
+		for _, s := range p.Sentences {
+			sentence := Sentence{Text: s.Text, Words: make([]Word, 0, len(s.Words))}
+			for _, w := range s.Words {
+				word := Word{Text: w.Text, Syllables: make([]Syllable, 0, len(w.Syllables))}
+				for _, syllable := range w.Syllables {
+					word.Syllables = append(word.Syllables, Syllable{Text: syllable})
+				}
+				sentence.Words = append(sentence.Words, word)
+			}
+			paragraph.Sentences = append(paragraph.Sentences, sentence)
+		}
+		paragraphs = append(paragraphs, paragraph)
+	}
+
+	return &AnalyzeDocumentResponse{
+		Paragraphs: paragraphs,
+		Metadata:   resp.Metadata,
+	}, nil
+}
+
+// ParagraphTokenize splits text into paragraphs
+func (c *Client) ParagraphTokenize(ctx context.Context, req *ParagraphTokenizeRequest) (*ParagraphTokenizeResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/paragraph_tokenize", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Paragraphs []string `json:"paragraphs"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse paragraph tokenize response: %w", err)
+	}
+
+	return &ParagraphTokenizeResponse{
+		Paragraphs: data.Paragraphs,
+		Metadata:   resp.Metadata,
+	}, nil
+}
+
+// NER tags the words of text with their named entity class
+func (c *Client) NER(ctx context.Context, req *NERRequest) (*NERResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/ner", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Tokens []TaggedToken `json:"tokens"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse NER response: %w", err)
+	}
+
+	return &NERResponse{
+		Tokens:   data.Tokens,
+		Metadata: resp.Metadata,
+	}, nil
+}
+
+// PersonNames checks whether a token is a person name or, with Extract,
+// finds the person names of a text
+func (c *Client) PersonNames(ctx context.Context, req *PersonNamesRequest) (*PersonNamesResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/person_names", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Name *struct {
+			Likely bool `json:"likely"`
+		} `json:"name"`
+		Names []PersonName `json:"names"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse person names response: %w", err)
+	}
+
+	result := &PersonNamesResponse{
+		Names:    data.Names,
+		Metadata: resp.Metadata,
+	}
+	if data.Name != nil {
+		result.Likely = data.Name.Likely
+	}
+	return result, nil
+}
+
+// WordDifficulty reports the corpus frequency and syllable count of words
+func (c *Client) WordDifficulty(ctx context.Context, req *WordDifficultyRequest) (*WordDifficultyResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/word_difficulty", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Words      []WordDifficulty `json:"words"`
+		CorpusSize int              `json:"corpus_size"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse word difficulty response: %w", err)
+	}
+
+	return &WordDifficultyResponse{
+		Words:      data.Words,
+		CorpusSize: data.CorpusSize,
+		Metadata:   resp.Metadata,
+	}, nil
+}
+
+// ReverseTransliterate writes romanized text in Thai script
+func (c *Client) ReverseTransliterate(ctx context.Context, req *ReverseTransliterateRequest) (*ReverseTransliterateResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/reverse_transliterate", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Thai string `json:"thai"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse reverse transliterate response: %w", err)
+	}
+
+	return &ReverseTransliterateResponse{
+		Thai:     data.Thai,
+		Metadata: resp.Metadata,
+	}, nil
+}
+
+// Similarity scores candidates against a text
+func (c *Client) Similarity(ctx context.Context, req *SimilarityRequest) (*SimilarityResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/similarity", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Scores []float64 `json:"scores"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse similarity response: %w", err)
+	}
+
+	return &SimilarityResponse{
+		Scores:   data.Scores,
+		Metadata: resp.Metadata,
+	}, nil
+}
+
+// SearchTerms normalizes text into index terms
+func (c *Client) SearchTerms(ctx context.Context, req *SearchTermsRequest) ([]SearchTerm, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, "/search_terms", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Terms []SearchTerm `json:"terms"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse search terms response: %w", err)
+	}
+	return data.Terms, nil
+}
+
 // Request types
 
 // TokenizeRequest represents a tokenization request
@@ -228,26 +686,64 @@ type TokenizeRequest struct {
 	Text    string                 `json:"text"`
 	Engine  string                 `json:"engine,omitempty"`
 	Options map[string]interface{} `json:"options,omitempty"`
+	Protect []string               `json:"protect,omitempty"`
+
+	KeepWhitespace *bool  `json:"keep_whitespace,omitempty"` // Nil for PyThaiNLP's default, true
+	JoinBrokenNum  *bool  `json:"join_broken_num,omitempty"` // Nil for PyThaiNLP's default, true
+	Granularity    string `json:"granularity,omitempty"`
+
+	Preprocess     *PreprocessOptions `json:"preprocess,omitempty"`
+	LoadBudgetMs   int64              `json:"load_budget_ms,omitempty"`
+	FallbackEngine string             `json:"fallback_engine,omitempty"`
+}
+
+// TokenizeBatchRequest represents a request tokenizing many texts
+type TokenizeBatchRequest struct {
+	Texts          []string               `json:"texts"`
+	Engine         string                 `json:"engine,omitempty"`
+	Options        map[string]interface{} `json:"options,omitempty"`
+	KeepWhitespace *bool                  `json:"keep_whitespace,omitempty"`
+	JoinBrokenNum  *bool                  `json:"join_broken_num,omitempty"`
+	Granularity    string                 `json:"granularity,omitempty"`
 }
 
 // RomanizeRequest represents a romanization request
 type RomanizeRequest struct {
-	Text     string `json:"text"`
-	Engine   string `json:"engine,omitempty"`
-	Tokenize bool   `json:"tokenize,omitempty"`
+	Text           string             `json:"text"`
+	Engine         string             `json:"engine,omitempty"`
+	Tokenize       bool               `json:"tokenize,omitempty"`
+	LookupFallback string             `json:"lookup_fallback,omitempty"`
+	Overrides      map[string]string  `json:"overrides,omitempty"`
+	ProperNouns    bool               `json:"proper_nouns,omitempty"`
+	BatchSize      int                `json:"batch_size,omitempty"`
+	Preprocess     *PreprocessOptions `json:"preprocess,omitempty"`
+	LoadBudgetMs   int64              `json:"load_budget_ms,omitempty"`
+	FallbackEngine string             `json:"fallback_engine,omitempty"`
+}
+
+// RomanizeBatchRequest represents a request romanizing many texts
+type RomanizeBatchRequest struct {
+	Texts          []string `json:"texts"`
+	Engine         string   `json:"engine,omitempty"`
+	LookupFallback string   `json:"lookup_fallback,omitempty"`
 }
 
 // TransliterateRequest represents a transliteration request
 type TransliterateRequest struct {
-	Text   string `json:"text"`
-	Engine string `json:"engine,omitempty"`
+	Text           string             `json:"text"`
+	Engine         string             `json:"engine,omitempty"`
+	Tokenize       bool               `json:"tokenize,omitempty"`
+	Preprocess     *PreprocessOptions `json:"preprocess,omitempty"`
+	LoadBudgetMs   int64              `json:"load_budget_ms,omitempty"`
+	FallbackEngine string             `json:"fallback_engine,omitempty"`
 }
 
 // SyllableTokenizeRequest represents a syllable tokenization request
 type SyllableTokenizeRequest struct {
-	Text           string `json:"text"`
-	Engine         string `json:"engine,omitempty"`
-	KeepWhitespace bool   `json:"keep_whitespace,omitempty"`
+	Text           string             `json:"text"`
+	Engine         string             `json:"engine,omitempty"`
+	KeepWhitespace bool               `json:"keep_whitespace,omitempty"`
+	Preprocess     *PreprocessOptions `json:"preprocess,omitempty"`
 }
 
 // AnalyzeRequest represents a combined analysis request
@@ -258,41 +754,233 @@ type AnalyzeRequest struct {
 	RomanizeEngine      string   `json:"romanize_engine,omitempty"`
 	TransliterateEngine string   `json:"transliterate_engine,omitempty"`
 	SyllableEngine      string   `json:"syllable_engine,omitempty"`
+	LemmaEngine         string   `json:"lemma_engine,omitempty"`
+	Granularity         string   `json:"granularity,omitempty"`
+	BatchSize           int      `json:"batch_size,omitempty"`
+
+	Preprocess *PreprocessOptions `json:"preprocess,omitempty"`
+}
+
+// PreprocessRequest represents a standalone preprocessing request
+type PreprocessRequest struct {
+	Text       string             `json:"text"`
+	Preprocess *PreprocessOptions `json:"preprocess"`
+}
+
+// SyllablePhoneticsRequest represents a per-syllable pronunciation request
+type SyllablePhoneticsRequest struct {
+	Text                string `json:"text"`
+	SyllableEngine      string `json:"syllable_engine,omitempty"`
+	TransliterateEngine string `json:"transliterate_engine,omitempty"`
+	RomanizeEngine      string `json:"romanize_engine,omitempty"`
+}
+
+// AnalyzeDocumentRequest represents a paragraph to syllable segmentation request
+type AnalyzeDocumentRequest struct {
+	Text           string `json:"text"`
+	SentenceEngine string `json:"sentence_engine,omitempty"`
+	TokenizeEngine string `json:"tokenize_engine,omitempty"`
+	SyllableEngine string `json:"syllable_engine,omitempty"`
+}
+
+// ParagraphTokenizeRequest represents a paragraph segmentation request
+type ParagraphTokenizeRequest struct {
+	Text   string `json:"text"`
+	Engine string `json:"engine,omitempty"`
+}
+
+// NERRequest represents a named entity recognition request
+type NERRequest struct {
+	Text string `json:"text"`
+}
+
+// PersonNamesRequest represents a person name check or extraction request
+type PersonNamesRequest struct {
+	Text    string `json:"text"`
+	Extract bool   `json:"extract,omitempty"`
+}
+
+// WordDifficultyRequest represents a word frequency and syllable count request
+type WordDifficultyRequest struct {
+	Words []string `json:"words"`
+}
+
+// ReverseTransliterateRequest represents a reverse transliteration request
+type ReverseTransliterateRequest struct {
+	Text    string                 `json:"text"`
+	Lang    string                 `json:"lang,omitempty"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// SimilarityRequest represents a sentence similarity request
+type SimilarityRequest struct {
+	Text       string   `json:"text"`
+	Candidates []string `json:"candidates"`
+	Model      string   `json:"model,omitempty"`
+}
+
+// SearchTermsRequest represents a search normalization request
+type SearchTermsRequest struct {
+	Text           string `json:"text"`
+	Engine         string `json:"engine,omitempty"`
+	KeepStopwords  bool   `json:"keep_stopwords,omitempty"`
+	SoundexEngine  string `json:"soundex_engine,omitempty"`
+	RomanizeEngine string `json:"romanize_engine,omitempty"`
 }
 
 // Response types
 
 // HealthResponse represents the health check response
 type HealthResponse struct {
-	Status  string              `json:"status"`
-	Version string              `json:"version"`
-	Engines map[string][]string `json:"engines"`
+	Status        string              `json:"status"`
+	Version       string              `json:"version"`
+	Restarts      int                 `json:"restarts"`
+	Protocol      int                 `json:"protocol"`
+	Uptime        float64             `json:"uptime_seconds"`
+	MemoryRSS     uint64              `json:"memory_rss"`
+	Requests      RequestCounters     `json:"requests"`
+	QueueDepth    int                 `json:"queue_depth"`
+	Workers       int                 `json:"workers"`
+	Seed          *int64              `json:"seed"`          // Set with WithSeed
+	Deterministic bool                `json:"deterministic"` // Whether the seed made outputs reproducible
+	LoadedModels  []LoadedModel       `json:"loaded_models"`
+	Plugins       []string            `json:"plugins"`     // Plugins the service loaded, see WithPlugins
+	PluginHash    string              `json:"plugin_hash"` // Checksum of the plugin files
+	Preload       string              `json:"preload"`     // Engines loaded at startup, see WithWarmEngines
+	Limits        ServiceLimits       `json:"limits"`
+	Engines       map[string][]string `json:"engines"`
+}
+
+// RequestCounters counts the requests served since the service started
+type RequestCounters struct {
+	Total  int64 `json:"total"`
+	Errors int64 `json:"errors"`
+}
+
+// StatsResponse reports the requests the service served since it started
+type StatsResponse struct {
+	Uptime     float64                  `json:"uptime_seconds"`
+	Requests   RequestCounters          `json:"requests"`
+	Endpoints  map[string]EndpointStats `json:"endpoints"` // Keyed by path, e.g. "/tokenize"
+	ModelCache ModelCacheCounters       `json:"model_cache"`
+}
+
+// EndpointStats counts the requests to one endpoint of the service
+type EndpointStats struct {
+	Requests     int64   `json:"requests"`
+	Errors       int64   `json:"errors"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"` // Measured in the service, including the wait for a worker
+}
+
+// ModelCacheCounters counts the engine calls that found their model already
+// loaded (hits) and those that loaded it (misses)
+type ModelCacheCounters struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// LoadedModel is an engine the service has loaded into memory
+type LoadedModel struct {
+	Operation string `json:"operation"`
+	Engine    string `json:"engine"`
+	// MemoryBytes is how much the first call to the engine grew the
+	// service's resident memory; an approximation of the model's footprint
+	MemoryBytes uint64 `json:"memory_bytes"`
 }
 
 // TokenizeResponse represents a tokenization response
 type TokenizeResponse struct {
-	Tokens   []string               `json:"tokens"`
-	Metadata map[string]interface{} `json:"metadata"`
+	Tokens   []string     `json:"tokens"`
+	Text     string       `json:"text,omitempty"` // Preprocessed text
+	Metadata ResponseMeta `json:"metadata"`
 }
 
 // RomanizeResponse represents a romanization response
 type RomanizeResponse struct {
-	Romanized       string                 `json:"romanized"`
-	Tokens          []string               `json:"tokens,omitempty"`
-	RomanizedTokens []string               `json:"romanized_tokens,omitempty"`
-	Metadata        map[string]interface{} `json:"metadata"`
+	Romanized       string            `json:"romanized"`
+	Tokens          []string          `json:"tokens,omitempty"`
+	RomanizedTokens []string          `json:"romanized_tokens,omitempty"`
+	Entities        []RomanizedEntity `json:"entities,omitempty"`
+	Text            string            `json:"text,omitempty"` // Preprocessed text
+	Metadata        ResponseMeta      `json:"metadata"`
 }
 
 // TransliterateResponse represents a transliteration response
 type TransliterateResponse struct {
-	Phonetic string                 `json:"phonetic"`
-	Metadata map[string]interface{} `json:"metadata"`
+	Phonetic       string       `json:"phonetic"`
+	Tokens         []string     `json:"tokens,omitempty"`
+	PhoneticTokens []string     `json:"phonetic_tokens,omitempty"`
+	Text           string       `json:"text,omitempty"` // Preprocessed text
+	Metadata       ResponseMeta `json:"metadata"`
 }
 
 // SyllableTokenizeResponse represents a syllable tokenization response
 type SyllableTokenizeResponse struct {
-	Syllables []string               `json:"syllables"`
-	Metadata  map[string]interface{} `json:"metadata"`
+	Syllables []string     `json:"syllables"`
+	Text      string       `json:"text,omitempty"` // Preprocessed text
+	Metadata  ResponseMeta `json:"metadata"`
+}
+
+// SyllablePhoneticsResponse represents a per-syllable pronunciation response
+type SyllablePhoneticsResponse struct {
+	Syllables []SyllablePhonetics `json:"syllables"`
+	Metadata  ResponseMeta        `json:"metadata"`
+}
+
+// AnalyzeDocumentResponse represents a paragraph to syllable segmentation
+// response, without offsets
+type AnalyzeDocumentResponse struct {
+	Paragraphs []Paragraph  `json:"paragraphs"`
+	Metadata   ResponseMeta `json:"metadata"`
+}
+
+// ParagraphTokenizeResponse represents a paragraph segmentation response
+type ParagraphTokenizeResponse struct {
+	Paragraphs []string     `json:"paragraphs"`
+	Metadata   ResponseMeta `json:"metadata"`
+}
+
+// NERResponse represents a named entity recognition response, with BIO tags
+// and without offsets
+type NERResponse struct {
+	Tokens   []TaggedToken `json:"tokens"`
+	Metadata ResponseMeta  `json:"metadata"`
+}
+
+// PersonNamesResponse represents a person name check or extraction response.
+// Likely is set by a check, Names by an extraction.
+type PersonNamesResponse struct {
+	Likely   bool         `json:"likely"`
+	Names    []PersonName `json:"names"`
+	Metadata ResponseMeta `json:"metadata"`
+}
+
+// WordDifficultyResponse represents a word frequency and syllable count
+// response, without scores and levels
+type WordDifficultyResponse struct {
+	Words      []WordDifficulty `json:"words"`
+	CorpusSize int              `json:"corpus_size"` // Number of words ranked
+	Metadata   ResponseMeta     `json:"metadata"`
+}
+
+// CorpusProvince is a province as listed by PyThaiNLP's corpus
+type CorpusProvince struct {
+	NameTH string `json:"name_th"`
+	AbbrTH string `json:"abbr_th"`
+	NameEN string `json:"name_en"`
+	AbbrEN string `json:"abbr_en"`
+}
+
+// ReverseTransliterateResponse represents a reverse transliteration response
+type ReverseTransliterateResponse struct {
+	Thai     string       `json:"thai"`
+	Metadata ResponseMeta `json:"metadata"`
+}
+
+// SimilarityResponse represents a sentence similarity response
+type SimilarityResponse struct {
+	Scores   []float64    `json:"scores"`
+	Metadata ResponseMeta `json:"metadata"`
 }
 
 // AnalyzeData contains the results of combined analysis
@@ -301,11 +989,16 @@ type AnalyzeData struct {
 	Romanized       string   `json:"romanized,omitempty"`
 	RomanizedTokens []string `json:"romanized_tokens,omitempty"`
 	Phonetic        string   `json:"phonetic,omitempty"`
+	PhoneticTokens  []string `json:"phonetic_tokens,omitempty"`
 	Syllables       []string `json:"syllables,omitempty"`
+	Text            string   `json:"text,omitempty"`
+	FrequencyRanks  []int    `json:"frequency_ranks,omitempty"`
+	Frequencies     []int    `json:"frequencies,omitempty"`
+	Lemmas          []string `json:"lemmas,omitempty"`
 }
 
 // AnalyzeResponse represents a combined analysis response
 type AnalyzeResponse struct {
-	Data     AnalyzeData            `json:"data"`
-	Metadata map[string]interface{} `json:"metadata"`
+	Data     AnalyzeData  `json:"data"`
+	Metadata ResponseMeta `json:"metadata"`
 }
\ No newline at end of file
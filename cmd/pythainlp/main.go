@@ -0,0 +1,388 @@
+// Command pythainlp runs PyThaiNLP from the shell. It starts the service
+// container, processes its input line by line, and stops the container when
+// done.
+//
+// Usage:
+//
+//	pythainlp <command> [flags] [file...]
+//
+// Commands are tokenize, romanize, g2p (transliteration) and analyze. Input is
+// read from the files, or from stdin if there are none, and each line gives
+// one line of output: space-separated tokens, the romanization, the phonetic
+// form, or with -json a JSON object.
+//
+//	echo "สวัสดีครับ" | pythainlp tokenize
+//	pythainlp romanize -engine thai2rom -full -json words.txt
+//
+// The batch command processes a whole corpus with ProcessFile instead, writing
+// JSONL and resuming where an interrupted run stopped:
+//
+//	pythainlp batch -format jsonl -concurrency 8 corpus.jsonl analyzed.jsonl
+//
+// The bench command measures the latency and throughput of the engines at
+// several payload sizes and concurrency levels, as CSV or JSON:
+//
+//	pythainlp bench -ops tokenize -sizes 100,1000 -concurrency 1,8 > tokenize.csv
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp"
+	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp/bench"
+)
+
+const usage = `usage: pythainlp <command> [flags] [file...]
+
+commands:
+  tokenize   split text into words
+  romanize   convert text to Latin script
+  g2p        convert text to its phonetic (IPA) form
+  analyze    run several of the above at once
+  batch      analyze a large corpus file into JSONL, resumably
+  bench      measure engine latency and throughput
+
+Run "pythainlp <command> -h" for the flags of a command.
+`
+
+// command processes one input line
+type command func(ctx context.Context, mgr *pythainlp.PyThaiNLPManager, line string) (text string, result any, err error)
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "-help" || os.Args[1] == "--help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	run := run
+	switch os.Args[1] {
+	case "batch":
+		run = runBatch
+	case "bench":
+		run = runBench
+	}
+	if err := run(os.Args[1], os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "pythainlp:", err)
+		os.Exit(1)
+	}
+}
+
+func run(name string, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	var engine string
+	asJSON := fs.Bool("json", false, "print one JSON object per input line")
+	full := fs.Bool("full", false, "use the full image, required for neural engines")
+	debug := fs.Bool("debug", false, "print library and container logs to stderr")
+
+	var cmd command
+	switch name {
+	case "tokenize", "romanize", "g2p":
+		fs.StringVar(&engine, "engine", "", "engine to use (default: the library default)")
+	}
+	switch name {
+	case "tokenize":
+		sep := fs.String("sep", " ", "separator between tokens")
+		cmd = func(ctx context.Context, mgr *pythainlp.PyThaiNLPManager, line string) (string, any, error) {
+			res, err := mgr.TokenizeWithEngine(ctx, line, pythainlp.TokenizeEngine(engine))
+			if err != nil {
+				return "", nil, err
+			}
+			return strings.Join(res.Raw, *sep), res, nil
+		}
+	case "romanize":
+		cmd = func(ctx context.Context, mgr *pythainlp.PyThaiNLPManager, line string) (string, any, error) {
+			res, err := mgr.RomanizeWithEngine(ctx, line, pythainlp.RomanizeEngine(engine))
+			if err != nil {
+				return "", nil, err
+			}
+			return res.Text, res, nil
+		}
+	case "g2p":
+		cmd = func(ctx context.Context, mgr *pythainlp.PyThaiNLPManager, line string) (string, any, error) {
+			res, err := mgr.TransliterateWithEngine(ctx, line, pythainlp.TransliterateEngine(engine))
+			if err != nil {
+				return "", nil, err
+			}
+			return res.Phonetic, res, nil
+		}
+	case "analyze":
+		features := fs.String("features", "tokenize,romanize", "comma-separated features: tokenize, romanize, transliterate, syllable, frequency, lemma")
+		cmd = func(ctx context.Context, mgr *pythainlp.PyThaiNLPManager, line string) (string, any, error) {
+			opts := pythainlp.AnalyzeOptions{Features: strings.Split(*features, ",")}
+			res, err := mgr.AnalyzeWithOptions(ctx, line, opts)
+			if err != nil {
+				return "", nil, err
+			}
+			return analyzeText(opts.Features, res), res, nil
+		}
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", name)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: pythainlp %s [flags] [file...]\n\nflags:\n", name)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	mgr, err := start(ctx, *full, *debug)
+	if err != nil {
+		return err
+	}
+	defer mgr.Close()
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+
+	return forEachLine(fs.Args(), func(line string) error {
+		// Blank lines are passed through to keep the output aligned with the input
+		var text string
+		var result any
+		var err error
+		if strings.TrimSpace(line) != "" {
+			if text, result, err = cmd(ctx, mgr, line); err != nil {
+				return err
+			}
+		}
+		if *asJSON {
+			return enc.Encode(result)
+		}
+		_, err = fmt.Fprintln(out, text)
+		return err
+	})
+}
+
+func runBatch(name string, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	format := fs.String("format", pythainlp.FormatLines, "input format: lines or jsonl")
+	field := fs.String("field", "text", "JSONL field holding the text")
+	features := fs.String("features", "tokenize,romanize", "comma-separated features: tokenize, romanize, transliterate, syllable, frequency, lemma")
+	concurrency := fs.Int("concurrency", 4, "records analyzed at once")
+	full := fs.Bool("full", false, "use the full image, required for neural engines")
+	debug := fs.Bool("debug", false, "print library and container logs to stderr")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: pythainlp batch [flags] input output\n\n"+
+			"Run it again with the same arguments to resume an interrupted run.\n\nflags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	mgr, err := start(ctx, *full, *debug)
+	if err != nil {
+		return err
+	}
+	defer mgr.Close()
+
+	stats, err := mgr.ProcessFile(ctx, fs.Arg(0), fs.Arg(1), pythainlp.ProcessOptions{
+		Analyze:     pythainlp.AnalyzeOptions{Features: strings.Split(*features, ",")},
+		Format:      *format,
+		TextField:   *field,
+		Concurrency: *concurrency,
+		Progress: func(records int) {
+			if records%1000 == 0 {
+				fmt.Fprintf(os.Stderr, "%d records\n", records)
+			}
+		},
+	})
+	if stats != nil {
+		fmt.Fprintf(os.Stderr, "%d records written, %d failed, %d resumed\n", stats.Records, stats.Failed, stats.Resumed)
+	}
+	return err
+}
+
+func runBench(name string, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	ops := fs.String("ops", "tokenize,romanize", "comma-separated operations: tokenize, romanize, transliterate, syllable")
+	engines := fs.String("engines", "", "comma-separated engines to measure (default: all available for each operation)")
+	sizes := fs.String("sizes", "16,256,4096", "comma-separated payload sizes in characters")
+	concurrency := fs.String("concurrency", "1,4,16", "comma-separated numbers of requests in flight")
+	requests := fs.Int("requests", 100, "measured requests per engine, size and concurrency")
+	format := fs.String("format", "csv", "output format: csv or json")
+	full := fs.Bool("full", false, "use the full image, required for neural engines")
+	debug := fs.Bool("debug", false, "print library and container logs to stderr")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: pythainlp bench [flags]\n\nflags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg := bench.Config{
+		Operations: strings.Split(*ops, ","),
+		Requests:   *requests,
+		OnResult: func(r bench.Result) {
+			fmt.Fprintf(os.Stderr, "%s/%s size=%d concurrency=%d: p50 %v, %.1f req/s, %d errors\n",
+				r.Operation, r.Engine, r.PayloadSize, r.Concurrency, r.P50, r.Throughput, r.Errors)
+		},
+	}
+	var err error
+	if cfg.PayloadSizes, err = parseInts(*sizes); err != nil {
+		return fmt.Errorf("invalid -sizes: %w", err)
+	}
+	if cfg.Concurrency, err = parseInts(*concurrency); err != nil {
+		return fmt.Errorf("invalid -concurrency: %w", err)
+	}
+	if *engines != "" {
+		cfg.Engines = make(map[string][]string)
+		for _, op := range cfg.Operations {
+			cfg.Engines[op] = strings.Split(*engines, ",")
+		}
+	}
+	write := bench.WriteCSV
+	switch *format {
+	case "csv":
+	case "json":
+		write = bench.WriteJSON
+	default:
+		return fmt.Errorf("unknown format %q, expected csv or json", *format)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	mgr, err := start(ctx, *full, *debug)
+	if err != nil {
+		return err
+	}
+	defer mgr.Close()
+
+	// Results measured before an interrupt are still written
+	results, err := bench.Run(ctx, mgr, cfg)
+	if werr := write(os.Stdout, results); werr != nil && err == nil {
+		err = werr
+	}
+	return err
+}
+
+// parseInts parses a comma-separated list of positive integers
+func parseInts(s string) ([]int, error) {
+	var ints []int
+	for _, field := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		if n <= 0 {
+			return nil, fmt.Errorf("%d is not positive", n)
+		}
+		ints = append(ints, n)
+	}
+	return ints, nil
+}
+
+// start creates the manager and starts the service
+func start(ctx context.Context, full, debug bool) (*pythainlp.PyThaiNLPManager, error) {
+	if debug {
+		pythainlp.EnableDebugLogging()
+	}
+	mgr, err := pythainlp.NewManager(ctx,
+		pythainlp.WithLightweightMode(!full),
+		pythainlp.WithLogConsumer(stderrLogs{enabled: debug}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := mgr.Init(ctx); err != nil {
+		mgr.Close()
+		return nil, fmt.Errorf("failed to start the service: %w", err)
+	}
+	return mgr, nil
+}
+
+// forEachLine calls fn with every line of the files, or of stdin if there
+// are none
+func forEachLine(files []string, fn func(line string) error) error {
+	if len(files) == 0 {
+		return scanLines(os.Stdin, fn)
+	}
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = scanLines(f, fn)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func scanLines(r io.Reader, fn func(line string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if err := fn(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// analyzeText prints the requested features as tab-separated columns
+func analyzeText(features []string, res *pythainlp.AnalyzeResult) string {
+	var cols []string
+	for _, feature := range features {
+		switch feature {
+		case "tokenize":
+			cols = append(cols, strings.Join(res.RawTokens, " "))
+		case "romanize":
+			cols = append(cols, res.Romanized)
+		case "transliterate":
+			cols = append(cols, res.Phonetic)
+		case "syllable":
+			cols = append(cols, strings.Join(res.Syllables, " "))
+		case "frequency":
+			ranks := make([]string, len(res.Tokens))
+			for i, t := range res.Tokens {
+				ranks[i] = strconv.Itoa(t.FrequencyRank)
+			}
+			cols = append(cols, strings.Join(ranks, " "))
+		case "lemma":
+			// "_" for tokens without a lemma, such as whitespace, keeps the
+			// column aligned with the tokens
+			lemmas := make([]string, len(res.Tokens))
+			for i, t := range res.Tokens {
+				lemmas[i] = cmp.Or(t.Lemma, "_")
+			}
+			cols = append(cols, strings.Join(lemmas, " "))
+		}
+	}
+	return strings.Join(cols, "\t")
+}
+
+// stderrLogs prints container logs to stderr when enabled and drops them otherwise
+type stderrLogs struct {
+	enabled bool
+}
+
+func (l stderrLogs) Log(containerName, message string) {
+	if l.enabled {
+		fmt.Fprintf(os.Stderr, "[%s] %s\n", containerName, message)
+	}
+}
+
+func (l stderrLogs) Err(containerName, message string) {
+	l.Log(containerName, message)
+}
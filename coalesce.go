@@ -0,0 +1,171 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WithRequestCoalescing gathers Tokenize and Romanize calls made concurrently
+// with the same options into batch requests: the first call of a batch waits
+// up to window for others, and a batch is sent early once it holds maxBatch
+// texts. Each call gets its own result back. Servers calling the service from
+// many goroutines trade that much latency for far fewer round trips. A zero
+// window or maxBatch uses 2ms and 64 texts.
+//
+// Calls that need the single-text endpoints are sent as usual: empty text,
+// the auto engine, Protect or Extra when tokenizing, TokenizeFirst, Align,
+// Overrides or ProperNouns when romanizing, and any call while an engine
+// fallback policy is set. The Metadata of coalesced results holds only the
+// engine and granularity.
+func WithRequestCoalescing(window time.Duration, maxBatch int) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		if window <= 0 {
+			window = batchWindow
+		}
+		if maxBatch <= 0 {
+			maxBatch = maxBatchTexts
+		}
+		pm.tokenizeCoalescer = newCoalescer(window, maxBatch, func(ctx context.Context, key tokenizeKey, texts []string) ([][]string, error) {
+			return pm.TokenizeBatch(ctx, texts, TokenizeOptions{
+				Engine:         key.engine,
+				DropWhitespace: key.dropWhitespace,
+				SplitBrokenNum: key.splitBrokenNum,
+				Granularity:    key.granularity,
+			})
+		})
+		pm.romanizeCoalescer = newCoalescer(window, maxBatch, func(ctx context.Context, key romanizeKey, texts []string) ([]string, error) {
+			return pm.RomanizeBatch(ctx, texts, RomanizeOptions{Engine: key.engine, FallbackEngine: key.fallback})
+		})
+	}
+}
+
+// Calls are coalesced with those having the same options
+type tokenizeKey struct {
+	engine         TokenizeEngine
+	dropWhitespace bool
+	splitBrokenNum bool
+	granularity    Granularity
+}
+
+type romanizeKey struct {
+	engine   RomanizeEngine
+	fallback RomanizeEngine
+}
+
+// coalescer turns concurrent single-text calls sharing a key into batches
+type coalescer[K comparable, R any] struct {
+	window   time.Duration
+	maxBatch int
+	send     func(ctx context.Context, key K, texts []string) ([]R, error)
+
+	mu      sync.Mutex
+	pending map[K]*coalescedBatch[R]
+}
+
+type coalescedBatch[R any] struct {
+	texts   []string
+	results []R
+	err     error
+	done    chan struct{}
+	timer   *time.Timer
+}
+
+func newCoalescer[K comparable, R any](window time.Duration, maxBatch int, send func(context.Context, K, []string) ([]R, error)) *coalescer[K, R] {
+	return &coalescer[K, R]{
+		window:   window,
+		maxBatch: maxBatch,
+		send:     send,
+		pending:  make(map[K]*coalescedBatch[R]),
+	}
+}
+
+// do adds text to the pending batch for key and waits for its result
+func (c *coalescer[K, R]) do(ctx context.Context, key K, text string) (R, error) {
+	c.mu.Lock()
+	b := c.pending[key]
+	if b == nil {
+		b = &coalescedBatch[R]{done: make(chan struct{})}
+		c.pending[key] = b
+		b.timer = time.AfterFunc(c.window, func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.flushLocked(ctx, key, b)
+		})
+	}
+	i := len(b.texts)
+	b.texts = append(b.texts, text)
+	if len(b.texts) >= c.maxBatch {
+		c.flushLocked(ctx, key, b)
+	}
+	c.mu.Unlock()
+
+	var zero R
+	select {
+	case <-b.done:
+		if b.err != nil {
+			return zero, b.err
+		}
+		return b.results[i], nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// flushLocked sends b in the background unless it was sent already. As with
+// BatchTokenizer, the request outlives the caller whose context is used.
+func (c *coalescer[K, R]) flushLocked(ctx context.Context, key K, b *coalescedBatch[R]) {
+	if c.pending[key] != b {
+		return
+	}
+	delete(c.pending, key)
+	b.timer.Stop()
+
+	go func() {
+		defer close(b.done)
+		b.results, b.err = c.send(context.WithoutCancel(ctx), key, b.texts)
+		if b.err == nil && len(b.results) != len(b.texts) {
+			b.err = fmt.Errorf("coalesced request returned %d results for %d texts", len(b.results), len(b.texts))
+		}
+	}()
+}
+
+// tokenizeCoalescible reports whether a Tokenize call can go in a batch
+func (pm *PyThaiNLPManager) tokenizeCoalescible(text string, opts TokenizeOptions) bool {
+	return pm.tokenizeCoalescer != nil && pm.fallback == nil && text != "" &&
+		opts.Engine != EngineAuto && len(opts.Protect) == 0 && len(opts.Extra) == 0 && opts.Preprocess == nil
+}
+
+// romanizeCoalescible reports whether a Romanize call can go in a batch
+func (pm *PyThaiNLPManager) romanizeCoalescible(text string, opts RomanizeOptions) bool {
+	return pm.romanizeCoalescer != nil && pm.fallback == nil && text != "" &&
+		!opts.TokenizeFirst && !opts.Align && !opts.ProperNouns && opts.Preprocess == nil &&
+		len(opts.Overrides) == 0 && len(pm.overrides) == 0
+}
+
+// coalescedTokenize tokenizes text as part of a batch
+func (pm *PyThaiNLPManager) coalescedTokenize(ctx context.Context, text string, opts TokenizeOptions) (*TokenizeResult, error) {
+	if opts.Engine == "" {
+		opts.Engine = EngineNewMM
+	}
+	key := tokenizeKey{engine: opts.Engine, dropWhitespace: opts.DropWhitespace, splitBrokenNum: opts.SplitBrokenNum, granularity: opts.Granularity}
+	tokens, err := pm.tokenizeCoalescer.do(ctx, key, text)
+	if err != nil {
+		return nil, err
+	}
+	return newTokenizeResult(text, tokens, Metadata{Engine: string(opts.Engine), Granularity: opts.Granularity}), nil
+}
+
+// coalescedRomanize romanizes text as part of a batch
+func (pm *PyThaiNLPManager) coalescedRomanize(ctx context.Context, text string, opts RomanizeOptions) (*RomanizeResult, error) {
+	if opts.Engine == "" {
+		opts.Engine = EngineRoyin
+	}
+	key := romanizeKey{engine: opts.Engine, fallback: opts.FallbackEngine}
+	romanized, err := pm.romanizeCoalescer.do(ctx, key, text)
+	if err != nil {
+		return nil, err
+	}
+	return &RomanizeResult{Text: romanized, Meta: Metadata{Engine: string(opts.Engine)}}, nil
+}
@@ -0,0 +1,285 @@
+package pythainlp
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ColumnOptions configures ProcessColumns
+type ColumnOptions struct {
+	Comma   rune     // Field delimiter (default ','; '\t' for TSV)
+	Columns []string // Header names of the columns to process
+
+	// Analyze selects the features and engines. Each feature adds one column
+	// per processed column: <name>_tokens, <name>_romanized, <name>_phonetic
+	// and <name>_syllables. Defaults to tokenize and romanize.
+	Analyze AnalyzeOptions
+
+	Separator   string // Joins tokens and syllables in a cell (default " ")
+	Concurrency int    // Cells analyzed at once (default 4)
+}
+
+// columnSuffixes names the column added for each feature
+var columnSuffixes = map[string]string{
+	"tokenize":      "_tokens",
+	"romanize":      "_romanized",
+	"transliterate": "_phonetic",
+	"syllable":      "_syllables",
+	"frequency":     "_frequency",
+	"lemma":         "_lemmas",
+}
+
+// noLemma stands in the lemma column for tokens without a lemma, such as
+// whitespace
+const noLemma = "_"
+
+// ProcessColumns copies a CSV or TSV table with a header row from r to w,
+// appending the analysis of the selected columns as new columns. Rows keep
+// their order; empty cells give empty results. CSV follows RFC 4180 quoting;
+// TSV has none, fields simply being separated by tabs.
+func (pm *PyThaiNLPManager) ProcessColumns(ctx context.Context, r io.Reader, w io.Writer, opts ColumnOptions) error {
+	if opts.Comma == 0 {
+		opts.Comma = ','
+	}
+	if len(opts.Analyze.Features) == 0 {
+		opts.Analyze.Features = []string{"tokenize", "romanize"}
+	}
+	for _, feature := range opts.Analyze.Features {
+		if _, ok := columnSuffixes[feature]; !ok {
+			return fmt.Errorf("unknown feature %q", feature)
+		}
+	}
+	if opts.Separator == "" {
+		opts.Separator = " "
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultBatchConcurrency
+	}
+
+	var reader rowReader
+	var writer rowWriter
+	if opts.Comma == '\t' {
+		reader, writer = newTSVReader(r), newTSVWriter(w)
+	} else {
+		csvReader := csv.NewReader(r)
+		csvReader.Comma = opts.Comma
+		csvWriter := csv.NewWriter(w)
+		csvWriter.Comma = opts.Comma
+		reader, writer = csvReader, csvWriter
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+	indexes := make([]int, len(opts.Columns))
+	for i, name := range opts.Columns {
+		indexes[i] = slices.Index(header, name)
+		if indexes[i] < 0 {
+			return fmt.Errorf("column %q not found in header %v", name, header)
+		}
+	}
+	for _, name := range opts.Columns {
+		for _, feature := range opts.Analyze.Features {
+			header = append(header, name+columnSuffixes[feature])
+		}
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	// Rows are read in chunks whose cells are analyzed concurrently, then
+	// written in order
+	chunkSize := opts.Concurrency * 4
+	line := 2 // the first data row, after the header
+	for {
+		var rows [][]string
+		for len(rows) < chunkSize {
+			row, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			rows = append(rows, row)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		if err := pm.analyzeRows(ctx, rows, line, indexes, opts); err != nil {
+			return err
+		}
+		line += len(rows)
+		for _, row := range rows {
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// analyzeRows appends the analysis columns to each row; line is the row
+// number of rows[0], for error messages
+func (pm *PyThaiNLPManager) analyzeRows(ctx context.Context, rows [][]string, line int, indexes []int, opts ColumnOptions) error {
+	results := make([][]*AnalyzeResult, len(rows))
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for i, row := range rows {
+		results[i] = make([]*AnalyzeResult, len(indexes))
+		for j, index := range indexes {
+			if index >= len(row) || strings.TrimSpace(row[index]) == "" {
+				continue
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				res, err := pm.AnalyzeWithOptions(ctx, row[index], opts.Analyze)
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("row %d, column %q: %w", line+i, opts.Columns[j], err)
+					}
+					mu.Unlock()
+					return
+				}
+				results[i][j] = res
+			}()
+		}
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+
+	for i := range rows {
+		for _, res := range results[i] {
+			for _, feature := range opts.Analyze.Features {
+				rows[i] = append(rows[i], columnValue(feature, res, opts.Separator))
+			}
+		}
+	}
+	return nil
+}
+
+// columnValue returns the cell for one feature of an analysis, or "" if the
+// source cell was empty
+func columnValue(feature string, res *AnalyzeResult, sep string) string {
+	if res == nil {
+		return ""
+	}
+	switch feature {
+	case "tokenize":
+		return strings.Join(res.RawTokens, sep)
+	case "romanize":
+		return res.Romanized
+	case "transliterate":
+		return res.Phonetic
+	case "syllable":
+		return strings.Join(res.Syllables, sep)
+	case "frequency":
+		ranks := make([]string, len(res.Tokens))
+		for i, t := range res.Tokens {
+			ranks[i] = strconv.Itoa(t.FrequencyRank)
+		}
+		return strings.Join(ranks, sep)
+	case "lemma":
+		// One entry per token, as for frequency, so that the cell lines up
+		// with the tokens one
+		lemmas := make([]string, len(res.Tokens))
+		for i, t := range res.Tokens {
+			lemmas[i] = cmp.Or(t.Lemma, noLemma)
+		}
+		return strings.Join(lemmas, sep)
+	}
+	return ""
+}
+
+type rowReader interface {
+	Read() ([]string, error)
+}
+
+type rowWriter interface {
+	Write(row []string) error
+	Flush()
+	Error() error
+}
+
+// tsvReader reads tab-separated lines. Unlike csv.Reader with Comma '\t', it
+// gives quotes no special meaning.
+type tsvReader struct {
+	scanner *bufio.Scanner
+}
+
+func newTSVReader(r io.Reader) *tsvReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return &tsvReader{scanner: scanner}
+}
+
+func (t *tsvReader) Read() ([]string, error) {
+	if !t.scanner.Scan() {
+		if err := t.scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return strings.Split(strings.TrimSuffix(t.scanner.Text(), "\r"), "\t"), nil
+}
+
+// tsvWriter writes tab-separated lines, replacing tabs and line breaks inside
+// fields with spaces since TSV cannot escape them
+type tsvWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+var tsvEscaper = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+func newTSVWriter(w io.Writer) *tsvWriter {
+	return &tsvWriter{w: bufio.NewWriter(w)}
+}
+
+func (t *tsvWriter) Write(row []string) error {
+	if t.err != nil {
+		return t.err
+	}
+	for i, field := range row {
+		if i > 0 {
+			t.w.WriteByte('\t')
+		}
+		tsvEscaper.WriteString(t.w, field)
+	}
+	_, t.err = t.w.WriteString("\n")
+	return t.err
+}
+
+func (t *tsvWriter) Flush() {
+	if err := t.w.Flush(); err != nil && t.err == nil {
+		t.err = err
+	}
+}
+
+func (t *tsvWriter) Error() error {
+	return t.err
+}
@@ -0,0 +1,107 @@
+package pythainlp
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RomanizeDocument romanizes texts, e.g. the paragraphs or subtitles of a
+// document, with one romanization per word across all of them: it tokenizes
+// every text first, romanizes each distinct word once, then builds each
+// result from that table. A word then reads the same everywhere, which
+// thai2rom does not guarantee when it is called again on another sentence,
+// and each word is romanized once however often it occurs. Results are
+// token by token, as with TokenizeFirst; ProperNouns is not supported.
+func (pm *PyThaiNLPManager) RomanizeDocument(ctx context.Context, texts []string, opts RomanizeOptions) ([]*RomanizeResult, error) {
+	if opts.ProperNouns {
+		return nil, errors.New("consistent romanization does not support ProperNouns")
+	}
+	if err := errors.Join(opts.Engine.Validate(), opts.FallbackEngine.Validate()); err != nil {
+		return nil, err
+	}
+	engine := cmp.Or(opts.Engine, EngineRoyin)
+	overrides := pm.romanizeOverrides(opts.Overrides)
+
+	// Keep each overridden word a single token, as the service does
+	var tokenizeOpts []CallOption
+	if len(overrides) > 0 {
+		words := slices.SortedFunc(maps.Keys(overrides), func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+		words = slices.DeleteFunc(words, func(w string) bool { return w == "" })
+		for i, w := range words {
+			words[i] = regexp.QuoteMeta(w)
+		}
+		if len(words) > 0 {
+			tokenizeOpts = append(tokenizeOpts, WithProtect(strings.Join(words, "|")))
+		}
+	}
+
+	tokens := make([][]string, len(texts))
+	seen := make(map[string]bool)
+	var words []string
+	for i, text := range texts {
+		if text == "" {
+			continue
+		}
+		result, err := pm.Tokenize(ctx, text, tokenizeOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("consistent romanization failed: %w", err)
+		}
+		tokens[i] = result.Raw
+		for _, t := range result.Raw {
+			if _, ok := overrides[t]; ok || seen[t] || strings.TrimSpace(t) == "" {
+				continue
+			}
+			seen[t] = true
+			words = append(words, t)
+		}
+	}
+
+	romanized, err := pm.RomanizeBatch(ctx, words, RomanizeOptions{Engine: engine, FallbackEngine: opts.FallbackEngine})
+	if err != nil {
+		return nil, fmt.Errorf("consistent romanization failed: %w", err)
+	}
+	table := maps.Clone(overrides)
+	if table == nil {
+		table = make(map[string]string, len(words))
+	}
+	for i, w := range words {
+		table[w] = romanized[i]
+	}
+
+	results := make([]*RomanizeResult, len(texts))
+	for i, text := range texts {
+		parts := make([]string, len(tokens[i]))
+		for k, t := range tokens[i] {
+			parts[k] = t
+			if r, ok := table[t]; ok {
+				parts[k] = r
+			}
+		}
+		results[i] = &RomanizeResult{
+			Text:           strings.Join(parts, " "),
+			Tokens:         tokens[i],
+			RomanizedParts: parts,
+			Meta:           Metadata{Engine: string(engine)},
+		}
+		if opts.Align {
+			results[i].Alignment = alignSegments(text, tokens[i], parts)
+		}
+	}
+	return results, nil
+}
+
+// romanizeConsistent romanizes a single text as RomanizeDocument does, for
+// RomanizeOptions.Consistent
+func (pm *PyThaiNLPManager) romanizeConsistent(ctx context.Context, text string, opts RomanizeOptions) (*RomanizeResult, error) {
+	results, err := pm.RomanizeDocument(ctx, []string{text}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return results[0], nil
+}
@@ -0,0 +1,119 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// CEFRLevel is a level of the Common European Framework of Reference for
+// languages, from A1 (beginner) to C2 (mastery)
+type CEFRLevel string
+
+const (
+	LevelA1 CEFRLevel = "A1"
+	LevelA2 CEFRLevel = "A2"
+	LevelB1 CEFRLevel = "B1"
+	LevelB2 CEFRLevel = "B2"
+	LevelC1 CEFRLevel = "C1"
+	LevelC2 CEFRLevel = "C2"
+)
+
+// cefrLevels are the levels in order, each with the highest frequency rank
+// it covers; words ranked lower or missing from the corpus are C2
+var cefrLevels = []struct {
+	level   CEFRLevel
+	maxRank int
+}{
+	{LevelA1, 500},
+	{LevelA2, 1000},
+	{LevelB1, 2000},
+	{LevelB2, 4000},
+	{LevelC1, 8000},
+	{LevelC2, math.MaxInt},
+}
+
+// longWordSyllables is the syllable count from which a word is placed one
+// level above its frequency band
+const longWordSyllables = 4
+
+// WordDifficulty is the estimated difficulty of a word for a learner
+type WordDifficulty struct {
+	Word      string    `json:"word"`
+	Rank      int       `json:"rank"`      // Frequency rank in the Thai National Corpus, 1 the most frequent, 0 if absent
+	Frequency int       `json:"frequency"` // Occurrences in the Thai National Corpus
+	Syllables int       `json:"syllables"`
+	Score     float64   `json:"score"` // From 0 (easiest) to 1 (hardest)
+	Level     CEFRLevel `json:"level"`
+}
+
+// WordDifficultyResult contains the difficulty of each word, in order
+type WordDifficultyResult struct {
+	Words []WordDifficulty
+	Meta  Metadata
+}
+
+// WordDifficulty estimates how hard words are for a learner, e.g. to build
+// graded readers or pick vocabulary to teach. Each word gets a score from 0
+// to 1, three quarters from its frequency rank in the Thai National Corpus on
+// a log scale and a quarter from its syllable count, and a CEFR level from
+// its rank band (A1 for the 500 most frequent words up to C2 past 8000 or
+// absent from the corpus), raised by one for words of four syllables or
+// more. Words are matched as given, so pass tokens rather than phrases.
+// These are estimates from frequency alone, not an official CEFR
+// vocabulary list. The first call ranks the whole corpus, which
+// WarmEngines.Frequency does at startup instead.
+func (pm *PyThaiNLPManager) WordDifficulty(ctx context.Context, words []string) (*WordDifficultyResult, error) {
+	if len(words) == 0 {
+		return &WordDifficultyResult{Meta: Metadata{Engine: "tnc"}}, nil
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+
+	var meta ResponseMeta
+	corpusSize := 0
+	results, err := inBatches(pm, words, func(batch []string) ([]WordDifficulty, error) {
+		resp, err := pm.client.WordDifficulty(ctx, &WordDifficultyRequest{Words: batch})
+		if err != nil {
+			return nil, err
+		}
+		meta, corpusSize = resp.Metadata, resp.CorpusSize
+		return resp.Words, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("word difficulty failed: %w", err)
+	}
+	if len(results) != len(words) {
+		return nil, fmt.Errorf("word difficulty failed: got %d results for %d words", len(results), len(words))
+	}
+	for i := range results {
+		results[i].Score, results[i].Level = scoreDifficulty(results[i].Rank, results[i].Syllables, corpusSize)
+	}
+	return &WordDifficultyResult{Words: results, Meta: newMetadata(meta, "tnc")}, nil
+}
+
+// scoreDifficulty returns the score and level of a word from its frequency
+// rank (0 if absent) among corpusSize words and its syllable count
+func scoreDifficulty(rank, syllables, corpusSize int) (float64, CEFRLevel) {
+	rarity := 1.0
+	if rank > 0 && corpusSize > 1 {
+		rarity = min(math.Log(float64(rank))/math.Log(float64(corpusSize)), 1)
+	}
+	length := float64(min(max(syllables-1, 0), longWordSyllables)) / longWordSyllables
+	score := math.Round((0.75*rarity+0.25*length)*1000) / 1000
+
+	band := len(cefrLevels) - 1
+	if rank > 0 {
+		for i, l := range cefrLevels {
+			if rank <= l.maxRank {
+				band = i
+				break
+			}
+		}
+	}
+	if syllables >= longWordSyllables {
+		band = min(band+1, len(cefrLevels)-1)
+	}
+	return score, cefrLevels[band].level
+}
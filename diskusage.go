@@ -0,0 +1,165 @@
+package pythainlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+// corpusDBFile is the catalog PyThaiNLP keeps in its data directory
+const corpusDBFile = "db.json"
+
+// DiskUsage reports the host storage used by the service
+type DiskUsage struct {
+	ImageSize int64            `json:"image_size"` // Size of the service image, 0 if absent
+	ModelSize int64            `json:"model_size"` // Total bytes in the PyThaiNLP data directory
+	Corpora   map[string]int64 `json:"corpora"`    // Bytes per downloaded corpus or model
+	CacheSize int64            `json:"cache_size"` // Total bytes in other files under the data directory
+	Caches    map[string]int64 `json:"caches"`     // Bytes per top-level cache entry
+}
+
+// Total returns the combined size of the image, models and caches
+func (u *DiskUsage) Total() int64 {
+	return u.ImageSize + u.ModelSize + u.CacheSize
+}
+
+// DiskUsage measures the image, the downloaded corpora and the remaining
+// files in the data directory. It does not require the service to be running,
+// except when the data lives in a Docker volume (see BackendInfo.NamedVolume):
+// the volume is then measured from inside the container, or only as a total
+// in ModelSize while the container is stopped.
+func (pm *PyThaiNLPManager) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	usage := &DiskUsage{}
+
+	inspect, err := dockerClient.ImageInspect(ctx, pm.image)
+	if err != nil && !client.IsErrNotFound(err) {
+		return nil, fmt.Errorf("failed to inspect image %s: %w", pm.image, err)
+	}
+	if err == nil {
+		usage.ImageSize = inspect.Size
+	}
+
+	if pm.usesDataVolume() {
+		usage.Corpora, usage.ModelSize, usage.Caches, usage.CacheSize, err = pm.volumeUsage(ctx, dockerClient)
+		if err != nil {
+			Logger.Debug().Err(err).Msg("Could not measure the data volume from the container")
+			usage.Corpora, usage.Caches = map[string]int64{}, map[string]int64{}
+			if usage.ModelSize, err = volumeSize(ctx, dockerClient, pm.dataVolume()); err != nil {
+				return nil, fmt.Errorf("failed to measure data volume: %w", err)
+			}
+		}
+		return usage, nil
+	}
+
+	usage.Corpora, usage.ModelSize, err = entrySizes(pm.modelDir(), corpusDBFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure model directory: %w", err)
+	}
+
+	usage.Caches, usage.CacheSize, err = entrySizes(pm.dataDir, filepath.Base(pm.modelDir()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure data directory: %w", err)
+	}
+
+	return usage, nil
+}
+
+// entrySizes measures each top-level entry of dir, skipping the named ones.
+// The total includes skipped files but not skipped directories.
+func entrySizes(dir string, skip ...string) (map[string]int64, int64, error) {
+	sizes := make(map[string]int64)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return sizes, 0, nil
+		}
+		return nil, 0, err
+	}
+
+	var total int64
+	for _, entry := range entries {
+		size, err := dirSize(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, 0, err
+		}
+		if slices.Contains(skip, entry.Name()) {
+			if !entry.IsDir() {
+				total += size
+			}
+			continue
+		}
+		sizes[entry.Name()] = size
+		total += size
+	}
+	return sizes, total, nil
+}
+
+// volumeUsage measures the data volume from inside the running container, as
+// entrySizes does on the host: the entries of the model directory but the
+// catalog, then the other entries of the data directory
+func (pm *PyThaiNLPManager) volumeUsage(ctx context.Context, dockerClient *client.Client) (corpora map[string]int64, modelSize int64, caches map[string]int64, cacheSize int64, err error) {
+	output, err := pm.execCommand(ctx, dockerClient, []string{"du", "-ab", "--max-depth=2", "/workspace"})
+	if err != nil {
+		return nil, 0, nil, 0, err
+	}
+
+	modelDir := filepath.Base(pm.modelDir())
+	corpora, caches = make(map[string]int64), make(map[string]int64)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		sizeStr, path, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		size, err := strconv.ParseInt(sizeStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel("/workspace", path)
+		if err != nil || rel == "." {
+			continue
+		}
+		parts := strings.Split(rel, "/")
+		switch {
+		case len(parts) == 1 && parts[0] != modelDir:
+			caches[parts[0]] = size
+			cacheSize += size
+		case len(parts) == 2 && parts[0] == modelDir:
+			modelSize += size
+			if parts[1] != corpusDBFile {
+				corpora[parts[1]] = size
+			}
+		}
+	}
+	return corpora, modelSize, caches, cacheSize, nil
+}
+
+// volumeSize returns the size the daemon reports for a volume, or 0 if it
+// does not exist
+func volumeSize(ctx context.Context, dockerClient *client.Client, name string) (int64, error) {
+	du, err := dockerClient.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}})
+	if err != nil {
+		return 0, err
+	}
+	for _, v := range du.Volumes {
+		if v.Name == name && v.UsageData != nil && v.UsageData.Size > 0 {
+			return v.UsageData.Size, nil
+		}
+	}
+	return 0, nil
+}
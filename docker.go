@@ -1,10 +1,17 @@
 package pythainlp
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"embed"
+	"errors"
 	"fmt"
-	"io"
+	"io/fs"
+	"maps"
 	"net"
 	"os"
 	"path/filepath"
@@ -16,6 +23,7 @@ import (
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/rs/zerolog"
 	"github.com/tassa-yoniso-manasi-karoto/dockerutil"
 )
@@ -24,6 +32,11 @@ const (
 	defaultProjectName   = "pythainlp"
 	defaultContainerName = "pythainlp-pythainlp-1"
 	healthCheckPath      = "/health"
+
+	// Where the image ships the service, and where a newer embedded copy is written
+	bakedServicePath  = "/opt/service/server.py"
+	copiedServicePath = "/workspace/service/server.py"
+	// Defaults for WithHealthCheckInterval and WithStartupTimeout
 	serviceCheckInterval = 500 * time.Millisecond
 	maxServiceWaitTime   = 480 * time.Second // account for first run = build take ~4min on low end CPU, low speed network
 
@@ -73,12 +86,58 @@ type PyThaiNLPManager struct {
 	client                   *Client
 	projectName              string
 	containerName            string
+	dataDir                  string
 	serviceURL               string
 	servicePort              int
 	QueryTimeout             time.Duration
+	startupTimeout           time.Duration
+	healthCheckInterval      time.Duration
 	serviceReady             bool
 	lightweightMode          bool
+	ephemeral                bool
+	project                  *types.Project
+	backend                  *BackendInfo
+	token                    string
+	lazyStart                bool
+	serviceWorkers           int
+	warmEngines              WarmEngines
+	replayDir                string
+	audit                    *auditTransport
+	similarityModel          string
+	fallback                 *FallbackPolicy
+	overrides                map[string]string
+	romanizeBatchSize        int
+	tokenizeCoalescer        *coalescer[tokenizeKey, []string]
+	romanizeCoalescer        *coalescer[romanizeKey, string]
+	cache                    *ResultCache
+	composeOverrides         []func(*types.Project)
+	env                      map[string]string
+	plugins                  fs.FS
+	startup                  StartupStatus
+	startupActive            bool
+	phaseTimes               map[StartupPhase]time.Duration // of the startup in progress
+	lastPhaseTimes           map[StartupPhase]time.Duration // of the last successful one
+	startupMu                sync.Mutex                     // guards the startup fields, apart from mu which startService holds
+	health                   healthMonitor
+	lazyInit                 *lazyAttempt
+	idleTimeout              time.Duration
+	idleTimer                *time.Timer
+	idleStopped              bool
+	lastActivity             time.Time
+	offline                  bool
+	seed                     *int64
+	tracebackLimit           int
+	hedging                  *hedgingTransport
+	proxy                    ProxyConfig
+	image                    string
+	registryCredentials      RegistryCredentialsFunc
+	pullOptions              *dockerutil.PullOptions
+	buildFromSource          bool
+	pythainlpVersion         string
+	resolvedVersion          string
 	downloadProgressCallback func(current, total int64, status string)
+	pullProgressCallback     func(PullProgress)
+	logConsumer              LogConsumer
 	mu                       sync.RWMutex
 }
 
@@ -92,6 +151,22 @@ func WithQueryTimeout(timeout time.Duration) ManagerOption {
 	}
 }
 
+// WithStartupTimeout sets how long to wait for the Python service to become
+// healthy after it is started (default 480s)
+func WithStartupTimeout(timeout time.Duration) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.startupTimeout = timeout
+	}
+}
+
+// WithHealthCheckInterval sets the delay between health checks while waiting
+// for the service (default 500ms)
+func WithHealthCheckInterval(interval time.Duration) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.healthCheckInterval = interval
+	}
+}
+
 // WithProjectName sets a custom project name for multiple instances
 func WithProjectName(name string) ManagerOption {
 	return func(pm *PyThaiNLPManager) {
@@ -100,6 +175,17 @@ func WithProjectName(name string) ManagerOption {
 	}
 }
 
+// WithEphemeralInstance gives the manager a unique project and container name
+// and removes its containers on Close, so tests and parallel pipelines can run
+// isolated instances side by side. The image is shared, and so is the data
+// directory among instances of the same project name, set with
+// WithProjectName or the default one.
+func WithEphemeralInstance() ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.ephemeral = true
+	}
+}
+
 // WithContainerName overrides the default container name
 func WithContainerName(name string) ManagerOption {
 	return func(pm *PyThaiNLPManager) {
@@ -114,7 +200,82 @@ func WithLightweightMode(lightweight bool) ManagerOption {
 	}
 }
 
-// WithDownloadProgressCallback sets a callback for download progress during image pull
+// WithOfflineMode disables all registry access and corpus downloads.
+// The image must already be present locally, e.g. loaded with LoadImageFromTar.
+func WithOfflineMode(offline bool) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.offline = offline
+	}
+}
+
+// WithServiceWorkers sets how many requests the Python service processes at
+// once (default 1). Engines backed by native code (CRF, ONNX, torch) then
+// serve concurrent callers in parallel; pure-Python ones remain serialized
+// by the interpreter lock.
+func WithServiceWorkers(n int) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.serviceWorkers = n
+	}
+}
+
+// WithSeed makes the neural engines (thai2rom, wangchanberta, thainer, ...)
+// reproducible between runs: the service seeds Python's, NumPy's and torch's
+// random generators with seed before every request and restricts torch to
+// deterministic algorithms, which may be slower. Metadata.Deterministic then
+// reports whether this took effect; it does not with more than one service
+// worker (WithServiceWorkers), as concurrent requests share the generators.
+// Python's string hashing is seeded separately, with PYTHONHASHSEED (WithEnv).
+func WithSeed(seed int64) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.seed = &seed
+	}
+}
+
+// WithTracebackLimit sets how many frames of the Python traceback an
+// INTERNAL_ERROR carries in its PythonException, keeping the innermost ones:
+// 0, the default, keeps them all and a negative number sends none. It applies
+// from the next start of the service.
+func WithTracebackLimit(frames int) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.tracebackLimit = frames
+	}
+}
+
+// WithEnv adds environment variables to the service container, e.g.
+// HF_HOME, TRANSFORMERS_OFFLINE or PYTHONHASHSEED. Calls add up. Variables
+// also set by another option, such as the proxy of WithProxy, take that
+// option's value, and PYTHAINLP_DATA_DIR and PYTHAINLP_SERVICE_* are
+// reserved. The environment is fixed when the container is created: use
+// InitRecreate after changing it.
+func WithEnv(env map[string]string) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		if pm.env == nil {
+			pm.env = make(map[string]string, len(env))
+		}
+		maps.Copy(pm.env, env)
+	}
+}
+
+// reservedEnv reports whether the manager sets the variable itself
+func reservedEnv(name string) bool {
+	return name == "PYTHAINLP_DATA_DIR" || strings.HasPrefix(name, "PYTHAINLP_SERVICE_")
+}
+
+// WithComposeOverride adjusts the generated compose project before the
+// manager is created, e.g. to add volumes, networks, labels or a healthcheck
+// to the "pythainlp" service. Overrides run in the order given. The service
+// name, its first volume (the data directory), its port and the
+// PYTHAINLP_SERVICE_* environment variables are relied upon and must be kept.
+func WithComposeOverride(fn func(*types.Project)) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.composeOverrides = append(pm.composeOverrides, fn)
+	}
+}
+
+// WithDownloadProgressCallback sets a callback for the progress of the
+// startup. During the image pull current and total count bytes; otherwise
+// they count steps, such as startup phases, build steps or packages to
+// install, and status names what is being counted.
 func WithDownloadProgressCallback(cb func(current, total int64, status string)) ManagerOption {
 	return func(pm *PyThaiNLPManager) {
 		pm.downloadProgressCallback = cb
@@ -127,12 +288,23 @@ func ptr(s string) *string {
 }
 
 // buildComposeProject creates the compose project definition for pythainlp
-func buildComposeProject(dataDir string, port int) *types.Project {
+func (pm *PyThaiNLPManager) buildComposeProject() *types.Project {
 	// Network name follows Docker Compose convention: {project}_{network}
-	defaultNetworkName := defaultProjectName + "_default"
+	defaultNetworkName := pm.projectName + "_default"
+	port := pm.servicePort
+
+	environment := types.MappingWithEquals{
+		"PYTHAINLP_DATA_DIR":     ptr("/workspace/pythainlp-data"),
+		"PYTHAINLP_SERVICE_PORT": ptr(fmt.Sprintf("%d", port)),
+		// Per-instance secret required by the service on every request
+		"PYTHAINLP_SERVICE_TOKEN": ptr(pm.token),
+	}
+	for k, v := range pm.containerEnv() {
+		environment[k] = ptr(v)
+	}
 
 	return &types.Project{
-		Name: defaultProjectName,
+		Name: pm.projectName,
 		// Default network required for port exposure
 		Networks: types.Networks{
 			"default": types.NetworkConfig{
@@ -142,20 +314,19 @@ func buildComposeProject(dataDir string, port int) *types.Project {
 		Services: types.Services{
 			"pythainlp": {
 				Name:          "pythainlp",
-				ContainerName: defaultContainerName, // Explicit for exec commands
-				Image:         ghcrImage,
+				ContainerName: pm.containerName, // Explicit for exec commands
+				Image:         pm.image,
 				StdinOpen:     true,
 				Tty:           true,
 				WorkingDir:    "/workspace",
-				Environment: types.MappingWithEquals{
-					"PYTHAINLP_DATA_DIR": ptr("/workspace/pythainlp-data"),
-				},
+				Environment:   environment,
 				Volumes: []types.ServiceVolumeConfig{{
 					Type:   types.VolumeTypeBind,
-					Source: dataDir,
+					Source: pm.dataDir,
 					Target: "/workspace",
 				}},
 				Ports: []types.ServicePortConfig{{
+					HostIP:    "127.0.0.1", // Not reachable from other hosts
 					Target:    uint32(port),
 					Published: fmt.Sprintf("%d", port),
 					Protocol:  "tcp",
@@ -172,29 +343,55 @@ func buildComposeProject(dataDir string, port int) *types.Project {
 
 // NewManager creates a new PyThaiNLP manager instance
 func NewManager(ctx context.Context, opts ...ManagerOption) (*PyThaiNLPManager, error) {
-	// Enable Docker logging to stdout
-	dockerutil.SetLogOutput(dockerutil.LogToStdout)
-
 	manager := &PyThaiNLPManager{
-		projectName:     defaultProjectName,
-		containerName:   defaultContainerName,
-		QueryTimeout:    DefaultQueryTimeout,
-		lightweightMode: UseLightweightMode,
+		projectName:         defaultProjectName,
+		containerName:       defaultContainerName,
+		QueryTimeout:        DefaultQueryTimeout,
+		startupTimeout:      maxServiceWaitTime,
+		healthCheckInterval: serviceCheckInterval,
+		lightweightMode:     UseLightweightMode,
+		image:               ghcrImage,
 	}
 
 	// Apply options
 	for _, opt := range opts {
 		opt(manager)
 	}
+	if manager.pythainlpVersion != "" {
+		if _, err := pipRequirement(manager.pythainlpVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	// Container logs go to stdout unless the application routes them itself
+	if manager.logConsumer == nil {
+		dockerutil.SetLogOutput(dockerutil.LogToStdout)
+	}
+
+	// The local tag depends on lightweight mode, so it is resolved once all options are applied
+	if manager.buildFromSource {
+		manager.image = manager.localImage()
+	}
+
+	// Ephemeral instances share the data directory of their project name, the
+	// default one unless WithProjectName says otherwise, so corpora are
+	// downloaded once rather than per instance
+	dataName := manager.projectName
+	if manager.ephemeral {
+		manager.projectName = fmt.Sprintf("%s-%s", manager.projectName, randomSuffix())
+		manager.containerName = manager.projectName + "-pythainlp-1"
+	}
 
 	// Get XDG data directory for pythainlp
-	dataDir := filepath.Join(xdg.ConfigHome, manager.projectName)
+	dataDir := filepath.Join(xdg.ConfigHome, dataName)
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create data directory: %w", err)
 	}
+	manager.dataDir = dataDir
+	manager.token = rand.Text()
 
 	// Allocate a free port
-	listener, err := net.Listen("tcp", ":0")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
 		return nil, fmt.Errorf("failed to allocate port: %w", err)
 	}
@@ -204,7 +401,10 @@ func NewManager(ctx context.Context, opts ...ManagerOption) (*PyThaiNLPManager,
 	Logger.Info().Int("port", manager.servicePort).Msg("Allocated port for PyThaiNLP service")
 
 	// Build compose project
-	project := buildComposeProject(dataDir, manager.servicePort)
+	project := manager.buildComposeProject()
+	for _, override := range manager.composeOverrides {
+		override(project)
+	}
 
 	// Configure logging
 	logConfig := dockerutil.LogConfig{
@@ -215,21 +415,30 @@ func NewManager(ctx context.Context, opts ...ManagerOption) (*PyThaiNLPManager,
 		InitMessage: "for more information",
 	}
 
+	if manager.logConsumer != nil {
+		logConfig.LogLevel = zerolog.Disabled
+	}
+
 	logger := dockerutil.NewContainerLogConsumer(logConfig)
+	var logConsumer dockerutil.LogConsumer = logger
+	if manager.logConsumer != nil {
+		logConsumer = &teeLogConsumer{ContainerLogConsumer: logger, sink: manager.logConsumer}
+	}
 
 	// Configure Docker manager
 	cfg := dockerutil.Config{
 		ProjectName:      manager.projectName,
 		Project:          project,
 		RequiredServices: []string{"pythainlp"},
-		LogConsumer:      logger,
+		LogConsumer:      logConsumer,
 		Timeout: dockerutil.Timeout{
 			Create:   30 * time.Minute,
 			Recreate: 60 * time.Minute,
 			Start:    30 * time.Minute,
 		},
-		OnPullProgress: manager.downloadProgressCallback,
 	}
+	// Installed even without a callback, so that StartupStatus follows the pull
+	cfg.OnPullProgress = manager.onPullProgress
 
 	dockerManager, err := dockerutil.NewDockerManager(ctx, cfg)
 	if err != nil {
@@ -237,27 +446,121 @@ func NewManager(ctx context.Context, opts ...ManagerOption) (*PyThaiNLPManager,
 	}
 
 	manager.docker = dockerManager
+	manager.project = project
 	manager.logger = logger
-	manager.serviceURL = fmt.Sprintf("http://localhost:%d", manager.servicePort)
+	manager.serviceURL = fmt.Sprintf("http://127.0.0.1:%d", manager.servicePort)
 
 	// Create HTTP client
 	manager.client = NewClient(manager.serviceURL, manager.QueryTimeout)
+	manager.client.SetToken(manager.token)
+	if manager.replayDir != "" {
+		manager.client.httpClient.Transport = &replayTransport{
+			dir:    manager.replayDir,
+			record: os.Getenv(recordEnv) == "1",
+			start:  manager.startOnDemand,
+			next:   manager.client.httpClient.Transport,
+		}
+	}
+	if manager.audit != nil {
+		manager.audit.next = manager.client.httpClient.Transport
+		manager.client.httpClient.Transport = manager.audit
+	}
+	if manager.hedging != nil {
+		if manager.workers() < 2 {
+			Logger.Warn().Msg("Request hedging needs several service workers, see WithServiceWorkers; disabled")
+		} else {
+			manager.hedging.next = manager.client.httpClient.Transport
+			manager.client.httpClient.Transport = manager.hedging
+		}
+	}
 
 	return manager, nil
 }
 
+// containerEnv returns the extra environment variables passed to the service container
+func (pm *PyThaiNLPManager) containerEnv() map[string]string {
+	env := make(map[string]string)
+	// Variables of WithEnv come first, so that dedicated options win
+	for k, v := range pm.env {
+		if reservedEnv(k) {
+			Logger.Warn().Str("variable", k).Msg("Ignoring environment variable set by the manager")
+			continue
+		}
+		env[k] = v
+	}
+	if pm.offline {
+		env["PYTHAINLP_OFFLINE"] = "1"
+	}
+	for k, v := range pm.proxy.env() {
+		env[k] = v
+	}
+	return env
+}
+
 // PullImage pre-pulls the GHCR image with progress tracking
 func (pm *PyThaiNLPManager) PullImage(ctx context.Context) error {
-	opts := dockerutil.DefaultPullOptions()
-	if pm.downloadProgressCallback != nil {
-		opts.OnProgress = pm.downloadProgressCallback
+	if pm.offline {
+		return fmt.Errorf("image pull disabled in offline mode, use LoadImageFromTar instead")
+	}
+	if pm.buildFromSource {
+		return fmt.Errorf("image is built from source, use BuildImage instead")
+	}
+	pm.checkDaemonProxy(ctx)
+
+	if pm.registryCredentials != nil || pm.pullProgressCallback != nil {
+		return pm.pull(ctx)
 	}
-	return dockerutil.PullImage(ctx, ghcrImage, opts)
+	return dockerutil.PullImage(ctx, pm.image, pm.pullOpts())
 }
 
-// Init initializes the docker service and starts the Python server
+// Init initializes the docker service and starts the Python server.
+// With WithLazyStart it returns immediately and the first request does the work;
+// with WithReplayDir, the first request without a recording.
 func (pm *PyThaiNLPManager) Init(ctx context.Context) error {
-	if err := pm.docker.Init(); err != nil {
+	if pm.lazyStart {
+		Logger.Debug().Msg("Lazy start enabled, deferring initialization to the first request")
+		return nil
+	}
+	if pm.replayDir != "" {
+		Logger.Debug().Str("dir", pm.replayDir).Msg("Replay mode, starting the service only to record missing responses")
+		return nil
+	}
+	return pm.initialize(ctx)
+}
+
+// initialize pulls or builds the image, starts the containers and the service
+func (pm *PyThaiNLPManager) initialize(ctx context.Context) (err error) {
+	pm.beginStartup()
+	defer func() { pm.endStartup(err) }()
+
+	if err := pm.checkOfflineImage(ctx); err != nil {
+		return err
+	}
+	pm.checkDaemonProxy(ctx)
+	pm.prepareBackend(ctx)
+
+	// dockerutil pulls with default options and no credentials.
+	// A locally built image exists once built, so dockerutil's pull becomes a no-op.
+	switch {
+	case pm.buildFromSource:
+		if err := pm.BuildImage(ctx, false); err != nil {
+			return fmt.Errorf("failed to build image: %w", err)
+		}
+	case pm.hasCustomPull() && !pm.offline:
+		if err := pm.PullImage(ctx); err != nil {
+			return fmt.Errorf("failed to pull image: %w", err)
+		}
+	}
+
+	pm.startupProgress(StartupCreate, "Creating the container", 0, 0)
+	// A container left by another manager publishes its port and carries its
+	// token: reusing it as is would fail every request with 401
+	if pm.containerOutdated(ctx) {
+		Logger.Info().Str("container", pm.containerName).Msg("Container was created with another port or token, recreating it")
+		if err := pm.docker.InitRecreate(); err != nil {
+			return fmt.Errorf("failed to recreate docker container: %w", err)
+		}
+	} else if err := pm.docker.Init(); err != nil {
 		return fmt.Errorf("failed to initialize docker: %w", err)
 	}
 
@@ -270,7 +573,28 @@ func (pm *PyThaiNLPManager) Init(ctx context.Context) error {
 }
 
 // InitRecreate removes existing containers then builds and starts new ones
-func (pm *PyThaiNLPManager) InitRecreate(ctx context.Context, noCache bool) error {
+func (pm *PyThaiNLPManager) InitRecreate(ctx context.Context, noCache bool) (err error) {
+	pm.beginStartup()
+	defer func() { pm.endStartup(err) }()
+
+	if err := pm.checkOfflineImage(ctx); err != nil {
+		return err
+	}
+	pm.checkDaemonProxy(ctx)
+	pm.prepareBackend(ctx)
+
+	switch {
+	case pm.buildFromSource:
+		if err := pm.BuildImage(ctx, noCache); err != nil {
+			return fmt.Errorf("failed to build image: %w", err)
+		}
+	case pm.hasCustomPull() && !pm.offline:
+		if err := pm.PullImage(ctx); err != nil {
+			return fmt.Errorf("failed to pull image: %w", err)
+		}
+	}
+
+	pm.startupProgress(StartupCreate, "Recreating the container", 0, 0)
 	if noCache {
 		if err := pm.docker.InitRecreateNoCache(); err != nil {
 			return err
@@ -335,28 +659,61 @@ func (pm *PyThaiNLPManager) startService(ctx context.Context) error {
 		return fmt.Errorf("failed to get Docker client: %w", err)
 	}
 
-	// Copy service files first
-	Logger.Debug().Msg("Copying service files...")
-	if err := pm.copyServiceFiles(ctx, dockerClient); err != nil {
-		return fmt.Errorf("failed to copy service files: %w", err)
+	servicePath, err := pm.resolveServicePath(ctx, dockerClient)
+	if err != nil {
+		return err
+	}
+
+	versionChanged, err := pm.ensurePyThaiNLPVersion(ctx, dockerClient)
+	if err != nil {
+		return fmt.Errorf("failed to install requested PyThaiNLP version: %w", err)
 	}
-	Logger.Debug().Msg("Service files copied successfully")
 
 	// Check if service is already running
 	Logger.Debug().Msg("Checking if service is already running...")
-	if pm.isServiceRunning(ctx) {
-		pm.serviceReady = true
-		Logger.Debug().Msg("Service is already running")
-		return nil
+	if versionChanged && pm.isServiceRunning(ctx) {
+		// The running server still has the previous version imported
+		Logger.Debug().Msg("PyThaiNLP version changed, restarting service...")
+		if err := pm.killServiceProcess(ctx, dockerClient); err != nil {
+			return fmt.Errorf("failed to stop service for version change: %w", err)
+		}
+	} else if pm.isServiceRunning(ctx) {
+		// A service left running by an older version of this library keeps
+		// serving its old code until restarted
+		if err := pm.checkProtocol(ctx); err == nil && !pm.configChanged(ctx) {
+			pm.serviceReady = true
+			Logger.Debug().Msg("Service is already running")
+			return nil
+		} else if err == nil {
+			Logger.Info().Int("workers", pm.workers()).Msg("Restarting service with a different worker count, plugins or seed")
+			if err := pm.killServiceProcess(ctx, dockerClient); err != nil {
+				return fmt.Errorf("failed to stop service for configuration change: %w", err)
+			}
+		} else if errors.Is(err, ErrProtocolMismatch) {
+			Logger.Info().Err(err).Msg("Restarting service with a different protocol version")
+			if err := pm.killServiceProcess(ctx, dockerClient); err != nil {
+				return fmt.Errorf("failed to stop outdated service: %w", err)
+			}
+		} else {
+			return err
+		}
 	}
 	Logger.Debug().Msg("Service is not running, starting it...")
 
-	// Start the service in a new bash session to avoid the interactive Python REPL
-	startCmd := []string{
-		"/bin/bash", "-c",
-		"exec python -u /workspace/service/server.py",
+	if err := pm.copyPlugins(ctx, dockerClient); err != nil {
+		return fmt.Errorf("failed to copy plugins: %w", err)
 	}
 
+	env, err := pm.serviceEnv()
+	if err != nil {
+		return err
+	}
+
+	pm.startupProgress(StartupStart, "Starting the Python service", 0, 0)
+
+	// Start the supervised service in a new bash session to avoid the interactive Python REPL
+	startCmd := []string{"/bin/bash", "-c", supervisorScript, supervisorName, servicePath}
+
 	execConfig := container.ExecOptions{
 		Cmd:          startCmd,
 		AttachStdout: false,
@@ -364,6 +721,7 @@ func (pm *PyThaiNLPManager) startService(ctx context.Context) error {
 		Detach:       true,
 		Tty:          false,
 		WorkingDir:   "/workspace",
+		Env:          env,
 	}
 
 	exec, err := dockerClient.ContainerExecCreate(ctx, pm.containerName, execConfig)
@@ -379,64 +737,148 @@ func (pm *PyThaiNLPManager) startService(ctx context.Context) error {
 		return fmt.Errorf("failed to start service: %w", err)
 	}
 	Logger.Debug().Msg("Python service exec started")
-	
-	// Check if the file exists and see if Python started
-	time.Sleep(2 * time.Second) // Give it a moment to start
-	checkCmd := []string{"ps", "aux", "|", "grep", "server.py"}
-	output, _ := pm.execCommand(ctx, dockerClient, checkCmd)
-	Logger.Debug().Str("processes", string(output)).Msg("Process check")
 
 	// Wait for service to be ready
 	Logger.Debug().Msg("Waiting for service to be ready...")
 	if err := pm.waitForService(ctx); err != nil {
 		return fmt.Errorf("service failed to start: %w", err)
 	}
+	if err := pm.checkProtocol(ctx); err != nil {
+		return err
+	}
 
 	pm.serviceReady = true
 	return nil
 }
 
-// copyServiceFiles copies the embedded service files into the container
-func (pm *PyThaiNLPManager) copyServiceFiles(ctx context.Context, dockerClient *client.Client) error {
-	// Read server.py from embedded files
+// supervisorScript restarts server.py ($1) whenever it exits and exports the
+// restart count for the health endpoint. Its $0 is supervisorName so that
+// killServiceProcess can find it. The service output goes to serviceLogPath
+// and the last exit to serviceStatusPath, for checkCrashLoop; the startup
+// status of an exited server.py is removed so readinessProbe never sees it.
+const supervisorScript = `restarts=0
+rm -f ` + serviceLogPath + ` ` + serviceStatusPath + ` ` + startupStatusPath + `
+while true; do
+  PYTHAINLP_RESTARTS=$restarts python -u "$1" >>` + serviceLogPath + ` 2>&1
+  code=$?
+  rm -f ` + startupStatusPath + `
+  restarts=$((restarts + 1))
+  echo "$restarts $code" >` + serviceStatusPath + `
+  echo "server.py exited with code $code, restarting (restart #$restarts)" >>` + serviceLogPath + `
+  sleep 1
+done`
+
+const supervisorName = "pythainlp-supervisor"
+
+// workers returns the configured number of service workers
+func (pm *PyThaiNLPManager) workers() int {
+	return max(pm.serviceWorkers, 1)
+}
+
+// configChanged reports whether the running service uses a different number
+// of workers, other plugins, another seed or other warm engines than
+// configured. Services predating the settings report none.
+func (pm *PyThaiNLPManager) configChanged(ctx context.Context) bool {
+	health, err := pm.client.Health(ctx)
+	if err != nil {
+		return false
+	}
+	hash, err := pm.pluginHash()
+	if err != nil {
+		return true
+	}
+	warm, err := pm.warmEngines.spec()
+	if err != nil {
+		return true
+	}
+	seedChanged := (health.Seed == nil) != (pm.seed == nil) || health.Seed != nil && *health.Seed != *pm.seed
+	return max(health.Workers, 1) != pm.workers() || health.PluginHash != hash || seedChanged || health.Preload != warm
+}
+
+// resolveServicePath returns the server.py to run. The image ships the service,
+// but an image older than this package gets the embedded copy instead so the
+// Go and Python sides always match.
+func (pm *PyThaiNLPManager) resolveServicePath(ctx context.Context, dockerClient *client.Client) (string, error) {
 	content, err := serviceFiles.ReadFile("service/server.py")
 	if err != nil {
-		return fmt.Errorf("failed to read server.py: %w", err)
+		return "", fmt.Errorf("failed to read server.py: %w", err)
 	}
+	want := fmt.Sprintf("%x", sha256.Sum256(content))
 
-	// Replace port placeholder with actual port
-	portStr := fmt.Sprintf("%d", pm.servicePort)
-	modifiedContent := strings.ReplaceAll(string(content), "__PYTHAINLP_SERVICE_PORT__", portStr)
-	
-	// Verify replacement occurred
-	if strings.Contains(modifiedContent, "__PYTHAINLP_SERVICE_PORT__") {
-		return fmt.Errorf("failed to replace port placeholder in server.py")
+	output, err := pm.execCommand(ctx, dockerClient, []string{"sha256sum", bakedServicePath})
+	if err == nil {
+		if got, _, _ := strings.Cut(string(output), " "); got == want {
+			Logger.Debug().Str("path", bakedServicePath).Msg("Using service baked into the image")
+			return bakedServicePath, nil
+		}
 	}
 
-	// Create service directory in container
-	mkdirCmd := []string{"mkdir", "-p", "/workspace/service"}
-	if _, err := pm.execCommand(ctx, dockerClient, mkdirCmd); err != nil {
-		return fmt.Errorf("failed to create service directory: %w", err)
+	Logger.Debug().Msg("Image service missing or outdated, copying embedded service files...")
+	if err := pm.copyServiceFiles(ctx, dockerClient); err != nil {
+		return "", fmt.Errorf("failed to copy service files: %w", err)
 	}
+	Logger.Debug().Msg("Service files copied successfully")
+	return copiedServicePath, nil
+}
 
-	// Write server.py to container
-	// Using a heredoc approach to write the file
-	writeCmd := []string{
-		fmt.Sprintf("cat > /workspace/service/server.py << 'EOF'\n%s\nEOF", modifiedContent),
+// copyServiceFiles copies the embedded service tree into the container
+// through the archive API, which is safe for any file content
+func (pm *PyThaiNLPManager) copyServiceFiles(ctx context.Context, dockerClient *client.Client) error {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := addServiceTree(tw); err != nil {
+		return fmt.Errorf("failed to archive service files: %w", err)
 	}
-	if _, err := pm.execCommand(ctx, dockerClient, writeCmd); err != nil {
-		return fmt.Errorf("failed to write server.py: %w", err)
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to archive service files: %w", err)
 	}
 
-	// Make it executable
-	chmodCmd := []string{"chmod", "+x", "/workspace/service/server.py"}
-	if _, err := pm.execCommand(ctx, dockerClient, chmodCmd); err != nil {
-		return fmt.Errorf("failed to chmod server.py: %w", err)
+	// The archive holds service/..., extracting to /workspace/service
+	if err := dockerClient.CopyToContainer(ctx, pm.containerName, "/workspace", &buf,
+		container.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("failed to copy service files: %w", err)
 	}
 
 	return nil
 }
 
+// addServiceTree writes the embedded service directory to a tar archive
+func addServiceTree(tw *tar.Writer) error {
+	now := time.Now()
+	return fs.WalkDir(serviceFiles, "service", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeDir,
+				Name:     name + "/",
+				Mode:     0755,
+				ModTime:  now,
+			})
+		}
+		content, err := serviceFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		return addTarFile(tw, name, content, 0755)
+	})
+}
+
+// addTarFile writes a single regular file to a tar archive
+func addTarFile(tw *tar.Writer, name string, content []byte, mode int64) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    mode,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
 // execCommand executes a command in the container and returns the output
 func (pm *PyThaiNLPManager) execCommand(ctx context.Context, dockerClient *client.Client, cmd []string) ([]byte, error) {
 	// Use bash to execute commands since the container might have Python as the main process
@@ -463,13 +905,22 @@ func (pm *PyThaiNLPManager) execCommand(ctx context.Context, dockerClient *clien
 	}
 	defer resp.Close()
 
-	output, err := io.ReadAll(resp.Reader)
-	if err != nil {
+	// Without a TTY stdout and stderr are multiplexed on the same stream
+	var output bytes.Buffer
+	if _, err := stdcopy.StdCopy(&output, &output, resp.Reader); err != nil {
 		return nil, err
 	}
 
-	Logger.Trace().Str("output", string(output)).Msg("Command output")
-	return output, nil
+	Logger.Trace().Str("output", output.String()).Msg("Command output")
+
+	inspect, err := dockerClient.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return output.Bytes(), err
+	}
+	if inspect.ExitCode != 0 {
+		return output.Bytes(), &execError{exitCode: inspect.ExitCode, output: output.String()}
+	}
+	return output.Bytes(), nil
 }
 
 // isServiceRunning checks if the Python service is responding
@@ -483,27 +934,79 @@ func (pm *PyThaiNLPManager) isServiceRunning(ctx context.Context) bool {
 	return err == nil && health.Status == "ready"
 }
 
-// waitForService waits for the Python service to be ready
+// containerOutdated reports whether the container exists with a service
+// port or token other than this manager's. Errors, such as a missing
+// container, report false and leave the creation to dockerutil.
+func (pm *PyThaiNLPManager) containerOutdated(ctx context.Context) bool {
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		return false
+	}
+	defer dockerClient.Close()
+
+	inspect, err := dockerClient.ContainerInspect(ctx, pm.containerName)
+	if err != nil || inspect.Config == nil {
+		return false
+	}
+	want := map[string]string{
+		"PYTHAINLP_SERVICE_PORT":  fmt.Sprintf("%d", pm.servicePort),
+		"PYTHAINLP_SERVICE_TOKEN": pm.token,
+	}
+	for _, kv := range inspect.Config.Env {
+		k, v, _ := strings.Cut(kv, "=")
+		if w, ok := want[k]; ok && v != w {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForService waits for the Python service to be ready. Each interval a
+// probe in the container tells whether server.py listens or keeps crashing,
+// and docker events report the container dying, so failures surface within
+// seconds rather than at the startup timeout.
 func (pm *PyThaiNLPManager) waitForService(ctx context.Context) error {
-	deadline := time.Now().Add(maxServiceWaitTime)
-	
-	attempt := 0
-	for time.Now().Before(deadline) {
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to get Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	died := pm.watchContainer(watchCtx, dockerClient)
+
+	timeout := time.NewTimer(pm.startupTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(pm.healthCheckInterval)
+	defer ticker.Stop()
+
+	for attempt := 1; ; attempt++ {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.After(serviceCheckInterval):
-			attempt++
-			Logger.Trace().Int("attempt", attempt).Msg("Health check attempt")
+		case err := <-died:
+			return err
+		case <-timeout.C:
+			return fmt.Errorf("service failed to start within %v: %w", pm.startupTimeout, ErrTimeout)
+		case <-ticker.C:
+		}
+
+		code, err := pm.probeService(ctx, dockerClient)
+		Logger.Trace().Int("attempt", attempt).Int("probe", code).Err(err).Msg("Readiness probe")
+		switch {
+		case err != nil, code == probeReady:
+			// Listening in the container does not mean reachable from here
 			if pm.isServiceRunning(ctx) {
 				Logger.Debug().Msg("Service is ready!")
 				return nil
 			}
-			Logger.Trace().Msg("Service not ready yet")
+		case code == probeCrashed:
+			if err := pm.checkCrashLoop(ctx, dockerClient); err != nil {
+				return err
+			}
 		}
 	}
-	
-	return fmt.Errorf("service failed to start within %v", maxServiceWaitTime)
 }
 
 // GetClient returns the HTTP client for making API calls
@@ -525,25 +1028,54 @@ func (pm *PyThaiNLPManager) IsLightweightMode() bool {
 	return pm.lightweightMode
 }
 
+// IsOfflineMode returns whether the manager is running in offline mode
+func (pm *PyThaiNLPManager) IsOfflineMode() bool {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.offline
+}
+
 // Stop stops the docker service
 func (pm *PyThaiNLPManager) Stop(ctx context.Context) error {
+	pm.stopIdleTimer()
 	pm.mu.Lock()
 	pm.serviceReady = false
 	pm.mu.Unlock()
+	pm.setHealth(HealthStopped)
 	
 	return pm.docker.Stop()
 }
 
 // Close implements io.Closer
 func (pm *PyThaiNLPManager) Close() error {
+	pm.stopIdleTimer()
 	pm.mu.Lock()
 	pm.serviceReady = false
 	pm.mu.Unlock()
+	pm.setHealth(HealthStopped)
+	pm.stopHealthMonitor()
 	
 	pm.logger.Close()
+	if pm.ephemeral {
+		return pm.docker.Down()
+	}
 	return pm.docker.Close()
 }
 
+// ProjectName returns the compose project name, unique for ephemeral instances
+func (pm *PyThaiNLPManager) ProjectName() string {
+	return pm.projectName
+}
+
+// randomSuffix returns a short random hex string for unique names
+func randomSuffix() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
 // Package-level functions for backward compatibility
 
 // getOrCreateDefaultManager returns or creates the default manager instance
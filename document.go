@@ -0,0 +1,156 @@
+package pythainlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Offsets of the document levels are byte offsets into the analyzed text, as
+// for Token: text[Start:End] is Text. Both are -1 when the piece could not be
+// found in the text, e.g. because an engine normalized it.
+
+// Paragraph is a line of the analyzed text and its sentences
+type Paragraph struct {
+	Text      string     `json:"text"`
+	Start     int        `json:"start"`
+	End       int        `json:"end"`
+	Sentences []Sentence `json:"sentences"`
+}
+
+// Sentence is a sentence of a paragraph and its words
+type Sentence struct {
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Words []Word `json:"words"`
+}
+
+// Word is a word of a sentence and its syllables. A word without Thai
+// characters is a single syllable.
+type Word struct {
+	Text      string     `json:"text"`
+	Start     int        `json:"start"`
+	End       int        `json:"end"`
+	Syllables []Syllable `json:"syllables"`
+}
+
+// Syllable is a syllable of a word
+type Syllable struct {
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// DocumentOptions selects the engines of AnalyzeDocument
+type DocumentOptions struct {
+	SentenceEngine SentenceEngine // Default crfcut
+	TokenizeEngine TokenizeEngine // Default newmm; EngineAuto is not supported
+	SyllableEngine SyllableEngine // Default han_solo
+}
+
+// DocumentResult is a text split into paragraphs, sentences, words and
+// syllables. Meta.Engine is the word engine; the sentence and syllable
+// engines are in Meta.Extra.
+type DocumentResult struct {
+	Paragraphs []Paragraph
+
+	Meta Metadata `json:"metadata"`
+}
+
+// AnalyzeDocument splits text into paragraphs at line breaks, then into
+// sentences, words and syllables, in a single request. Every level carries
+// its offsets in text so that a reader can map a syllable back to its
+// sentence, e.g. to highlight it. Whitespace is dropped at every level.
+func (pm *PyThaiNLPManager) AnalyzeDocument(ctx context.Context, text string, opts DocumentOptions) (*DocumentResult, error) {
+	if err := errors.Join(
+		opts.SentenceEngine.Validate(),
+		opts.TokenizeEngine.Validate(),
+		opts.SyllableEngine.Validate(),
+	); err != nil {
+		return nil, err
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+	if err := pm.client.checkText(text); err != nil {
+		return nil, err
+	}
+
+	req := &AnalyzeDocumentRequest{
+		Text:           text,
+		SentenceEngine: string(opts.SentenceEngine),
+		TokenizeEngine: string(opts.TokenizeEngine),
+		SyllableEngine: string(opts.SyllableEngine),
+	}
+	if req.SentenceEngine == "" {
+		req.SentenceEngine = string(EngineSentenceCRFCut)
+	}
+	if req.TokenizeEngine == "" {
+		req.TokenizeEngine = string(EngineNewMM)
+	}
+	if req.SyllableEngine == "" {
+		req.SyllableEngine = string(EngineSyllableHanSolo)
+	}
+
+	resp, err := pm.client.AnalyzeDocument(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("document analysis failed: %w", err)
+	}
+
+	alignDocument(text, resp.Paragraphs)
+	return &DocumentResult{
+		Paragraphs: resp.Paragraphs,
+		Meta:       newMetadata(resp.Metadata, req.TokenizeEngine),
+	}, nil
+}
+
+// alignDocument fills in the offsets of every level, searching each piece
+// after the previous one within its parent
+func alignDocument(text string, paragraphs []Paragraph) {
+	pos := 0
+	for i := range paragraphs {
+		p := &paragraphs[i]
+		p.Start, p.End, pos = locate(text, p.Text, pos, len(text))
+		at, limit := parentBounds(p.Start, p.End, pos, len(text))
+		for j := range p.Sentences {
+			s := &p.Sentences[j]
+			s.Start, s.End, at = locate(text, s.Text, at, limit)
+			wat, wlimit := parentBounds(s.Start, s.End, at, limit)
+			for k := range s.Words {
+				w := &s.Words[k]
+				w.Start, w.End, wat = locate(text, w.Text, wat, wlimit)
+				sat, slimit := parentBounds(w.Start, w.End, wat, wlimit)
+				for l := range w.Syllables {
+					y := &w.Syllables[l]
+					y.Start, y.End, sat = locate(text, y.Text, sat, slimit)
+				}
+			}
+		}
+	}
+}
+
+// locate finds piece in text[from:limit] and returns its offsets and where
+// to search for the next piece, or -1 offsets and from when it is missing
+func locate(text, piece string, from, limit int) (start, end, next int) {
+	if piece == "" || from > limit {
+		return -1, -1, from
+	}
+	i := strings.Index(text[from:limit], piece)
+	if i < 0 {
+		return -1, -1, from
+	}
+	start = from + i
+	end = start + len(piece)
+	return start, end, end
+}
+
+// parentBounds returns the range to search the children of a piece in: the
+// piece itself when it was found, otherwise the rest of its own parent
+func parentBounds(start, end, from, limit int) (int, int) {
+	if start < 0 {
+		return from, limit
+	}
+	return start, end
+}
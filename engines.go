@@ -0,0 +1,241 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// TokenizeEngine names a word tokenization engine
+type TokenizeEngine string
+
+// RomanizeEngine names a romanization engine
+type RomanizeEngine string
+
+// TransliterateEngine names a transliteration engine
+type TransliterateEngine string
+
+// SyllableEngine names a syllable tokenization engine
+type SyllableEngine string
+
+// SentenceEngine names a sentence segmentation engine
+type SentenceEngine string
+
+// LemmaEngine names a dependency parser providing lemmas
+type LemmaEngine string
+
+// Known engines, whether or not they are installed in the current image
+var (
+	tokenizeEngines = []TokenizeEngine{
+		EngineNewMM, EngineLongest, EngineICU, EngineAttaCut, EngineDeepCut,
+		EngineNerCut, EngineNLPO3, EngineOSKut, EngineSefrCut, EngineTLTK, EngineAuto,
+	}
+	romanizeEngines = []RomanizeEngine{
+		EngineRoyin, EngineThai2Rom, EngineThai2RomONNX, EngineTLTKRom, EngineLookup,
+	}
+	transliterateEngines = []TransliterateEngine{
+		EngineThaig2p, EngineICUTrans, EngineIPA, EngineTLTKG2P,
+		EngineISO11940, EngineTLTKIPA, EngineThaig2pV2,
+	}
+	syllableEngines = []SyllableEngine{
+		EngineSyllableDict, EngineSyllableHanSolo, EngineSyllableSSG, EngineSyllableTLTK,
+	}
+	sentenceEngines = []SentenceEngine{
+		EngineSentenceCRFCut, EngineSentenceWhitespaceNewline, EngineSentenceWhitespace,
+		EngineSentenceTLTK, EngineSentenceWtP,
+	}
+	lemmaEngines = []LemmaEngine{EngineLemmaEsupar, EngineLemmaSpacyThai}
+)
+
+// Validate returns an error wrapping ErrEngineUnavailable if the engine is
+// unknown. The empty engine selects the default and is valid.
+func (e TokenizeEngine) Validate() error {
+	return validateEngine("tokenize", e, tokenizeEngines)
+}
+
+// Validate returns an error wrapping ErrEngineUnavailable if the engine is
+// unknown. The empty engine selects the default and is valid.
+func (e RomanizeEngine) Validate() error {
+	return validateEngine("romanize", e, romanizeEngines)
+}
+
+// Validate returns an error wrapping ErrEngineUnavailable if the engine is
+// unknown. The empty engine selects the default and is valid.
+func (e TransliterateEngine) Validate() error {
+	return validateEngine("transliterate", e, transliterateEngines)
+}
+
+// Validate returns an error wrapping ErrEngineUnavailable if the engine is
+// unknown. The empty engine selects the default and is valid.
+func (e SyllableEngine) Validate() error {
+	return validateEngine("syllable", e, syllableEngines)
+}
+
+// Validate returns an error wrapping ErrEngineUnavailable if the engine is
+// unknown. The empty engine selects the default and is valid.
+func (e SentenceEngine) Validate() error {
+	return validateEngine("sentence", e, sentenceEngines)
+}
+
+// Validate returns an error wrapping ErrEngineUnavailable if the engine is
+// unknown. The empty engine uses normalized tokens only and is valid.
+func (e LemmaEngine) Validate() error {
+	return validateEngine("lemma", e, lemmaEngines)
+}
+
+func validateEngine[E ~string](kind string, e E, known []E) error {
+	if e == "" || slices.Contains(known, e) {
+		return nil
+	}
+	return fmt.Errorf("%w: unknown %s engine %q, expected one of %v", ErrEngineUnavailable, kind, e, known)
+}
+
+// EngineInfo describes one engine as reported by the service
+type EngineInfo struct {
+	Operation        string `json:"operation"`          // tokenize, romanize, transliterate, syllable, sentence, ner, reverse_transliterate, similarity or lemma
+	Name             string `json:"name"`               // Engine name as passed to the service
+	Available        bool   `json:"available"`          // Importable in the running container
+	RequiresFullMode bool   `json:"requires_full_mode"` // Dependencies are only installed in full mode
+	Corpus           string `json:"corpus,omitempty"`   // Corpus downloaded on first use, empty if bundled
+	ModelDownloaded  bool   `json:"model_downloaded"`   // Always true for engines with a bundled model
+	ModelSize        int64  `json:"model_size"`         // Bytes on disk once downloaded, otherwise an approximate download size
+}
+
+// ProbeStatus is the outcome of ProbeEngine
+type ProbeStatus string
+
+const (
+	ProbeAvailable      ProbeStatus = "available"       // The engine can be used now
+	ProbeMissingPackage ProbeStatus = "missing_package" // Its Python package is not installed, usually outside full mode
+	ProbeMissingModel   ProbeStatus = "missing_model"   // Its model is not downloaded yet
+	ProbeLoadFailed     ProbeStatus = "load_failed"     // Its package or model failed to load
+)
+
+// EngineProbe is what ProbeEngine found out about an engine
+type EngineProbe struct {
+	Operation        string      `json:"operation"`
+	Engine           string      `json:"engine"`
+	Status           ProbeStatus `json:"status"`
+	RequiresFullMode bool        `json:"requires_full_mode"`
+	Package          string      `json:"package,omitempty"` // Pip package the engine needs, if any
+	Corpus           string      `json:"corpus,omitempty"`  // Model the engine downloads on first use, if any
+	ModelSize        int64       `json:"model_size"`        // Bytes on disk, or the approximate download size with ProbeMissingModel
+	Loaded           bool        `json:"loaded"`            // Whether the engine is now loaded in the service
+	Message          string      `json:"message,omitempty"` // The import or load error
+}
+
+// EngineReport lists every known engine
+type EngineReport struct {
+	Engines []EngineInfo
+}
+
+// Get returns the engine for the operation, or nil if it is unknown
+func (r *EngineReport) Get(operation, name string) *EngineInfo {
+	for i := range r.Engines {
+		if r.Engines[i].Operation == operation && r.Engines[i].Name == name {
+			return &r.Engines[i]
+		}
+	}
+	return nil
+}
+
+// IsAvailable reports whether the engine can be used in the running container
+func (r *EngineReport) IsAvailable(operation, name string) bool {
+	e := r.Get(operation, name)
+	return e != nil && e.Available
+}
+
+// availableNames returns the available engines for an operation
+func (r *EngineReport) availableNames(operation string) []string {
+	var names []string
+	for _, e := range r.Engines {
+		if e.Operation == operation && e.Available {
+			names = append(names, e.Name)
+		}
+	}
+	return names
+}
+
+// Capabilities lists the engines importable in the running container, which
+// depends on lightweight or full mode
+type Capabilities struct {
+	Tokenize      []TokenizeEngine
+	Romanize      []RomanizeEngine
+	Transliterate []TransliterateEngine
+	Syllable      []SyllableEngine
+	Sentence      []SentenceEngine
+	Lemma         []LemmaEngine
+
+	// ReverseTransliterate reports whether wunsen is installed (full mode)
+	ReverseTransliterate bool
+	// Similarity reports whether sentence-transformers is installed (full mode)
+	Similarity bool
+}
+
+// SupportsTokenize reports whether the tokenization engine is available
+func (c *Capabilities) SupportsTokenize(e TokenizeEngine) bool {
+	return slices.Contains(c.Tokenize, e)
+}
+
+// SupportsRomanize reports whether the romanization engine is available
+func (c *Capabilities) SupportsRomanize(e RomanizeEngine) bool {
+	return slices.Contains(c.Romanize, e)
+}
+
+// SupportsTransliterate reports whether the transliteration engine is available
+func (c *Capabilities) SupportsTransliterate(e TransliterateEngine) bool {
+	return slices.Contains(c.Transliterate, e)
+}
+
+// SupportsSyllable reports whether the syllable engine is available
+func (c *Capabilities) SupportsSyllable(e SyllableEngine) bool {
+	return slices.Contains(c.Syllable, e)
+}
+
+// SupportsSentence reports whether the sentence engine is available
+func (c *Capabilities) SupportsSentence(e SentenceEngine) bool {
+	return slices.Contains(c.Sentence, e)
+}
+
+// SupportsLemma reports whether the lemma engine is available
+func (c *Capabilities) SupportsLemma(e LemmaEngine) bool {
+	return slices.Contains(c.Lemma, e)
+}
+
+// Capabilities returns the engines the service detected at startup
+func (pm *PyThaiNLPManager) Capabilities(ctx context.Context) (*Capabilities, error) {
+	report, err := pm.GetSupportedEngines(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Capabilities{
+		Tokenize:      toEngines[TokenizeEngine](report.availableNames("tokenize")),
+		Romanize:      toEngines[RomanizeEngine](report.availableNames("romanize")),
+		Transliterate: toEngines[TransliterateEngine](report.availableNames("transliterate")),
+		Syllable:      toEngines[SyllableEngine](report.availableNames("syllable")),
+		Sentence:      toEngines[SentenceEngine](report.availableNames("sentence")),
+		Lemma:         toEngines[LemmaEngine](report.availableNames("lemma")),
+
+		ReverseTransliterate: report.IsAvailable("reverse_transliterate", "wunsen"),
+		Similarity:           report.IsAvailable("similarity", "sentence_transformers"),
+	}, nil
+}
+
+// GetCapabilities returns the engines available in the default instance
+func GetCapabilities() (*Capabilities, error) {
+	ctx := context.Background()
+	mgr, err := getOrCreateDefaultManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mgr.Capabilities(ctx)
+}
+
+func toEngines[E ~string](names []string) []E {
+	engines := make([]E, len(names))
+	for i, name := range names {
+		engines[i] = E(name)
+	}
+	return engines
+}
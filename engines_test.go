@@ -0,0 +1,40 @@
+package pythainlp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSentenceEngineValidate(t *testing.T) {
+	for _, e := range []SentenceEngine{"", EngineSentenceCRFCut, EngineSentenceWhitespaceNewline, EngineSentenceWhitespace, EngineSentenceTLTK, EngineSentenceWtP} {
+		if err := e.Validate(); err != nil {
+			t.Errorf("Validate(%q) = %v, want nil", e, err)
+		}
+	}
+	if err := SentenceEngine("punkt").Validate(); !errors.Is(err, ErrEngineUnavailable) {
+		t.Errorf("Validate(punkt) = %v, want ErrEngineUnavailable", err)
+	}
+}
+
+func TestWarmEnginesSentence(t *testing.T) {
+	spec, err := WarmEngines{
+		Tokenize: []TokenizeEngine{EngineNewMM},
+		Sentence: []SentenceEngine{EngineSentenceCRFCut},
+	}.spec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "tokenize/newmm,sentence/crfcut"; spec != want {
+		t.Errorf("spec = %q, want %q", spec, want)
+	}
+
+	if _, err := (WarmEngines{Sentence: []SentenceEngine{"punkt"}}).spec(); !errors.Is(err, ErrEngineUnavailable) {
+		t.Errorf("spec with unknown engine = %v, want ErrEngineUnavailable", err)
+	}
+}
+
+func TestWarmEnginesAuto(t *testing.T) {
+	if _, err := (WarmEngines{Tokenize: []TokenizeEngine{EngineAuto}}).spec(); !errors.Is(err, ErrEngineUnavailable) {
+		t.Errorf("spec with EngineAuto = %v, want ErrEngineUnavailable", err)
+	}
+}
@@ -0,0 +1,153 @@
+package pythainlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/client"
+	"github.com/tassa-yoniso-manasi-karoto/dockerutil"
+)
+
+const (
+	// minAPIVersion is the oldest Docker API the compose project is tested against (Docker 20.10)
+	minAPIVersion = "1.41"
+
+	// Rough requirements covering the image plus downloaded corpora
+	minDiskLightweight   = 2 << 30
+	minDiskFull          = 8 << 30
+	minMemoryLightweight = 1 << 30
+	minMemoryFull        = 4 << 30
+
+	envPingTimeout = 5 * time.Second
+)
+
+// Errors returned by CheckEnvironment, wrapped with details on how to fix them
+var (
+	ErrDockerNotRunning        = errors.New("Docker daemon is not reachable")
+	ErrDockerAPITooOld         = errors.New("Docker API version is too old")
+	ErrUnsupportedArchitecture = errors.New("no published image for this architecture")
+	ErrInsufficientDisk        = errors.New("insufficient free disk space")
+	ErrInsufficientMemory      = errors.New("insufficient memory available to Docker")
+)
+
+// EnvReport describes the Docker environment as seen by CheckEnvironment
+type EnvReport struct {
+	DaemonReachable bool
+	ServerVersion   string
+	APIVersion      string
+	OS              string // e.g. "Docker Desktop" or "Ubuntu 24.04 LTS"
+	Architecture    string // Daemon architecture, normalized to GOARCH naming
+	ImagePresent    bool   // Whether the service image is already in the local cache
+	FreeDisk        uint64 // Free bytes on the data directory's filesystem, 0 if unknown
+	MemoryTotal     int64  // Memory available to the daemon (the VM on Docker Desktop)
+	NCPU            int
+	Backend         *BackendInfo // nil if detection failed
+	Problems        []error      // Each wraps one of the Err* sentinels
+}
+
+// OK reports whether no problem was found
+func (r *EnvReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// CheckEnvironment verifies that Docker can run the service before Init is
+// called. The report is always returned; the error joins all problems found
+// so callers can test for a cause with errors.Is.
+func (pm *PyThaiNLPManager) CheckEnvironment(ctx context.Context) (*EnvReport, error) {
+	report := &EnvReport{}
+
+	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		report.Problems = append(report.Problems, fmt.Errorf("%w: failed to create Docker client: %v", ErrDockerNotRunning, err))
+		return report, errors.Join(report.Problems...)
+	}
+	defer dockerClient.Close()
+
+	pingCtx, cancel := context.WithTimeout(ctx, envPingTimeout)
+	ping, err := dockerClient.Ping(pingCtx)
+	cancel()
+	if err != nil {
+		report.Problems = append(report.Problems, fmt.Errorf("%w: start %s and retry: %v", ErrDockerNotRunning, dockerutil.DockerBackendName(), err))
+		return report, errors.Join(report.Problems...)
+	}
+	report.DaemonReachable = true
+	report.APIVersion = ping.APIVersion
+
+	if report.APIVersion != "" && versions.LessThan(report.APIVersion, minAPIVersion) {
+		report.Problems = append(report.Problems, fmt.Errorf("%w: daemon supports API %s, need %s or later; upgrade Docker",
+			ErrDockerAPITooOld, report.APIVersion, minAPIVersion))
+	}
+
+	info, err := dockerClient.Info(ctx)
+	if err != nil {
+		report.Problems = append(report.Problems, fmt.Errorf("%w: failed to query daemon info: %v", ErrDockerNotRunning, err))
+		return report, errors.Join(report.Problems...)
+	}
+	report.ServerVersion = info.ServerVersion
+	report.OS = info.OperatingSystem
+	report.Architecture = normalizeArch(info.Architecture)
+	report.MemoryTotal = info.MemTotal
+	report.NCPU = info.NCPU
+
+	backendName := dockerutil.DockerBackendName()
+	if backend, err := pm.Backend(ctx); err == nil {
+		report.Backend = backend
+		backendName = string(backend.Backend)
+	}
+
+	if _, err := dockerClient.ImageInspect(ctx, pm.image); err == nil {
+		report.ImagePresent = true
+	}
+
+	// A locally available image, loaded or built, runs regardless of what is published
+	if !report.ImagePresent && !pm.buildFromSource && report.Architecture != "amd64" && report.Architecture != "arm64" {
+		report.Problems = append(report.Problems, fmt.Errorf("%w: daemon is %s; use WithBuildFromSource to build the image locally",
+			ErrUnsupportedArchitecture, info.Architecture))
+	}
+
+	minDisk, minMemory := uint64(minDiskLightweight), int64(minMemoryLightweight)
+	if !pm.lightweightMode {
+		minDisk, minMemory = minDiskFull, minMemoryFull
+	}
+
+	if free, err := freeDiskSpace(pm.dataDir); err != nil {
+		Logger.Debug().Err(err).Str("path", pm.dataDir).Msg("Could not determine free disk space")
+	} else {
+		report.FreeDisk = free
+		if free < minDisk {
+			report.Problems = append(report.Problems, fmt.Errorf("%w: %d MB free in %s, need about %d MB",
+				ErrInsufficientDisk, free>>20, pm.dataDir, minDisk>>20))
+		}
+	}
+
+	if report.MemoryTotal > 0 && report.MemoryTotal < minMemory {
+		report.Problems = append(report.Problems, fmt.Errorf("%w: daemon has %d MB, need about %d MB; raise the memory limit of %s",
+			ErrInsufficientMemory, report.MemoryTotal>>20, minMemory>>20, backendName))
+	}
+
+	return report, errors.Join(report.Problems...)
+}
+
+// CheckEnvironment verifies the Docker environment for the default manager
+func CheckEnvironment(ctx context.Context) (*EnvReport, error) {
+	mgr, err := getOrCreateDefaultManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mgr.CheckEnvironment(ctx)
+}
+
+// normalizeArch maps the daemon's uname-style architecture to GOARCH naming
+func normalizeArch(arch string) string {
+	switch arch {
+	case "x86_64":
+		return "amd64"
+	case "aarch64":
+		return "arm64"
+	default:
+		return arch
+	}
+}
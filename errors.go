@@ -0,0 +1,129 @@
+package pythainlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// Errors for use with errors.Is. Service errors returned by the Python side
+// (*ServiceError) wrap the matching sentinel, so callers do not need to
+// compare error codes or messages.
+var (
+	// ErrServiceNotReady is returned when a call is made before Init succeeded or after Stop/Close
+	ErrServiceNotReady = errors.New("service not ready")
+	// ErrEngineUnavailable is returned for an unknown engine or one not installed in the container
+	ErrEngineUnavailable = errors.New("engine unavailable")
+	// ErrModelNotDownloaded is returned when a corpus or model is missing and cannot be downloaded
+	ErrModelNotDownloaded = errors.New("model not downloaded")
+	// ErrTimeout is returned when a request or the service startup exceeds its deadline
+	ErrTimeout = errors.New("timed out")
+	// ErrContainerCrashed is returned when the service stops accepting connections,
+	// usually because the Python process or its container died
+	ErrContainerCrashed = errors.New("service container crashed")
+	// ErrProtocolMismatch is returned when the service speaks a different API
+	// version than this library, e.g. because an old image is cached
+	ErrProtocolMismatch = errors.New("service protocol version mismatch")
+	// ErrLimitExceeded is returned for a text, batch or request larger than
+	// the service accepts, see ServiceLimits
+	ErrLimitExceeded = errors.New("service limit exceeded")
+)
+
+// LimitError reports a call over one of the ServiceLimits, detected before
+// sending it
+type LimitError struct {
+	Limit string // "text_length", "batch_size" or "request_bytes"
+	Size  int64
+	Max   int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s %d exceeds the service maximum of %d", e.Limit, e.Size, e.Max)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// PythonException is an exception raised by the Python service while
+// handling a request, reported as an INTERNAL_ERROR. Get it from the error
+// of a call with errors.As, or from the *ServiceError with Exception.
+type PythonException struct {
+	Type      string // Exception class, qualified with its module unless builtin, e.g. "KeyError" or "torch.OutOfMemoryError"
+	Message   string
+	Traceback string // Formatted as Python prints it, possibly shortened (WithTracebackLimit) or empty
+}
+
+func (e *PythonException) Error() string {
+	return e.Type + ": " + e.Message
+}
+
+// Exception returns the Python exception behind an INTERNAL_ERROR, or nil
+func (e ServiceError) Exception() *PythonException {
+	name, _ := e.Details["exception"].(string)
+	if e.Code != "INTERNAL_ERROR" || name == "" {
+		return nil
+	}
+	tb, _ := e.Details["traceback"].(string)
+	return &PythonException{Type: name, Message: e.Message, Traceback: tb}
+}
+
+// Unwrap maps the service error code to its sentinel error
+func (e ServiceError) Unwrap() error {
+	switch e.Code {
+	case "INVALID_ENGINE":
+		return ErrEngineUnavailable
+	case "MODEL_MISSING":
+		return ErrModelNotDownloaded
+	case "PROTOCOL_MISMATCH":
+		return ErrProtocolMismatch
+	case "TEXT_TOO_LONG", "BATCH_TOO_LARGE", "REQUEST_TOO_LARGE":
+		return ErrLimitExceeded
+	}
+	return nil
+}
+
+// exceptionError is returned for an INTERNAL_ERROR carrying a Python
+// exception: it is the *ServiceError, which it unwraps to, and errors.As
+// also finds its *PythonException
+type exceptionError struct {
+	*ServiceError
+	exception *PythonException
+}
+
+func (e *exceptionError) Unwrap() error {
+	return e.ServiceError
+}
+
+func (e *exceptionError) As(target any) bool {
+	if exc, ok := target.(**PythonException); ok {
+		*exc = e.exception
+		return true
+	}
+	return false
+}
+
+// serviceErr returns the error of a call the service answered with e
+func serviceErr(e *ServiceError) error {
+	if exc := e.Exception(); exc != nil {
+		return &exceptionError{ServiceError: e, exception: exc}
+	}
+	return e
+}
+
+// classifyRequestError wraps a transport error with ErrTimeout or
+// ErrContainerCrashed when it matches one of them
+func classifyRequestError(err error) error {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	case errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNRESET):
+		return fmt.Errorf("%w: %w", ErrContainerCrashed, err)
+	}
+	return err
+}
@@ -0,0 +1,46 @@
+package pythainlp
+
+import "time"
+
+// FallbackPolicy lets a request fall back to a fast dictionary engine while
+// the requested engine is still loading, so that the first call to a slow,
+// cold-loading neural engine does not block an interactive UI. The requested
+// engine keeps loading in the service and is used as soon as it is ready.
+// The Engine method of results reports the engine that actually ran.
+type FallbackPolicy struct {
+	// LoadBudget is how long a request waits for the requested engine to
+	// load before using the fallback engine. With zero, the fallback is used
+	// whenever the requested engine is not loaded yet.
+	LoadBudget time.Duration
+
+	// Fallback engines, defaulting to newmm, royin and iso_11940
+	Tokenize      TokenizeEngine
+	Romanize      RomanizeEngine
+	Transliterate TransliterateEngine
+}
+
+// WithEngineFallback applies policy to tokenization, romanization and
+// transliteration requests
+func WithEngineFallback(policy FallbackPolicy) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		if policy.Tokenize == "" {
+			policy.Tokenize = EngineNewMM
+		}
+		if policy.Romanize == "" {
+			policy.Romanize = EngineRoyin
+		}
+		if policy.Transliterate == "" {
+			policy.Transliterate = EngineISO11940
+		}
+		pm.fallback = &policy
+	}
+}
+
+// loadBudgetMs returns the load budget of the fallback policy, or 0 if there
+// is none
+func (pm *PyThaiNLPManager) loadBudgetMs() int64 {
+	if pm.fallback == nil {
+		return 0
+	}
+	return max(pm.fallback.LoadBudget.Milliseconds(), 1)
+}
@@ -0,0 +1,45 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+)
+
+// EnableFullMode installs the full requirements into the running container
+// and restarts the service, making the neural engines available without
+// recreating the manager. Progress is reported per package through the
+// download progress callback. Like UpgradeDependencies, the installation is
+// lost when the container is recreated. It returns the refreshed engine report.
+func (pm *PyThaiNLPManager) EnableFullMode(ctx context.Context) (*EngineReport, error) {
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+	if !pm.IsLightweightMode() {
+		return pm.GetSupportedEngines(ctx)
+	}
+	if pm.offline {
+		return nil, fmt.Errorf("cannot install full mode dependencies in offline mode")
+	}
+
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	Logger.Info().Msg("Installing full mode requirements")
+	if err := pm.installPackages(ctx, dockerClient, requirementLines(fullRequirements), false); err != nil {
+		return nil, err
+	}
+
+	pm.mu.Lock()
+	pm.lightweightMode = false
+	pm.mu.Unlock()
+
+	// Engines are detected when server.py starts
+	if err := pm.restartService(ctx, dockerClient); err != nil {
+		return nil, fmt.Errorf("service failed to restart in full mode: %w", err)
+	}
+
+	return pm.GetSupportedEngines(ctx)
+}
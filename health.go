@@ -0,0 +1,152 @@
+package pythainlp
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// HealthState is the state of the service as seen by the manager
+type HealthState string
+
+const (
+	HealthStopped    HealthState = "stopped"    // Not started yet, stopped or closed
+	HealthStarting   HealthState = "starting"   // Being started by Init or a lazy start
+	HealthReady      HealthState = "ready"      // Answering requests
+	HealthUnhealthy  HealthState = "unhealthy"  // Stopped answering health checks, or failed to restart
+	HealthRecovering HealthState = "recovering" // Restarting after a crash, a reload or an upgrade
+)
+
+// healthMonitorInterval is how often OnHealthChange checks the service
+const healthMonitorInterval = 5 * time.Second
+
+// healthMonitor notifies the listeners of OnHealthChange from its own
+// goroutine, so that a slow listener never holds up the manager
+type healthMonitor struct {
+	mu        sync.Mutex
+	state     HealthState
+	listeners []func(HealthState)
+	pending   []HealthState
+	wake      chan struct{}
+	stop      chan struct{}
+}
+
+// OnHealthChange calls fn whenever the service goes from one HealthState to
+// another, e.g. ready, then unhealthy when server.py crashes, then recovering
+// while the supervisor restarts it and ready again. While a listener is
+// registered the manager checks the service every few seconds. Listeners are
+// called one at a time, in the order of the changes, from a goroutine of
+// their own; they may call the manager.
+func (pm *PyThaiNLPManager) OnHealthChange(fn func(state HealthState)) {
+	pm.health.mu.Lock()
+	defer pm.health.mu.Unlock()
+	pm.health.listeners = append(pm.health.listeners, fn)
+	if pm.health.stop == nil {
+		pm.health.wake = make(chan struct{}, 1)
+		pm.health.stop = make(chan struct{})
+		go pm.monitorHealth(pm.health.wake, pm.health.stop)
+	}
+}
+
+// Health returns the current HealthState of the service
+func (pm *PyThaiNLPManager) Health() HealthState {
+	pm.health.mu.Lock()
+	defer pm.health.mu.Unlock()
+	if pm.health.state == "" {
+		return HealthStopped
+	}
+	return pm.health.state
+}
+
+// setHealth records a change of state for the listeners of OnHealthChange
+func (pm *PyThaiNLPManager) setHealth(state HealthState) {
+	pm.health.mu.Lock()
+	defer pm.health.mu.Unlock()
+	if state == pm.health.state || (state == HealthStopped && pm.health.state == "") {
+		return
+	}
+	Logger.Debug().Str("from", string(pm.health.state)).Str("to", string(state)).Msg("Service health changed")
+	pm.health.state = state
+	if pm.health.stop == nil {
+		return
+	}
+	pm.health.pending = append(pm.health.pending, state)
+	select {
+	case pm.health.wake <- struct{}{}:
+	default:
+	}
+}
+
+// stopHealthMonitor ends the goroutine started by OnHealthChange
+func (pm *PyThaiNLPManager) stopHealthMonitor() {
+	pm.health.mu.Lock()
+	defer pm.health.mu.Unlock()
+	if pm.health.stop != nil {
+		close(pm.health.stop)
+		pm.health.stop = nil
+	}
+}
+
+func (pm *PyThaiNLPManager) monitorHealth(wake, stop chan struct{}) {
+	ticker := time.NewTicker(healthMonitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-wake:
+		case <-ticker.C:
+			pm.checkHealth()
+		}
+
+		pm.health.mu.Lock()
+		pending := pm.health.pending
+		pm.health.pending = nil
+		listeners := slices.Clone(pm.health.listeners)
+		pm.health.mu.Unlock()
+		for _, state := range pending {
+			for _, fn := range listeners {
+				fn(state)
+			}
+		}
+	}
+}
+
+// checkHealth detects the service crashing and coming back. Startups are
+// left to beginStartup and endStartup.
+func (pm *PyThaiNLPManager) checkHealth() {
+	pm.startupMu.Lock()
+	starting := pm.startupActive
+	pm.startupMu.Unlock()
+	if starting {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthMonitorInterval)
+	defer cancel()
+	switch state := pm.Health(); state {
+	case HealthReady:
+		if !pm.isServiceRunning(ctx) {
+			pm.setHealth(HealthUnhealthy)
+		}
+	case HealthUnhealthy, HealthRecovering:
+		if pm.isServiceRunning(ctx) {
+			pm.setHealth(HealthReady)
+			return
+		}
+		if state == HealthRecovering {
+			return
+		}
+		// The supervisor restarts a crashed server.py, which then reports
+		// its startup again
+		dockerClient, err := pm.docker.GetClient()
+		if err != nil {
+			return
+		}
+		defer dockerClient.Close()
+		if code, err := pm.probeService(ctx, dockerClient); err == nil && code == probeStarting {
+			pm.setHealth(HealthRecovering)
+		}
+	}
+}
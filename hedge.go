@@ -0,0 +1,198 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// HedgeOptions configures WithHedging
+type HedgeOptions struct {
+	// Percentile of the recent latencies of an endpoint after which a request
+	// still waiting is sent again (default 0.99)
+	Percentile float64
+	// MinDelay is the least delay before sending a duplicate (default 10ms)
+	MinDelay time.Duration
+	// MinSamples is how many latencies an endpoint needs before its requests
+	// are hedged (default 20)
+	MinSamples int
+}
+
+// hedgeWindow is how many latencies are kept per endpoint
+const hedgeWindow = 200
+
+// hedgedPaths are the endpoints safe to send twice: they only compute a
+// result from the request
+var hedgedPaths = []string{
+	"/tokenize", "/tokenize_batch", "/romanize", "/romanize_batch", "/transliterate",
+	"/syllable_tokenize", "/syllable_phonetics", "/analyze", "/analyze_document",
+	"/paragraph_tokenize", "/ner", "/person_names", "/preprocess", "/reverse_transliterate",
+	"/similarity", "/search_terms", "/word_difficulty",
+}
+
+// WithHedging sends a duplicate of a request that takes longer than most
+// recent ones to the same endpoint (see HedgeOptions.Percentile) and returns
+// whichever response comes first, cancelling the other. It trims the long
+// tail of latencies, e.g. when one worker is stuck loading a model, at the
+// cost of some extra work. Hedging needs several service workers
+// (WithServiceWorkers), as a single one would queue the duplicate behind the
+// request, and is disabled without them. Only the text-processing endpoints
+// are hedged, not plugins.
+func WithHedging(opts HedgeOptions) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		if opts.Percentile <= 0 || opts.Percentile >= 1 {
+			opts.Percentile = 0.99
+		}
+		if opts.MinDelay <= 0 {
+			opts.MinDelay = 10 * time.Millisecond
+		}
+		if opts.MinSamples <= 0 {
+			opts.MinSamples = 20
+		}
+		pm.hedging = &hedgingTransport{opts: opts, latencies: make(map[string][]time.Duration)}
+	}
+}
+
+// hedgingTransport hedges the requests passing through it
+type hedgingTransport struct {
+	opts HedgeOptions
+	next http.RoundTripper
+
+	mu        sync.Mutex
+	latencies map[string][]time.Duration // The last hedgeWindow of each endpoint, oldest first
+}
+
+// attempt is the outcome of one of the copies of a hedged request
+type attempt struct {
+	id      int // Index of its context's cancel function
+	resp    *http.Response
+	err     error
+	elapsed time.Duration
+}
+
+func (t *hedgingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := req.URL.Path
+	if req.Method != http.MethodPost || req.GetBody == nil || !slices.Contains(hedgedPaths, path) {
+		return t.next.RoundTrip(req)
+	}
+	delay, ok := t.delay(path)
+	if !ok {
+		start := time.Now()
+		resp, err := t.next.RoundTrip(req)
+		if err == nil {
+			t.record(path, time.Since(start))
+		}
+		return resp, err
+	}
+
+	results := make(chan attempt, 2)
+	var cancels []context.CancelFunc
+	send := func(r *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		id := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			start := time.Now()
+			a := attempt{id: id}
+			// A panic in a transport would otherwise crash the program from
+			// a goroutine the caller cannot recover
+			defer func() {
+				if p := recover(); p != nil {
+					a.resp, a.err = nil, fmt.Errorf("hedged request panicked: %v", p)
+				}
+				a.elapsed = time.Since(start)
+				results <- a
+			}()
+			a.resp, a.err = t.next.RoundTrip(r.WithContext(ctx))
+		}()
+	}
+
+	send(req)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	pending := 1
+	for {
+		select {
+		case <-timer.C:
+			body, err := req.GetBody()
+			if err != nil {
+				continue
+			}
+			dup := req.Clone(req.Context())
+			dup.Body = body
+			send(dup)
+			pending++
+			Logger.Debug().Str("path", path).Dur("after", delay).Msg("Hedging slow request")
+		case a := <-results:
+			pending--
+			if a.err != nil && pending > 0 {
+				cancels[a.id]()
+				continue
+			}
+			// The other copy, if any, lost the race
+			for id, cancel := range cancels {
+				if id != a.id {
+					cancel()
+				}
+			}
+			if pending > 0 {
+				go closeLoser(results)
+			}
+			if a.err != nil {
+				cancels[a.id]()
+				return nil, a.err
+			}
+			t.record(path, a.elapsed)
+			a.resp.Body = &cancelOnClose{ReadCloser: a.resp.Body, cancel: cancels[a.id]}
+			return a.resp, nil
+		}
+	}
+}
+
+// closeLoser closes the response of the copy of a request that lost the race
+func closeLoser(results <-chan attempt) {
+	if a := <-results; a.resp != nil {
+		a.resp.Body.Close()
+	}
+}
+
+// cancelOnClose releases the context of a winning request once its body is
+// read
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// delay returns how long to wait before hedging a request to path, and false
+// while too few of its latencies are known
+func (t *hedgingTransport) delay(path string) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	window := t.latencies[path]
+	if len(window) < t.opts.MinSamples {
+		return 0, false
+	}
+	sorted := slices.Sorted(slices.Values(window))
+	p := sorted[min(int(float64(len(sorted))*t.opts.Percentile), len(sorted)-1)]
+	return max(p, t.opts.MinDelay), true
+}
+
+// record adds the latency of a successful request to path
+func (t *hedgingTransport) record(path string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	window := append(t.latencies[path], d)
+	if len(window) > hedgeWindow {
+		window = window[len(window)-hedgeWindow:]
+	}
+	t.latencies[path] = window
+}
@@ -0,0 +1,66 @@
+package pythainlp
+
+import (
+	"time"
+)
+
+// WithIdleTimeout stops the container after d without requests, releasing the
+// memory held by loaded models. A request running longer than d keeps it up
+// until it completes. The container is kept, and the next request starts it
+// again transparently, blocking until the service is ready.
+func WithIdleTimeout(d time.Duration) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.idleTimeout = d
+	}
+}
+
+// touch records activity and reschedules the idle shutdown
+func (pm *PyThaiNLPManager) touch() {
+	if pm.idleTimeout <= 0 {
+		return
+	}
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.lastActivity = time.Now()
+	if pm.idleTimer == nil {
+		pm.idleTimer = time.AfterFunc(pm.idleTimeout, pm.stopIfIdle)
+	} else {
+		pm.idleTimer.Reset(pm.idleTimeout)
+	}
+}
+
+// stopIfIdle stops the containers unless a request arrived meanwhile or is
+// still running, in which case the shutdown is postponed by a full timeout
+func (pm *PyThaiNLPManager) stopIfIdle() {
+	pm.mu.Lock()
+	if !pm.serviceReady || time.Since(pm.lastActivity) < pm.idleTimeout {
+		pm.mu.Unlock()
+		return
+	}
+	if pm.client.active.Load() > 0 {
+		pm.lastActivity = time.Now()
+		pm.idleTimer.Reset(pm.idleTimeout)
+		pm.mu.Unlock()
+		return
+	}
+	pm.serviceReady = false
+	pm.idleStopped = true
+	pm.mu.Unlock()
+	pm.setHealth(HealthStopped)
+
+	Logger.Info().Dur("idle", pm.idleTimeout).Msg("Stopping idle service")
+	if err := pm.docker.Stop(); err != nil {
+		Logger.Warn().Err(err).Msg("Failed to stop idle service")
+	}
+}
+
+// stopIdleTimer cancels a pending idle shutdown
+func (pm *PyThaiNLPManager) stopIdleTimer() {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	if pm.idleTimer != nil {
+		pm.idleTimer.Stop()
+	}
+	pm.idleStopped = false
+}
@@ -0,0 +1,132 @@
+package pythainlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
+const (
+	// Files written by supervisorScript inside the container
+	serviceLogPath    = "/tmp/pythainlp-service.log"
+	serviceStatusPath = "/tmp/pythainlp-service.status"
+
+	// Written by server.py while it starts, see probeService
+	startupStatusPath = "/tmp/pythainlp-startup.json"
+
+	// crashLoopRestarts is how many consecutive exits during startup are
+	// treated as a permanent failure
+	crashLoopRestarts = 3
+
+	// installLogLines is how many output lines an InstallError keeps
+	installLogLines = 20
+)
+
+// InstallError reports a dependency or model installation that failed inside
+// the container, either while running pip or while the service imported its
+// modules at startup
+type InstallError struct {
+	Package  string   // Requirement, Python module or model that failed, if known
+	ExitCode int      // Exit code of the failing command
+	Log      []string // Last lines of its output
+	Err      error
+}
+
+func (e *InstallError) Error() string {
+	msg := "installation failed"
+	if e.Package != "" {
+		msg = fmt.Sprintf("failed to install %s", e.Package)
+	}
+	msg = fmt.Sprintf("%s (exit code %d)", msg, e.ExitCode)
+	if len(e.Log) > 0 {
+		msg += ": " + e.Log[len(e.Log)-1]
+	}
+	return msg
+}
+
+func (e *InstallError) Unwrap() error {
+	return e.Err
+}
+
+// execError is returned by execCommand when the command exits with a non-zero code
+type execError struct {
+	exitCode int
+	output   string
+}
+
+func (e *execError) Error() string {
+	return fmt.Sprintf("command exited with code %d: %s", e.exitCode, strings.TrimSpace(e.output))
+}
+
+// newInstallError wraps a failed pip command for pkg into an InstallError
+func newInstallError(pkg string, err error) error {
+	installErr := &InstallError{Package: pkg, Err: err}
+	var execErr *execError
+	if errors.As(err, &execErr) {
+		installErr.ExitCode = execErr.exitCode
+		installErr.Log = lastLines(execErr.output, installLogLines)
+	}
+	return installErr
+}
+
+// checkCrashLoop returns an InstallError when server.py keeps exiting during
+// startup, which happens when a dependency or model fails to load
+func (pm *PyThaiNLPManager) checkCrashLoop(ctx context.Context, dockerClient *client.Client) error {
+	status, err := pm.execCommand(ctx, dockerClient, []string{"cat", serviceStatusPath})
+	if err != nil {
+		// No exit recorded yet
+		return nil
+	}
+
+	var restarts, exitCode int
+	if _, err := fmt.Sscanf(string(status), "%d %d", &restarts, &exitCode); err != nil || restarts < crashLoopRestarts {
+		return nil
+	}
+
+	output, _ := pm.execCommand(ctx, dockerClient, []string{"tail", "-n", fmt.Sprint(installLogLines * 2), serviceLogPath})
+	log := lastLines(string(output), installLogLines)
+	Logger.Error().Int("restarts", restarts).Int("exit_code", exitCode).Strs("log", log).Msg("Service keeps exiting during startup")
+
+	return &InstallError{
+		Package:  failingModule(log),
+		ExitCode: exitCode,
+		Log:      log,
+		Err:      fmt.Errorf("server.py exited %d times during startup: %w", restarts, ErrContainerCrashed),
+	}
+}
+
+var (
+	missingModuleRe = regexp.MustCompile(`No module named '([^']+)'`)
+	corpusRe        = regexp.MustCompile(`[Cc]orpus:? '?([\w.-]+)'?`)
+)
+
+// failingModule extracts the missing module or corpus from a Python traceback
+func failingModule(log []string) string {
+	for i := len(log) - 1; i >= 0; i-- {
+		if m := missingModuleRe.FindStringSubmatch(log[i]); m != nil {
+			return m[1]
+		}
+		if m := corpusRe.FindStringSubmatch(log[i]); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// lastLines returns the last n non-empty lines of s
+func lastLines(s string, n int) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
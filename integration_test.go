@@ -3,6 +3,7 @@ package pythainlp_test
 import (
 	"context"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -47,7 +48,7 @@ func TestIntegration(t *testing.T) {
 		}
 
 		t.Logf("Tokens: %v", result.Raw)
-		t.Logf("Engine: %s, Processing time: %.2fms", result.Engine, result.ProcessingTime)
+		t.Logf("Engine: %s, Processing time: %.2fms", result.Engine(), result.ProcessingTime())
 
 		if len(result.Raw) == 0 {
 			t.Error("Expected tokens, got none")
@@ -55,7 +56,7 @@ func TestIntegration(t *testing.T) {
 	})
 
 	t.Run("TokenizeWithEngine", func(t *testing.T) {
-		engines := []string{pythainlp.EngineNewMM, pythainlp.EngineLongest}
+		engines := []pythainlp.TokenizeEngine{pythainlp.EngineNewMM, pythainlp.EngineLongest}
 		
 		for _, engine := range engines {
 			result, err := manager.TokenizeWithEngine(ctx, testText, engine)
@@ -65,6 +66,35 @@ func TestIntegration(t *testing.T) {
 			}
 
 			t.Logf("Engine %s: %v", engine, result.Raw)
+			if joined := strings.Join(result.Raw, ""); joined != testText {
+				t.Errorf("Engine %s: expected whitespace to be kept, got %q", engine, joined)
+			}
+		}
+	})
+
+	t.Run("TokenizeKeepWhitespace", func(t *testing.T) {
+		kept, err := manager.Tokenize(ctx, testText, pythainlp.WithKeepWhitespace(true))
+		if err != nil {
+			t.Fatalf("Tokenize failed: %v", err)
+		}
+		if joined := strings.Join(kept.Raw, ""); joined != testText {
+			t.Errorf("Expected tokens to add up to the text, got %q", joined)
+		}
+
+		dropped, err := manager.Tokenize(ctx, testText, pythainlp.WithKeepWhitespace(false))
+		if err != nil {
+			t.Fatalf("Tokenize failed: %v", err)
+		}
+		for _, token := range dropped.Tokens {
+			if strings.TrimSpace(token.Surface) == "" {
+				t.Errorf("Expected no whitespace tokens, got %v", dropped.Raw)
+			}
+			if got := testText[token.Start:token.End]; got != token.Surface {
+				t.Errorf("Token %q at %d:%d covers %q", token.Surface, token.Start, token.End, got)
+			}
+		}
+		if len(dropped.Tokens) != len(kept.Tokens)-1 {
+			t.Errorf("Expected one token less without whitespace: %v, %v", kept.Raw, dropped.Raw)
 		}
 	})
 
@@ -75,7 +105,7 @@ func TestIntegration(t *testing.T) {
 		}
 
 		t.Logf("Romanized: %s", result.Text)
-		t.Logf("Engine: %s, Processing time: %.2fms", result.Engine, result.ProcessingTime)
+		t.Logf("Engine: %s, Processing time: %.2fms", result.Engine(), result.ProcessingTime())
 
 		if result.Text == "" {
 			t.Error("Expected romanized text, got empty")
@@ -100,7 +130,7 @@ func TestIntegration(t *testing.T) {
 
 	t.Run("Transliterate", func(t *testing.T) {
 		// Note: Some engines require additional dependencies
-		engines := []string{pythainlp.EngineICUTrans}
+		engines := []pythainlp.TransliterateEngine{pythainlp.EngineICUTrans}
 		
 		for _, engine := range engines {
 			result, err := manager.TransliterateWithEngine(ctx, "สวัสดี", engine)
@@ -113,6 +143,19 @@ func TestIntegration(t *testing.T) {
 		}
 	})
 
+	t.Run("TransliterateISO11940", func(t *testing.T) {
+		for _, text := range []string{testText, "ภาษาไทย", "ก็", "ฤดูใบไม้ผลิ", "ปี ๒๕๖๘ ฯลฯ"} {
+			result, err := manager.TransliterateWithEngine(ctx, text, pythainlp.EngineISO11940)
+			if err != nil {
+				t.Fatalf("TransliterateWithEngine failed: %v", err)
+			}
+
+			if local := pythainlp.TransliterateISO11940(text); local != result.Phonetic {
+				t.Errorf("%s: local %q, service %q", text, local, result.Phonetic)
+			}
+		}
+	})
+
 	t.Run("SyllableTokenize", func(t *testing.T) {
 		result, err := manager.SyllableTokenize(ctx, testText)
 		if err != nil {
@@ -120,7 +163,7 @@ func TestIntegration(t *testing.T) {
 		}
 
 		t.Logf("Syllables: %v", result.Syllables)
-		t.Logf("Engine: %s, Processing time: %.2fms", result.Engine, result.ProcessingTime)
+		t.Logf("Engine: %s, Processing time: %.2fms", result.Engine(), result.ProcessingTime())
 
 		if len(result.Syllables) == 0 {
 			t.Error("Expected syllables, got none")
@@ -128,7 +171,7 @@ func TestIntegration(t *testing.T) {
 	})
 
 	t.Run("SyllableTokenizeWithEngine", func(t *testing.T) {
-		engines := []string{pythainlp.EngineSyllableHanSolo, pythainlp.EngineSyllableDict, pythainlp.EngineSyllableTLTK}
+		engines := []pythainlp.SyllableEngine{pythainlp.EngineSyllableHanSolo, pythainlp.EngineSyllableDict, pythainlp.EngineSyllableTLTK}
 		
 		for _, engine := range engines {
 			result, err := manager.SyllableTokenizeWithEngine(ctx, "สวัสดี", engine)
@@ -149,7 +192,7 @@ func TestIntegration(t *testing.T) {
 
 		t.Logf("Raw tokens: %v", result.RawTokens)
 		t.Logf("Romanized: %s", result.Romanized)
-		t.Logf("Processing time: %.2fms", result.ProcessingTime)
+		t.Logf("Processing time: %.2fms", result.ProcessingTime())
 
 		if len(result.Tokens) > 0 {
 			t.Log("Token details:")
@@ -174,7 +217,7 @@ func TestIntegration(t *testing.T) {
 		t.Logf("Raw tokens: %v", result.RawTokens)
 		t.Logf("Romanized: %s", result.Romanized)
 		t.Logf("Syllables: %v", result.Syllables)
-		t.Logf("Processing time: %.2fms", result.ProcessingTime)
+		t.Logf("Processing time: %.2fms", result.ProcessingTime())
 
 		if len(result.Syllables) == 0 {
 			t.Error("Expected syllables in combined analysis")
@@ -191,17 +234,78 @@ func TestIntegration(t *testing.T) {
 	})
 
 	t.Run("GetSupportedEngines", func(t *testing.T) {
-		engines, err := manager.GetSupportedEngines(ctx)
+		report, err := manager.GetSupportedEngines(ctx)
 		if err != nil {
 			t.Fatalf("GetSupportedEngines failed: %v", err)
 		}
 
-		for category, engineList := range engines {
-			t.Logf("%s engines: %v", category, engineList)
+		for _, e := range report.Engines {
+			t.Logf("%s/%s: available=%v downloaded=%v", e.Operation, e.Name, e.Available, e.ModelDownloaded)
+		}
+		if !report.IsAvailable("tokenize", string(pythainlp.EngineNewMM)) {
+			t.Error("Expected newmm to be available")
+		}
+		if !report.IsAvailable("sentence", string(pythainlp.EngineSentenceCRFCut)) {
+			t.Error("Expected crfcut to be available")
+		}
+	})
+
+	t.Run("AnalyzeDocumentWithSentenceEngine", func(t *testing.T) {
+		engines := []pythainlp.SentenceEngine{pythainlp.EngineSentenceCRFCut, pythainlp.EngineSentenceWhitespace, pythainlp.EngineSentenceTLTK}
+		text := "ผมชอบกินข้าว แต่เขาชอบกินก๋วยเตี๋ยว"
+
+		for _, engine := range engines {
+			result, err := manager.AnalyzeDocument(ctx, text, pythainlp.DocumentOptions{SentenceEngine: engine})
+			if err != nil {
+				t.Logf("Engine %s not available: %v", engine, err)
+				continue
+			}
+			if len(result.Paragraphs) != 1 || len(result.Paragraphs[0].Sentences) == 0 {
+				t.Errorf("Engine %s: expected one paragraph with sentences, got %+v", engine, result.Paragraphs)
+				continue
+			}
+			for _, s := range result.Paragraphs[0].Sentences {
+				if s.Start >= 0 && text[s.Start:s.End] != s.Text {
+					t.Errorf("Engine %s: sentence %q has wrong offsets %d-%d", engine, s.Text, s.Start, s.End)
+				}
+			}
+			t.Logf("Engine %s: %d sentences", engine, len(result.Paragraphs[0].Sentences))
 		}
 	})
 }
 
+func TestReinit(t *testing.T) {
+	// Skip if not explicitly enabled
+	if os.Getenv("PYTHAINLP_TEST") != "1" {
+		t.Skip("Integration tests disabled. Set PYTHAINLP_TEST=1 to run")
+	}
+
+	ctx := context.Background()
+
+	// Each manager has its own port and token, so the second one must not
+	// reuse the container of the first as is
+	for i := range 2 {
+		manager, err := pythainlp.NewManager(ctx,
+			pythainlp.WithProjectName("pythainlp-reinit-test"),
+			pythainlp.WithQueryTimeout(30*time.Second))
+		if err != nil {
+			t.Fatalf("Manager %d: failed to create: %v", i, err)
+		}
+		if err := manager.Init(ctx); err != nil {
+			t.Fatalf("Manager %d: failed to initialize: %v", i, err)
+		}
+		result, err := manager.Tokenize(ctx, "ภาษาไทย")
+		if err != nil {
+			manager.Close()
+			t.Fatalf("Manager %d: Tokenize failed: %v", i, err)
+		}
+		t.Logf("Manager %d tokens: %v", i, result.Raw)
+		if err := manager.Close(); err != nil {
+			t.Fatalf("Manager %d: failed to close: %v", i, err)
+		}
+	}
+}
+
 func TestPackageLevelFunctions(t *testing.T) {
 	// Skip if not explicitly enabled
 	if os.Getenv("PYTHAINLP_TEST") != "1" {
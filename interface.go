@@ -0,0 +1,21 @@
+package pythainlp
+
+import "context"
+
+// ThaiNLP is the text-processing API of PyThaiNLPManager. Applications can
+// depend on it instead of the manager and substitute pythainlptest.Fake in
+// unit tests that should not need Docker.
+type ThaiNLP interface {
+	Tokenize(ctx context.Context, text string, opts ...CallOption) (*TokenizeResult, error)
+	TokenizeWithOptions(ctx context.Context, text string, opts TokenizeOptions) (*TokenizeResult, error)
+	Romanize(ctx context.Context, text string, opts ...CallOption) (*RomanizeResult, error)
+	RomanizeWithOptions(ctx context.Context, text string, opts RomanizeOptions) (*RomanizeResult, error)
+	Transliterate(ctx context.Context, text string, opts ...CallOption) (*TransliterateResult, error)
+	TransliterateWithOptions(ctx context.Context, text string, opts TransliterateOptions) (*TransliterateResult, error)
+	SyllableTokenize(ctx context.Context, text string, opts ...CallOption) (*SyllableTokenizeResult, error)
+	SyllableTokenizeWithOptions(ctx context.Context, text string, opts SyllableTokenizeOptions) (*SyllableTokenizeResult, error)
+	AnalyzeText(ctx context.Context, text string, opts ...CallOption) (*AnalyzeResult, error)
+	AnalyzeWithOptions(ctx context.Context, text string, opts AnalyzeOptions) (*AnalyzeResult, error)
+}
+
+var _ ThaiNLP = (*PyThaiNLPManager)(nil)
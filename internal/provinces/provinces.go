@@ -0,0 +1,48 @@
+// Package provinces holds the list of the 77 Thai provinces shared by
+// pythainlp and thaivalid, so that both read the same names. Districts and
+// subdistricts are not covered: PyThaiNLP has no corpus for them.
+package provinces
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// provinces.tsv lists the provinces in Thai alphabetical order, one per
+// line: Thai name, RTGS romanization, English name and region, separated by
+// tabs
+//
+//go:embed provinces.tsv
+var file string
+
+// Row is a line of the list
+type Row struct {
+	Thai    string
+	RTGS    string
+	English string
+	Region  string
+}
+
+// Rows returns the provinces in Thai alphabetical order, with Bangkok as
+// กรุงเทพมหานคร
+func Rows() []Row {
+	var rows []Row
+	for line := range strings.Lines(file) {
+		fields := strings.Split(strings.TrimRight(line, "\r\n"), "\t")
+		if len(fields) != 4 {
+			continue
+		}
+		rows = append(rows, Row{Thai: fields[0], RTGS: fields[1], English: fields[2], Region: fields[3]})
+	}
+	return rows
+}
+
+// Names returns the Thai names of the provinces, in the order of Rows
+func Names() []string {
+	rows := Rows()
+	names := make([]string, len(rows))
+	for i, r := range rows {
+		names[i] = r.Thai
+	}
+	return names
+}
@@ -0,0 +1,46 @@
+package pythainlp
+
+import "strings"
+
+// iso11940 maps each Thai character to its ISO 11940 transliteration. The
+// standard is graphical: characters are transliterated one by one, in
+// written order, so the mapping is reversible but not a pronunciation.
+var iso11940 = map[rune]string{
+	// Consonants
+	'ก': "k", 'ข': "k̄h", 'ฃ': "ḳ̄h", 'ค': "kh", 'ฅ': "k̛h", 'ฆ': "ḳh", 'ง': "ng",
+	'จ': "c", 'ฉ': "c̄h", 'ช': "ch", 'ซ': "s", 'ฌ': "c̣h", 'ญ': "ỵ",
+	'ฎ': "ḍ", 'ฏ': "ṭ", 'ฐ': "ṭ̄h", 'ฑ': "ṯh", 'ฒ': "t̛h", 'ณ': "ṇ",
+	'ด': "d", 'ต': "t", 'ถ': "t̄h", 'ท': "th", 'ธ': "ṭh", 'น': "n",
+	'บ': "b", 'ป': "p", 'ผ': "p̄h", 'ฝ': "f̄", 'พ': "ph", 'ฟ': "f", 'ภ': "p̣h", 'ม': "m",
+	'ย': "y", 'ร': "r", 'ฤ': "v", 'ล': "l", 'ฦ': "ł", 'ว': "w",
+	'ศ': "ṣ̄", 'ษ': "s̛̄", 'ส': "s̄", 'ห': "h̄", 'ฬ': "ḷ", 'อ': "x", 'ฮ': "ḥ",
+
+	// Vowels
+	'ะ': "a", 'ั': "ạ", 'า': "ā", 'ำ': "å", 'ิ': "i", 'ี': "ī", 'ึ': "ụ", 'ื': "ụ̄",
+	'ุ': "u", 'ู': "ū", 'เ': "e", 'แ': "æ", 'โ': "o", 'ใ': "ı", 'ไ': "ị", 'ๅ': "ɨ",
+
+	// Tone marks and other signs
+	'่': "̀", '้': "̂", '๊': "̃", '๋': "̌",
+	'็': "̆", '์': "̒", 'ํ': "̊", 'ฺ': "̥", '๎': "~",
+	'ฯ': "ǂ", 'ๆ': "«", '๏': "§", '๚': "ǁ", '๛': "»",
+
+	// Digits
+	'๐': "0", '๑': "1", '๒': "2", '๓': "3", '๔': "4", '๕': "5", '๖': "6", '๗': "7", '๘': "8", '๙': "9",
+}
+
+// TransliterateISO11940 transliterates text to the Latin script following
+// ISO 11940, locally and without the service, e.g. for offline use. Other
+// characters are kept as they are. It implements the same standard as the
+// iso_11940 engine.
+func TransliterateISO11940(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range text {
+		if s, ok := iso11940[r]; ok {
+			b.WriteString(s)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
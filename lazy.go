@@ -0,0 +1,84 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+)
+
+// lazyAttempt is one background initialization shared by concurrent callers
+type lazyAttempt struct {
+	done chan struct{}
+	err  error
+}
+
+// WithLazyStart makes Init return immediately. The image is pulled and the
+// container started by the first request, which blocks until the service is
+// ready; concurrent requests wait for the same startup. This lets GUI
+// applications construct the manager cheaply at launch.
+func WithLazyStart() ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.lazyStart = true
+	}
+}
+
+// ensureReady returns nil once the service is ready. In lazy mode, or after
+// an idle shutdown, it starts the service first.
+func (pm *PyThaiNLPManager) ensureReady(ctx context.Context) error {
+	pm.touch()
+	// In replay mode the service is only started to record a missing response
+	if pm.IsReady() || pm.replayDir != "" {
+		return nil
+	}
+
+	pm.mu.Lock()
+	startable := pm.lazyStart || pm.idleStopped
+	pm.mu.Unlock()
+	if !startable {
+		return ErrServiceNotReady
+	}
+	return pm.startOnDemand(ctx)
+}
+
+// startOnDemand starts the service in the background, or joins a start
+// already in progress, and waits until it is ready or ctx is done
+func (pm *PyThaiNLPManager) startOnDemand(ctx context.Context) error {
+	if pm.IsReady() {
+		return nil
+	}
+
+	pm.mu.Lock()
+	attempt := pm.lazyInit
+	if attempt == nil {
+		attempt = &lazyAttempt{done: make(chan struct{})}
+		pm.lazyInit = attempt
+		// Detached from ctx so that a caller giving up does not abort the
+		// startup other callers are waiting on
+		go pm.runLazyInit(attempt)
+	}
+	pm.mu.Unlock()
+
+	select {
+	case <-attempt.done:
+		if attempt.err != nil {
+			return fmt.Errorf("%w: start on demand failed: %w", ErrServiceNotReady, attempt.err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (pm *PyThaiNLPManager) runLazyInit(attempt *lazyAttempt) {
+	Logger.Info().Msg("Starting service on demand")
+	attempt.err = pm.initialize(context.Background())
+
+	// Cleared in all cases so that a failed start, or a service stopped
+	// later, is started again by the next request
+	pm.mu.Lock()
+	pm.lazyInit = nil
+	if attempt.err == nil {
+		pm.idleStopped = false
+	}
+	pm.mu.Unlock()
+	close(attempt.done)
+}
@@ -0,0 +1,141 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ServiceLimits are the largest requests the service accepts, as advertised
+// by its health endpoint. Zero means no limit is known.
+type ServiceLimits struct {
+	MaxTextLength   int64 `json:"max_text_length"`   // Characters per text
+	MaxBatchSize    int64 `json:"max_batch_size"`    // Texts per batch request
+	MaxRequestBytes int64 `json:"max_request_bytes"` // Size of a request body
+}
+
+// Limits returns the limits the service advertised when it started. Calls
+// are checked against them before being sent: Tokenize splits a longer text
+// at whitespace and TokenizeBatch and RomanizeBatch split larger batches,
+// while the other methods return a *LimitError.
+func (pm *PyThaiNLPManager) Limits() ServiceLimits {
+	return pm.client.Limits()
+}
+
+// Limits returns the limits of the service, known once Health was called
+func (c *Client) Limits() ServiceLimits {
+	if limits := c.limits.Load(); limits != nil {
+		return *limits
+	}
+	return ServiceLimits{}
+}
+
+// checkText returns a *LimitError if text is longer than the service accepts
+func (c *Client) checkText(text string) error {
+	max := c.Limits().MaxTextLength
+	if max <= 0 || int64(len(text)) <= max {
+		return nil
+	}
+	if n := int64(utf8.RuneCountInString(text)); n > max {
+		return &LimitError{Limit: "text_length", Size: n, Max: max}
+	}
+	return nil
+}
+
+// checkTexts is checkText for every text of a batch
+func (c *Client) checkTexts(texts []string) error {
+	for _, text := range texts {
+		if err := c.checkText(text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inBatches calls send with consecutive batches of texts no larger than the
+// service accepts and concatenates the results
+func inBatches[R any](pm *PyThaiNLPManager, texts []string, send func(batch []string) ([]R, error)) ([]R, error) {
+	if err := pm.client.checkTexts(texts); err != nil {
+		return nil, err
+	}
+	size := int(pm.Limits().MaxBatchSize)
+	if size <= 0 || len(texts) <= size {
+		return send(texts)
+	}
+
+	results := make([]R, 0, len(texts))
+	for start := 0; start < len(texts); start += size {
+		batch, err := send(texts[start:min(start+size, len(texts))])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
+
+// tokenizeChunks tokenizes a text longer than the service accepts in pieces
+// of at most maxRunes, cut after the last whitespace that fits
+func (pm *PyThaiNLPManager) tokenizeChunks(ctx context.Context, text string, opts TokenizeOptions, maxRunes int) (*TokenizeResult, error) {
+	var tokens []string
+	var meta Metadata
+	var processed strings.Builder
+	for i, chunk := range splitAtWhitespace(text, maxRunes) {
+		req := pm.tokenizeRequest(chunk, opts)
+		resp, err := pm.client.Tokenize(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("tokenization failed: %w", err)
+		}
+		if i == 0 {
+			meta = newMetadata(resp.Metadata, req.Engine)
+		}
+		tokens = append(tokens, resp.Tokens...)
+		processed.WriteString(resp.Text)
+	}
+	if opts.Preprocess == nil {
+		return newTokenizeResult(text, tokens, meta), nil
+	}
+	// Each chunk was preprocessed on its own
+	result := newTokenizeResult(processed.String(), tokens, meta)
+	result.Text = processed.String()
+	return result, nil
+}
+
+// splitAtWhitespace cuts text into pieces of at most maxRunes runes, each
+// ending after whitespace where possible. A stretch of maxRunes runes without
+// whitespace is cut where it reaches the limit, but not before a combining
+// mark such as a Thai vowel or tone mark.
+func splitAtWhitespace(text string, maxRunes int) []string {
+	var chunks []string
+	for utf8.RuneCountInString(text) > maxRunes {
+		end, cut, n := 0, 0, 0
+		for i, r := range text {
+			if n == maxRunes {
+				break
+			}
+			n++
+			end = i + utf8.RuneLen(r)
+			if unicode.IsSpace(r) {
+				cut = end
+			}
+		}
+		if cut == 0 {
+			cut = end
+			for cut > 0 {
+				if r, _ := utf8.DecodeRuneInString(text[cut:]); !unicode.Is(unicode.Mn, r) {
+					break
+				}
+				_, size := utf8.DecodeLastRuneInString(text[:cut])
+				cut -= size
+			}
+			if cut == 0 {
+				cut = end
+			}
+		}
+		chunks = append(chunks, text[:cut])
+		text = text[cut:]
+	}
+	return append(chunks, text)
+}
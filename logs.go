@@ -0,0 +1,37 @@
+package pythainlp
+
+import (
+	"github.com/tassa-yoniso-manasi-karoto/dockerutil"
+)
+
+// LogConsumer receives the log lines of the service container
+type LogConsumer interface {
+	Log(containerName, message string) // stdout
+	Err(containerName, message string) // stderr
+}
+
+// WithLogConsumer routes container logs to the given consumer (a file, ring
+// buffer, GUI panel...) instead of stdout. The package then leaves
+// dockerutil's global log output untouched.
+func WithLogConsumer(consumer LogConsumer) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.logConsumer = consumer
+	}
+}
+
+// teeLogConsumer forwards container logs to the application's consumer while
+// keeping dockerutil's consumer in the loop for init message detection
+type teeLogConsumer struct {
+	*dockerutil.ContainerLogConsumer
+	sink LogConsumer
+}
+
+func (t *teeLogConsumer) Log(containerName, message string) {
+	t.ContainerLogConsumer.Log(containerName, message)
+	t.sink.Log(containerName, message)
+}
+
+func (t *teeLogConsumer) Err(containerName, message string) {
+	t.ContainerLogConsumer.Err(containerName, message)
+	t.sink.Err(containerName, message)
+}
@@ -0,0 +1,51 @@
+package pythainlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MapTexts calls fn on each of texts, at most concurrency at a time (at least
+// one), and returns the results in the order of texts. A text fn fails on
+// leaves the zero R in its place and the others still run; the errors are
+// joined, each prefixed with the index of its text. Once ctx is done no
+// further texts are started, and ctx.Err() is among the errors.
+//
+// Manager methods take options, so wrap them:
+//
+//	results, err := pythainlp.MapTexts(ctx, texts, func(ctx context.Context, text string) (*pythainlp.RomanizeResult, error) {
+//		return manager.Romanize(ctx, text, pythainlp.WithEngine(pythainlp.EngineThai2Rom))
+//	}, 8)
+func MapTexts[R any](ctx context.Context, texts []string, fn func(ctx context.Context, text string) (R, error), concurrency int) ([]R, error) {
+	results := make([]R, len(texts))
+	errs := make([]error, len(texts))
+	sem := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+
+	var ctxErr error
+	for i, text := range texts {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctxErr = ctx.Err(); ctxErr != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res, err := fn(ctx, text)
+			if err != nil {
+				errs[i] = fmt.Errorf("text %d: %w", i, err)
+				return
+			}
+			results[i] = res
+		}()
+	}
+	wg.Wait()
+
+	return results, errors.Join(append(errs, ctxErr)...)
+}
@@ -0,0 +1,156 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TagScheme selects how NER returns what it recognized
+type TagScheme int
+
+const (
+	// TagSchemeSpans merges the tokens of each entity into one Entity
+	TagSchemeSpans TagScheme = iota
+	// TagSchemeBIO returns every token tagged B-TYPE at the beginning of an
+	// entity, I-TYPE inside it and O outside any entity, as thainer does
+	TagSchemeBIO
+	// TagSchemeBIOES is TagSchemeBIO with the last token of an entity tagged
+	// E-TYPE and a single-token entity S-TYPE
+	TagSchemeBIOES
+)
+
+// Entity is a named entity found by NER. Start and End are byte offsets into
+// the text, -1 when the entity could not be found in it.
+type Entity struct {
+	Text  string `json:"text"`
+	Type  string `json:"type"` // e.g. "PERSON", "LOCATION" or "ORGANIZATION"
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// TaggedToken is a token with its tag in the scheme of NEROptions. Start and
+// End are byte offsets into the text, -1 when the token could not be found
+// in it.
+type TaggedToken struct {
+	Text  string `json:"text"`
+	Tag   string `json:"tag"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// NEROptions configures NER
+type NEROptions struct {
+	// Scheme selects merged entity spans (the default) or tagged tokens, as
+	// sequence labeling training data needs
+	Scheme TagScheme
+}
+
+// NERResult lists the entities of a text with TagSchemeSpans, or its tagged
+// tokens with the other schemes
+type NERResult struct {
+	Entities []Entity
+	Tokens   []TaggedToken
+
+	Meta Metadata `json:"metadata"`
+}
+
+// NER finds the named entities of text, such as people, places and
+// organizations, with the thainer engine. Its corpus is downloaded on first
+// use.
+func (pm *PyThaiNLPManager) NER(ctx context.Context, text string, opts NEROptions) (*NERResult, error) {
+	if opts.Scheme < TagSchemeSpans || opts.Scheme > TagSchemeBIOES {
+		return nil, fmt.Errorf("unknown tag scheme %d", opts.Scheme)
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+	if err := pm.client.checkText(text); err != nil {
+		return nil, err
+	}
+
+	resp, err := pm.client.NER(ctx, &NERRequest{Text: text})
+	if err != nil {
+		return nil, fmt.Errorf("named entity recognition failed: %w", err)
+	}
+
+	tokens := resp.Tokens
+	pos := 0
+	for i := range tokens {
+		tokens[i].Start, tokens[i].End, pos = locate(text, tokens[i].Text, pos, len(text))
+	}
+
+	result := &NERResult{Meta: newMetadata(resp.Metadata, "thainer")}
+	switch opts.Scheme {
+	case TagSchemeSpans:
+		result.Entities = mergeEntities(text, tokens)
+	case TagSchemeBIO:
+		result.Tokens = tokens
+	case TagSchemeBIOES:
+		result.Tokens = toBIOES(tokens)
+	}
+	return result, nil
+}
+
+// splitTag returns the prefix and the entity type of a BIO tag, or "O" and
+// "" outside any entity
+func splitTag(tag string) (prefix, kind string) {
+	if p, k, ok := strings.Cut(tag, "-"); ok {
+		return p, k
+	}
+	return "O", ""
+}
+
+// continues reports whether a token tagged tag continues an entity of kind
+func continues(tag, kind string) bool {
+	prefix, k := splitTag(tag)
+	return kind != "" && k == kind && (prefix == "I" || prefix == "E")
+}
+
+// mergeEntities joins each run of B-/I- tagged tokens into an Entity
+func mergeEntities(text string, tokens []TaggedToken) []Entity {
+	var entities []Entity
+	for i := 0; i < len(tokens); {
+		_, kind := splitTag(tokens[i].Tag)
+		if kind == "" {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(tokens) && continues(tokens[j].Tag, kind) {
+			j++
+		}
+		entity := Entity{Type: kind, Start: tokens[i].Start, End: tokens[j-1].End}
+		if entity.Start >= 0 && entity.End >= entity.Start {
+			entity.Text = text[entity.Start:entity.End]
+		} else {
+			var b strings.Builder
+			for _, t := range tokens[i:j] {
+				b.WriteString(t.Text)
+			}
+			entity.Text, entity.Start, entity.End = b.String(), -1, -1
+		}
+		entities = append(entities, entity)
+		i = j
+	}
+	return entities
+}
+
+// toBIOES retags BIO tokens, marking the last token of each entity E- and
+// single-token entities S-
+func toBIOES(tokens []TaggedToken) []TaggedToken {
+	for i := range tokens {
+		prefix, kind := splitTag(tokens[i].Tag)
+		if kind == "" {
+			continue
+		}
+		last := i+1 == len(tokens) || !continues(tokens[i+1].Tag, kind)
+		switch {
+		case prefix == "B" && last:
+			tokens[i].Tag = "S-" + kind
+		case prefix == "I" && last:
+			tokens[i].Tag = "E-" + kind
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,95 @@
+package pythainlp
+
+import "strings"
+
+// buddhistEraOffset is the difference between Buddhist Era and Common Era
+// years, as used in Thailand since 1941 (years start on 1 January)
+const buddhistEraOffset = 543
+
+// ToThaiDigits replaces the ASCII digits of s with Thai digits, ๐ to ๙
+func ToThaiDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r - '0' + '๐'
+		}
+		return r
+	}, s)
+}
+
+// ToArabicDigits replaces the Thai digits of s with ASCII digits
+func ToArabicDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '๐' && r <= '๙' {
+			return r - '๐' + '0'
+		}
+		return r
+	}, s)
+}
+
+// ToBuddhistYear converts a Common Era year to the Buddhist Era, e.g. 2025
+// to 2568
+func ToBuddhistYear(ce int) int {
+	return ce + buddhistEraOffset
+}
+
+// ToCommonEraYear converts a Buddhist Era year to the Common Era, e.g. 2568
+// to 2025
+func ToCommonEraYear(be int) int {
+	return be - buddhistEraOffset
+}
+
+var (
+	thaiDigitWords = [...]string{"ศูนย์", "หนึ่ง", "สอง", "สาม", "สี่", "ห้า", "หก", "เจ็ด", "แปด", "เก้า"}
+	thaiPlaceWords = [...]string{"", "สิบ", "ร้อย", "พัน", "หมื่น", "แสน"}
+)
+
+// ThaiNumberText spells n out in Thai words, e.g. 21 as ยี่สิบเอ็ด and
+// 1000001 as หนึ่งล้านเอ็ด, like PyThaiNLP's num_to_thaiword
+func ThaiNumberText(n int64) string {
+	if n == 0 {
+		return thaiDigitWords[0]
+	}
+	var b strings.Builder
+	if n < 0 {
+		b.WriteString("ลบ")
+	}
+	u := uint64(n)
+	if n < 0 {
+		u = -u
+	}
+	writeThaiNumber(&b, u, false)
+	return b.String()
+}
+
+// writeThaiNumber writes u > 0 in words. A units digit of 1 is read เอ็ด
+// after any higher digit, including those of higher groups (hasHigher).
+func writeThaiNumber(b *strings.Builder, u uint64, hasHigher bool) {
+	if millions := u / 1_000_000; millions > 0 {
+		writeThaiNumber(b, millions, hasHigher)
+		b.WriteString("ล้าน")
+		u %= 1_000_000
+		hasHigher = true
+	}
+	digits := [6]int{}
+	for i := range digits {
+		digits[i] = int(u % 10)
+		u /= 10
+	}
+	for place := 5; place >= 0; place-- {
+		d := digits[place]
+		switch {
+		case d == 0:
+			continue
+		case place == 1 && d == 1:
+			// สิบ, not หนึ่งสิบ
+		case place == 1 && d == 2:
+			b.WriteString("ยี่")
+		case place == 0 && d == 1 && hasHigher:
+			b.WriteString("เอ็ด")
+		default:
+			b.WriteString(thaiDigitWords[d])
+		}
+		b.WriteString(thaiPlaceWords[place])
+		hasHigher = true
+	}
+}
@@ -0,0 +1,71 @@
+package pythainlp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestThaiNumberText(t *testing.T) {
+	for _, c := range []struct {
+		n    int64
+		want string
+	}{
+		{0, "ศูนย์"},
+		{1, "หนึ่ง"},
+		{10, "สิบ"},
+		{11, "สิบเอ็ด"},
+		{20, "ยี่สิบ"},
+		{21, "ยี่สิบเอ็ด"},
+		{101, "หนึ่งร้อยเอ็ด"},
+		{110, "หนึ่งร้อยสิบ"},
+		{1001, "หนึ่งพันเอ็ด"},
+		{1_000_000, "หนึ่งล้าน"},
+		{1_000_001, "หนึ่งล้านเอ็ด"}, // เอ็ด after a higher group
+		{2_000_010, "สองล้านสิบ"},
+		{11_000_000, "สิบเอ็ดล้าน"},
+		{21_000_021, "ยี่สิบเอ็ดล้านยี่สิบเอ็ด"},
+		{1_000_000_000_000, "หนึ่งล้านล้าน"},
+		{-21, "ลบยี่สิบเอ็ด"},
+		{math.MinInt64, "ลบเก้าล้านสองแสนสองหมื่นสามพันสามร้อยเจ็ดสิบสองล้านสามหมื่นหกพันแปดร้อยห้าสิบสี่ล้านเจ็ดแสนเจ็ดหมื่นห้าพันแปดร้อยแปด"},
+	} {
+		if got := ThaiNumberText(c.n); got != c.want {
+			t.Errorf("ThaiNumberText(%d) = %q, want %q", c.n, got, c.want)
+		}
+	}
+}
+
+func TestThaiDigits(t *testing.T) {
+	for _, c := range []struct {
+		arabic, thai string
+	}{
+		{"", ""},
+		{"0123456789", "๐๑๒๓๔๕๖๗๘๙"},
+		{"พ.ศ. 2568", "พ.ศ. ๒๕๖๘"},
+		{"abc", "abc"},
+	} {
+		if got := ToThaiDigits(c.arabic); got != c.thai {
+			t.Errorf("ToThaiDigits(%q) = %q, want %q", c.arabic, got, c.thai)
+		}
+		if got := ToArabicDigits(c.thai); got != c.arabic {
+			t.Errorf("ToArabicDigits(%q) = %q, want %q", c.thai, got, c.arabic)
+		}
+	}
+}
+
+func TestBuddhistYear(t *testing.T) {
+	for _, c := range []struct {
+		ce, be int
+	}{
+		{2025, 2568},
+		{1941, 2484},
+		{0, 543},
+		{-543, 0},
+	} {
+		if got := ToBuddhistYear(c.ce); got != c.be {
+			t.Errorf("ToBuddhistYear(%d) = %d, want %d", c.ce, got, c.be)
+		}
+		if got := ToCommonEraYear(c.be); got != c.ce {
+			t.Errorf("ToCommonEraYear(%d) = %d, want %d", c.be, got, c.ce)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package pythainlp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// LoadImageFromTar loads the service image from an archive created with
+// `docker save`, for hosts that cannot reach the registry
+func (pm *PyThaiNLPManager) LoadImageFromTar(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open image archive: %w", err)
+	}
+	defer f.Close()
+
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to get Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	Logger.Info().Str("path", path).Msg("Loading image from archive")
+
+	resp, err := dockerClient.ImageLoad(ctx, f, client.ImageLoadWithQuiet(true))
+	if err != nil {
+		return fmt.Errorf("failed to load image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.JSON {
+		// The daemon reports load failures in the stream, not in the status code
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var msg jsonmessage.JSONMessage
+			if err := decoder.Decode(&msg); err != nil {
+				if err == io.EOF {
+					break
+				}
+				return fmt.Errorf("failed to decode load output: %w", err)
+			}
+			if msg.Error != nil {
+				return fmt.Errorf("failed to load image: %s", msg.Error.Message)
+			}
+			if msg.Stream != "" {
+				Logger.Debug().Str("output", strings.TrimSpace(msg.Stream)).Msg("Image load")
+			}
+		}
+	} else if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return fmt.Errorf("failed to read load output: %w", err)
+	}
+
+	if err := checkLocalImage(ctx, dockerClient, pm.image); err != nil {
+		return fmt.Errorf("archive %s did not provide the expected image: %w", path, err)
+	}
+
+	Logger.Info().Str("image", pm.image).Msg("Image loaded from archive")
+	return nil
+}
+
+// checkOfflineImage fails fast when offline mode is enabled and the image
+// would otherwise have to be pulled from the registry
+func (pm *PyThaiNLPManager) checkOfflineImage(ctx context.Context) error {
+	if !pm.offline {
+		return nil
+	}
+
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to get Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	return checkLocalImage(ctx, dockerClient, pm.image)
+}
+
+// checkLocalImage verifies that the image exists in the local Docker cache
+func checkLocalImage(ctx context.Context, dockerClient *client.Client, ref string) error {
+	if _, err := dockerClient.ImageInspect(ctx, ref); err != nil {
+		if client.IsErrNotFound(err) {
+			return fmt.Errorf("image %s is not available locally: export it on a connected host with "+
+				"`docker save %s -o pythainlp.tar` and load it with LoadImageFromTar", ref, ref)
+		}
+		return fmt.Errorf("failed to inspect image %s: %w", ref, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+)
+
+// ParagraphEngine splits a text into paragraphs
+type ParagraphEngine string
+
+const (
+	// EngineParagraphNewline splits at line breaks, keeping the lines of
+	// hard-wrapped text together (default)
+	EngineParagraphNewline ParagraphEngine = "newline"
+	// EngineParagraphWtP runs wtpsplit through PyThaiNLP's paragraph_tokenize
+	// between blank lines, full mode only
+	EngineParagraphWtP ParagraphEngine = "wtp"
+)
+
+var paragraphEngines = []ParagraphEngine{EngineParagraphNewline, EngineParagraphWtP}
+
+// Validate returns an error wrapping ErrEngineUnavailable if e is not a known
+// paragraph engine. The zero value is valid and selects the default engine.
+func (e ParagraphEngine) Validate() error {
+	return validateEngine("paragraph", e, paragraphEngines)
+}
+
+// ParagraphOptions selects the engine of ParagraphTokenizeWithOptions
+type ParagraphOptions struct {
+	Engine ParagraphEngine // Default newline
+}
+
+// ParagraphSpan is a paragraph and its byte offsets in the text, as for
+// Token: text[Start:End] is Text. Both are -1 when it could not be found.
+type ParagraphSpan struct {
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// ParagraphResult is a text split into paragraphs
+type ParagraphResult struct {
+	Paragraphs []ParagraphSpan
+
+	Meta Metadata `json:"metadata"`
+}
+
+// ParagraphTokenize splits text into paragraphs, e.g. to chunk a document
+// before heavier processing. Blank lines always separate paragraphs. A
+// single line break does too, unless the text looks hard-wrapped: lines of
+// 40 to 120 characters, the line before the break about as wide as the
+// widest of its block and the next one neither indented nor a list item.
+// Paragraphs are trimmed of surrounding whitespace.
+func (pm *PyThaiNLPManager) ParagraphTokenize(ctx context.Context, text string) (*ParagraphResult, error) {
+	return pm.ParagraphTokenizeWithOptions(ctx, text, ParagraphOptions{})
+}
+
+// ParagraphTokenizeWithOptions is ParagraphTokenize with a choice of engine
+func (pm *PyThaiNLPManager) ParagraphTokenizeWithOptions(ctx context.Context, text string, opts ParagraphOptions) (*ParagraphResult, error) {
+	if err := opts.Engine.Validate(); err != nil {
+		return nil, err
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+	if err := pm.client.checkText(text); err != nil {
+		return nil, err
+	}
+
+	req := &ParagraphTokenizeRequest{Text: text, Engine: string(opts.Engine)}
+	if req.Engine == "" {
+		req.Engine = string(EngineParagraphNewline)
+	}
+
+	resp, err := pm.client.ParagraphTokenize(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("paragraph segmentation failed: %w", err)
+	}
+
+	result := &ParagraphResult{
+		Paragraphs: make([]ParagraphSpan, len(resp.Paragraphs)),
+		Meta:       newMetadata(resp.Metadata, req.Engine),
+	}
+	pos := 0
+	for i, p := range resp.Paragraphs {
+		span := ParagraphSpan{Text: p}
+		span.Start, span.End, pos = locate(text, p, pos, len(text))
+		result.Paragraphs[i] = span
+	}
+	return result, nil
+}
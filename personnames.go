@@ -0,0 +1,83 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+)
+
+// PersonName is a Thai personal name, split into its parts. Start and End
+// are byte offsets into the text the name was extracted from, -1 when it
+// could not be found in it.
+type PersonName struct {
+	Text       string `json:"text"`
+	Honorific  string `json:"honorific"`   // e.g. นาย, นาง, นางสาว or น.ส., empty if there is none
+	GivenName  string `json:"given_name"`  // The first word after the honorific
+	FamilyName string `json:"family_name"` // The words after the given name, often empty
+
+	// KnownGivenName and KnownFamilyName report whether the names are in
+	// PyThaiNLP's name corpora
+	KnownGivenName  bool `json:"known_given_name"`
+	KnownFamilyName bool `json:"known_family_name"`
+
+	// Source tells what found the name: "corpus" for a known name, "ner" for
+	// the thainer engine, or both separated by a comma
+	Source string `json:"source"`
+
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// PersonNamesResult lists the person names found in a text
+type PersonNamesResult struct {
+	Names []PersonName
+
+	Meta Metadata `json:"metadata"`
+}
+
+// IsLikelyPersonName reports whether token, such as "นายสมชาย ใจดี" or
+// "สมชาย", is likely a person name. An honorific is set aside, then the name
+// is likely if PyThaiNLP's name corpora know its given or family name, or
+// failing that if thainer tags it as a person.
+func (pm *PyThaiNLPManager) IsLikelyPersonName(ctx context.Context, token string) (bool, error) {
+	if err := pm.ensureReady(ctx); err != nil {
+		return false, err
+	}
+	if err := pm.client.checkText(token); err != nil {
+		return false, err
+	}
+
+	resp, err := pm.client.PersonNames(ctx, &PersonNamesRequest{Text: token})
+	if err != nil {
+		return false, fmt.Errorf("person name check failed: %w", err)
+	}
+	return resp.Likely, nil
+}
+
+// ExtractPersonNames finds the person names of text, e.g. to redact them in
+// a PII detection pipeline. It combines the person entities of NER with
+// honorifics (นาย, นาง, นางสาว, น.ส., ด.ช., คุณ, ...) followed by a given
+// name the corpora know. This catches names thainer misses without taking
+// every word starting with an honorific, such as นายก, for a name.
+func (pm *PyThaiNLPManager) ExtractPersonNames(ctx context.Context, text string) (*PersonNamesResult, error) {
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+	if err := pm.client.checkText(text); err != nil {
+		return nil, err
+	}
+
+	resp, err := pm.client.PersonNames(ctx, &PersonNamesRequest{Text: text, Extract: true})
+	if err != nil {
+		return nil, fmt.Errorf("person name extraction failed: %w", err)
+	}
+
+	names := resp.Names
+	pos := 0
+	for i := range names {
+		names[i].Start, names[i].End, pos = locate(text, names[i].Text, pos, len(text))
+	}
+	return &PersonNamesResult{
+		Names: names,
+		Meta:  newMetadata(resp.Metadata, "thainer"),
+	}, nil
+}
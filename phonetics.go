@@ -0,0 +1,89 @@
+package pythainlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Tone is the tone of a Thai syllable
+type Tone string
+
+const (
+	ToneMid     Tone = "mid"
+	ToneLow     Tone = "low"
+	ToneFalling Tone = "falling"
+	ToneHigh    Tone = "high"
+	ToneRising  Tone = "rising"
+	ToneUnknown Tone = "" // PyThaiNLP could not tell, e.g. for a non-Thai syllable
+)
+
+// SyllablePhonetics is the pronunciation of one syllable
+type SyllablePhonetics struct {
+	Syllable  string `json:"syllable"`
+	IPA       string `json:"ipa"`
+	Tone      Tone   `json:"tone"`
+	Romanized string `json:"romanized"`
+}
+
+// SyllablePhoneticsOptions selects the engines of SyllablePhonetics
+type SyllablePhoneticsOptions struct {
+	SyllableEngine      SyllableEngine      // Default han_solo
+	TransliterateEngine TransliterateEngine // Gives the IPA; default tltk_ipa, available in lightweight mode
+	RomanizeEngine      RomanizeEngine      // Default royin
+}
+
+// SyllablePhoneticsResult lists the syllables of a text with their
+// pronunciation. Meta.Engine is the syllable engine; the IPA and romanization
+// engines are in Meta.Extra.
+type SyllablePhoneticsResult struct {
+	Syllables []SyllablePhonetics
+
+	Meta Metadata `json:"metadata"`
+}
+
+// SyllablePhonetics splits text into syllables and gives the IPA, tone and
+// romanization of each one, e.g. for flashcards drilling syllables. Unlike
+// Transliterate, which converts the text as a whole, every syllable is
+// converted on its own. Whitespace is skipped.
+func (pm *PyThaiNLPManager) SyllablePhonetics(ctx context.Context, text string, opts SyllablePhoneticsOptions) (*SyllablePhoneticsResult, error) {
+	if err := errors.Join(
+		opts.SyllableEngine.Validate(),
+		opts.TransliterateEngine.Validate(),
+		opts.RomanizeEngine.Validate(),
+	); err != nil {
+		return nil, err
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+	if err := pm.client.checkText(text); err != nil {
+		return nil, err
+	}
+
+	req := &SyllablePhoneticsRequest{
+		Text:                text,
+		SyllableEngine:      string(opts.SyllableEngine),
+		TransliterateEngine: string(opts.TransliterateEngine),
+		RomanizeEngine:      string(opts.RomanizeEngine),
+	}
+	if req.SyllableEngine == "" {
+		req.SyllableEngine = string(EngineSyllableHanSolo)
+	}
+	if req.TransliterateEngine == "" {
+		req.TransliterateEngine = string(EngineTLTKIPA)
+	}
+	if req.RomanizeEngine == "" {
+		req.RomanizeEngine = string(EngineRoyin)
+	}
+
+	resp, err := pm.client.SyllablePhonetics(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("syllable phonetics failed: %w", err)
+	}
+
+	return &SyllablePhoneticsResult{
+		Syllables: resp.Syllables,
+		Meta:      newMetadata(resp.Metadata, req.SyllableEngine),
+	}, nil
+}
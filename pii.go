@@ -0,0 +1,161 @@
+package pythainlp
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp/thaivalid"
+)
+
+// PIIType is the kind of personal information a PIISpan holds
+type PIIType string
+
+const (
+	PIIPerson       PIIType = "PERSON"
+	PIILocation     PIIType = "LOCATION"
+	PIIOrganization PIIType = "ORGANIZATION"
+	PIINationalID   PIIType = "NATIONAL_ID" // 13-digit Thai citizen ID with a valid check digit
+	PIIPhone        PIIType = "PHONE"       // Thai landline or mobile number
+	PIIEmail        PIIType = "EMAIL"
+	PIILicensePlate PIIType = "LICENSE_PLATE" // Vehicle plate written with its province
+)
+
+// PIISpan is a piece of personal information in a text. Start and End are
+// byte offsets into the text.
+type PIISpan struct {
+	Type   PIIType `json:"type"`
+	Text   string  `json:"text"`
+	Start  int     `json:"start"`
+	End    int     `json:"end"`
+	Source string  `json:"source"` // "regex", "ner", "corpus" or "corpus,ner"
+}
+
+// PIIResult lists the personal information found in Text, in order and
+// without overlaps
+type PIIResult struct {
+	Text  string
+	Spans []PIISpan
+
+	Meta Metadata `json:"metadata"`
+}
+
+// piiNERTypes maps the thainer entity classes reported as PII, other than
+// persons which ExtractPersonNames finds
+var piiNERTypes = map[string]PIIType{
+	"LOCATION":     PIILocation,
+	"ORGANIZATION": PIIOrganization,
+	"PHONE":        PIIPhone,
+	"EMAIL":        PIIEmail,
+}
+
+var emailRe = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// DetectPII finds the personal information of text: person names as
+// ExtractPersonNames finds them, locations, organizations, phone numbers and
+// emails tagged by NER, and Thai national ID numbers, phone numbers, license
+// plates and emails matched by pattern (see the thaivalid package). Where
+// findings overlap, the one starting first is kept, the longest if they start
+// together, and a pattern match over a NER entity covering the same text. Use
+// MaskPII or PIIResult.Mask to redact them.
+func (pm *PyThaiNLPManager) DetectPII(ctx context.Context, text string) (*PIIResult, error) {
+	names, err := pm.ExtractPersonNames(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("PII detection failed: %w", err)
+	}
+	entities, err := pm.NER(ctx, text, NEROptions{})
+	if err != nil {
+		return nil, fmt.Errorf("PII detection failed: %w", err)
+	}
+
+	spans := matchPII(text)
+	for _, n := range names.Names {
+		spans = append(spans, PIISpan{Type: PIIPerson, Text: n.Text, Start: n.Start, End: n.End, Source: n.Source})
+	}
+	for _, e := range entities.Entities {
+		if kind, ok := piiNERTypes[e.Type]; ok {
+			spans = append(spans, PIISpan{Type: kind, Text: e.Text, Start: e.Start, End: e.End, Source: "ner"})
+		}
+	}
+
+	return &PIIResult{
+		Text:  text,
+		Spans: resolvePII(spans),
+		Meta:  entities.Meta,
+	}, nil
+}
+
+// matchPII returns the pattern matches of text
+func matchPII(text string) []PIISpan {
+	var spans []PIISpan
+	add := func(kind PIIType, matches []thaivalid.Match) {
+		for _, m := range matches {
+			spans = append(spans, PIISpan{Type: kind, Text: m.Text, Start: m.Start, End: m.End, Source: "regex"})
+		}
+	}
+	add(PIINationalID, thaivalid.NationalIDs(text))
+	add(PIIPhone, thaivalid.Phones(text))
+	add(PIILicensePlate, thaivalid.Plates(text))
+	for _, loc := range emailRe.FindAllStringIndex(text, -1) {
+		spans = append(spans, PIISpan{Type: PIIEmail, Text: text[loc[0]:loc[1]], Start: loc[0], End: loc[1], Source: "regex"})
+	}
+	return spans
+}
+
+// resolvePII sorts spans and drops those overlapping one kept before them
+func resolvePII(spans []PIISpan) []PIISpan {
+	spans = slices.DeleteFunc(spans, func(s PIISpan) bool { return s.Start < 0 || s.End <= s.Start })
+	slices.SortStableFunc(spans, func(a, b PIISpan) int {
+		return cmp.Or(
+			cmp.Compare(a.Start, b.Start),
+			cmp.Compare(b.End, a.End),
+			cmp.Compare(piiSourceRank(a.Source), piiSourceRank(b.Source)),
+		)
+	})
+	kept := spans[:0]
+	for _, s := range spans {
+		if len(kept) > 0 && s.Start < kept[len(kept)-1].End {
+			continue
+		}
+		kept = append(kept, s)
+	}
+	return kept
+}
+
+// piiSourceRank orders findings of the same extent, patterns first
+func piiSourceRank(source string) int {
+	if source == "regex" {
+		return 0
+	}
+	return 1
+}
+
+// MaskPII returns text with every span replaced by replace(span), or by its
+// type in brackets, e.g. [PHONE], when replace is nil. Spans must be in order
+// and must not overlap, as DetectPII returns them.
+func MaskPII(text string, spans []PIISpan, replace func(PIISpan) string) string {
+	if replace == nil {
+		replace = func(s PIISpan) string { return "[" + string(s.Type) + "]" }
+	}
+	var b strings.Builder
+	pos := 0
+	for _, s := range spans {
+		if s.Start < pos || s.End > len(text) {
+			continue
+		}
+		b.WriteString(text[pos:s.Start])
+		b.WriteString(replace(s))
+		pos = s.End
+	}
+	b.WriteString(text[pos:])
+	return b.String()
+}
+
+// Mask returns the text with its personal information replaced as MaskPII
+// does
+func (r *PIIResult) Mask(replace func(PIISpan) string) string {
+	return MaskPII(r.Text, r.Spans, replace)
+}
@@ -0,0 +1,121 @@
+package pythainlp
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// pluginDir is where plugins are copied in the container. It lies outside the
+// data directory, which instances share.
+const pluginDir = "/opt/pythainlp-plugins"
+
+// WithPlugins adds the Python modules of plugins, e.g. an embed.FS or
+// os.DirFS of a directory, to the service. Every *.py file at the root of
+// plugins is imported on startup and its register(app) function called with
+// the aiohttp application, to add routes; other files, such as helper
+// modules and data in subdirectories, are copied along. A plugin that fails
+// to load is logged by the service and skipped. Call the routes with
+// Client.CallCustom or PyThaiNLPManager.CallCustom.
+//
+// A handler answers like the built-in ones; in_worker and the other helpers
+// of server.py can be imported from __main__:
+//
+//	from aiohttp import web
+//	from __main__ import in_worker
+//
+//	async def classify(request):
+//	    data = await request.json()
+//	    label = await in_worker(my_model.predict, data["text"])
+//	    return web.json_response({"data": {"label": label}, "metadata": {}, "error": None})
+//
+//	def register(app):
+//	    app.router.add_post("/classify", classify)
+func WithPlugins(plugins fs.FS) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.plugins = plugins
+	}
+}
+
+// CallCustom posts payload to an endpoint added by a plugin and returns the
+// response data and metadata, see Client.CallCustom
+func (pm *PyThaiNLPManager) CallCustom(ctx context.Context, path string, payload any) (*ServiceResponse, error) {
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := pm.client.CallCustom(ctx, path, payload)
+	if err != nil {
+		return nil, fmt.Errorf("call to %s failed: %w", path, err)
+	}
+	return resp, nil
+}
+
+// pluginHash returns a checksum of the plugin files, or "" without plugins
+func (pm *PyThaiNLPManager) pluginHash() (string, error) {
+	if pm.plugins == nil {
+		return "", nil
+	}
+	h := sha256.New()
+	err := fs.WalkDir(pm.plugins, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(pm.plugins, name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", name, len(content))
+		h.Write(content)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read plugins: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyPlugins replaces the plugins in the container with those configured
+func (pm *PyThaiNLPManager) copyPlugins(ctx context.Context, dockerClient *client.Client) error {
+	if pm.plugins == nil {
+		return nil
+	}
+	if _, err := pm.execCommand(ctx, dockerClient, []string{"rm", "-rf", pluginDir, "&&", "mkdir", "-p", pluginDir}); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	err := fs.WalkDir(pm.plugins, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || name == "." {
+			return err
+		}
+		if d.IsDir() {
+			return tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeDir,
+				Name:     name + "/",
+				Mode:     0755,
+			})
+		}
+		content, err := fs.ReadFile(pm.plugins, name)
+		if err != nil {
+			return err
+		}
+		return addTarFile(tw, name, content, 0644)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive plugins: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to archive plugins: %w", err)
+	}
+
+	Logger.Debug().Str("dir", pluginDir).Msg("Copying plugins into the container")
+	return dockerClient.CopyToContainer(ctx, pm.containerName, pluginDir, &buf, container.CopyToContainerOptions{})
+}
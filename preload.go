@@ -0,0 +1,102 @@
+package pythainlp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WarmEngines lists engines the service loads before it reports ready.
+// newmm and royin are always loaded. EngineAuto is not an engine of its own
+// and is rejected.
+type WarmEngines struct {
+	Tokenize      []TokenizeEngine
+	Romanize      []RomanizeEngine
+	Transliterate []TransliterateEngine
+	Syllable      []SyllableEngine
+	Sentence      []SentenceEngine
+
+	// Frequency ranks the Thai National Corpus, which WordDifficulty and the
+	// frequency feature of AnalyzeText otherwise do on their first call
+	Frequency bool
+}
+
+// WithWarmEngines loads the given engines while the service starts, so that
+// Init returns only once their models are in memory and the first request is
+// as fast as the following ones. Models that must be downloaded first make
+// startup correspondingly longer. Engines unavailable in the container are
+// skipped with a message in the service log.
+func WithWarmEngines(engines WarmEngines) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.warmEngines = engines
+	}
+}
+
+// spec validates the engines and encodes them as the comma-separated
+// "operation/engine" list read by server.py
+func (w WarmEngines) spec() (string, error) {
+	var items []string
+	var errs []error
+	items, errs = appendWarm(items, errs, "tokenize", w.Tokenize)
+	items, errs = appendWarm(items, errs, "romanize", w.Romanize)
+	items, errs = appendWarm(items, errs, "transliterate", w.Transliterate)
+	items, errs = appendWarm(items, errs, "syllable", w.Syllable)
+	items, errs = appendWarm(items, errs, "sentence", w.Sentence)
+	if w.Frequency {
+		items = append(items, "frequency/tnc")
+	}
+	return strings.Join(items, ","), errors.Join(errs...)
+}
+
+func appendWarm[E interface {
+	~string
+	Validate() error
+}](items []string, errs []error, operation string, engines []E) ([]string, []error) {
+	for _, e := range engines {
+		if e == "" {
+			continue
+		}
+		if err := e.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if any(e) == any(EngineAuto) {
+			errs = append(errs, fmt.Errorf("%w: %s picks an engine per request and cannot be warmed, list the engines it picks from instead",
+				ErrEngineUnavailable, EngineAuto))
+			continue
+		}
+		items = append(items, operation+"/"+string(e))
+	}
+	return items, errs
+}
+
+// serviceEnv returns the environment server.py is started with
+func (pm *PyThaiNLPManager) serviceEnv() ([]string, error) {
+	warm, err := pm.warmEngines.spec()
+	if err != nil {
+		return nil, err
+	}
+	env := []string{
+		// Also set on the container, but a reused container keeps the values
+		// of the manager that created it
+		"PYTHAINLP_SERVICE_PORT=" + strconv.Itoa(pm.servicePort),
+		"PYTHAINLP_SERVICE_TOKEN=" + pm.token,
+		"PYTHAINLP_SERVICE_WORKERS=" + strconv.Itoa(pm.workers()),
+		"PYTHAINLP_PRELOAD=" + warm,
+	}
+	if pm.tracebackLimit != 0 {
+		env = append(env, "PYTHAINLP_TRACEBACK_LIMIT="+strconv.Itoa(pm.tracebackLimit))
+	}
+	if pm.seed != nil {
+		env = append(env, "PYTHAINLP_SEED="+strconv.FormatInt(*pm.seed, 10))
+	}
+	if pm.plugins != nil {
+		hash, err := pm.pluginHash()
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, "PYTHAINLP_PLUGIN_DIR="+pluginDir, "PYTHAINLP_PLUGIN_HASH="+hash)
+	}
+	return env, nil
+}
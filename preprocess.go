@@ -0,0 +1,40 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+)
+
+// PreprocessOptions selects the clean-up steps applied to informal text, such
+// as social media posts, before analysis. Enabled steps run in field order.
+type PreprocessOptions struct {
+	StripZeroWidth  bool `json:"strip_zero_width,omitempty"` // Remove zero-width spaces and joiners, except in emoji
+	Normalize       bool `json:"normalize,omitempty"`        // PyThaiNLP's normalize: reorder and dedupe vowels and tone marks
+	CollapseRepeats int  `json:"collapse_repeats,omitempty"` // Cap runs of one character at this length, e.g. 3 turns 555555 into 555 (0: off)
+	ExpandMaiyamok  bool `json:"expand_maiyamok,omitempty"`  // Repeat the word before ๆ: เด็กๆ becomes เด็กเด็ก
+	SeparateEmoji   bool `json:"separate_emoji,omitempty"`   // Put spaces around emoji sequences
+}
+
+// SocialMediaPreprocessing enables every step, keeping up to 3 repeated
+// characters
+func SocialMediaPreprocessing() PreprocessOptions {
+	return PreprocessOptions{
+		StripZeroWidth:  true,
+		Normalize:       true,
+		CollapseRepeats: 3,
+		ExpandMaiyamok:  true,
+		SeparateEmoji:   true,
+	}
+}
+
+// Preprocess applies the steps of opts to text and returns the result
+func (pm *PyThaiNLPManager) Preprocess(ctx context.Context, text string, opts PreprocessOptions) (string, error) {
+	if err := pm.ensureReady(ctx); err != nil {
+		return "", err
+	}
+	text, err := pm.client.Preprocess(ctx, &PreprocessRequest{Text: text, Preprocess: &opts})
+	if err != nil {
+		return "", fmt.Errorf("preprocessing failed: %w", err)
+	}
+	return text, nil
+}
@@ -0,0 +1,35 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// protocolVersion is the service API version this library speaks. Bump it
+	// together with PROTOCOL_VERSION in server.py on incompatible changes.
+	protocolVersion = 2
+
+	// protocolHeader carries protocolVersion on every request
+	protocolHeader = "X-PyThaiNLP-Protocol"
+)
+
+// checkProtocol verifies that the running service speaks protocolVersion
+func (pm *PyThaiNLPManager) checkProtocol(ctx context.Context) error {
+	health, err := pm.client.Health(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query service protocol: %w", err)
+	}
+
+	switch {
+	case health.Protocol < protocolVersion:
+		return fmt.Errorf("%w: service speaks protocol %d, this library needs %d; "+
+			"the cached image is outdated, update it with PullImage and InitRecreate",
+			ErrProtocolMismatch, health.Protocol, protocolVersion)
+	case health.Protocol > protocolVersion:
+		return fmt.Errorf("%w: service speaks protocol %d, this library supports %d; "+
+			"upgrade github.com/tassa-yoniso-manasi-karoto/go-pythainlp",
+			ErrProtocolMismatch, health.Protocol, protocolVersion)
+	}
+	return nil
+}
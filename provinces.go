@@ -0,0 +1,114 @@
+package pythainlp
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp/internal/provinces"
+)
+
+// Region is one of the six geographical regions of Thailand
+type Region string
+
+const (
+	RegionNorth     Region = "north"
+	RegionNortheast Region = "northeast"
+	RegionCentral   Region = "central" // Including Bangkok
+	RegionEast      Region = "east"
+	RegionWest      Region = "west"
+	RegionSouth     Region = "south"
+)
+
+// Province is a Thai province, or Bangkok. Districts and subdistricts are
+// not covered, as PyThaiNLP has no corpus for them.
+type Province struct {
+	Thai    string `json:"thai"`    // e.g. เชียงใหม่
+	RTGS    string `json:"rtgs"`    // Royal Thai General System romanization, e.g. Chon Buri
+	English string `json:"english"` // Usual English spelling, e.g. Chonburi
+	Region  Region `json:"region"`
+
+	// ThaiAbbreviation and EnglishAbbreviation, e.g. กทม and BKK, come from
+	// PyThaiNLP's corpus and are empty in LocalProvinces
+	ThaiAbbreviation    string `json:"thai_abbreviation,omitempty"`
+	EnglishAbbreviation string `json:"english_abbreviation,omitempty"`
+}
+
+var (
+	provincesOnce  sync.Once
+	localProvinces []Province
+)
+
+// LocalProvinces returns the provinces embedded in the package, without the
+// service and without abbreviations, in Thai alphabetical order. The list is
+// the one thaivalid.Provinces is read from.
+func LocalProvinces() []Province {
+	provincesOnce.Do(func() {
+		for _, r := range provinces.Rows() {
+			localProvinces = append(localProvinces, Province{Thai: r.Thai, RTGS: r.RTGS, English: r.English, Region: Region(r.Region)})
+		}
+	})
+	return append([]Province(nil), localProvinces...)
+}
+
+// LookupProvince finds a province by its Thai, RTGS or English name, ignoring
+// case, spaces and a leading จังหวัด or จ., e.g. "จ.เชียงใหม่" or "chonburi".
+// It uses the embedded list.
+func LookupProvince(name string) (Province, bool) {
+	key := provinceKey(name)
+	for _, p := range LocalProvinces() {
+		if key == provinceKey(p.Thai) || key == provinceKey(p.RTGS) || key == provinceKey(p.English) {
+			return p, true
+		}
+	}
+	switch key {
+	case "กทม", "กรุงเทพ", "กรุงเทพฯ":
+		return LookupProvince("กรุงเทพมหานคร")
+	}
+	return Province{}, false
+}
+
+func provinceKey(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.TrimPrefix(name, "จังหวัด")
+	name = strings.TrimPrefix(name, "จ.")
+	name = strings.ReplaceAll(name, ".", "")
+	return strings.ToLower(strings.Join(strings.Fields(name), ""))
+}
+
+// Provinces returns the provinces of PyThaiNLP's corpus, completed with the
+// RTGS names and regions of LocalProvinces. When the service cannot answer,
+// it logs a warning and returns LocalProvinces instead.
+func (pm *PyThaiNLPManager) Provinces(ctx context.Context) ([]Province, error) {
+	local := LocalProvinces()
+	err := pm.ensureReady(ctx)
+	var corpus []CorpusProvince
+	if err == nil {
+		corpus, err = pm.client.Provinces(ctx)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		Logger.Warn().Err(err).Msg("Province corpus unavailable, using the embedded list")
+		return local, nil
+	}
+
+	byName := make(map[string]int, len(local))
+	for i, p := range local {
+		byName[p.Thai] = i
+	}
+	for _, c := range corpus {
+		i, ok := byName[c.NameTH]
+		if !ok {
+			local = append(local, Province{Thai: c.NameTH, English: c.NameEN})
+			i = len(local) - 1
+		}
+		if c.NameEN != "" {
+			local[i].English = c.NameEN
+		}
+		local[i].ThaiAbbreviation = c.AbbrTH
+		local[i].EnglishAbbreviation = c.AbbrEN
+	}
+	return local, nil
+}
@@ -0,0 +1,93 @@
+package pythainlp
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// ProxyConfig holds the HTTP(S) proxy settings used for the image pull and
+// for downloads made inside the container (pip, PyThaiNLP corpora)
+type ProxyConfig struct {
+	HTTPProxy  string // Proxy URL for plain HTTP requests
+	HTTPSProxy string // Proxy URL for HTTPS requests
+	NoProxy    string // Comma-separated hosts that bypass the proxy
+}
+
+// IsZero returns whether no proxy is configured
+func (p ProxyConfig) IsZero() bool {
+	return p.HTTPProxy == "" && p.HTTPSProxy == "" && p.NoProxy == ""
+}
+
+// WithProxy sets the proxy used by the service container and checked
+// against the Docker daemon configuration before pulling
+func WithProxy(cfg ProxyConfig) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.proxy = cfg
+	}
+}
+
+// WithProxyFromEnvironment reads HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// (or their lowercase forms) from the host environment
+func WithProxyFromEnvironment() ManagerOption {
+	return WithProxy(ProxyConfig{
+		HTTPProxy:  getenvAny("HTTP_PROXY", "http_proxy"),
+		HTTPSProxy: getenvAny("HTTPS_PROXY", "https_proxy"),
+		NoProxy:    getenvAny("NO_PROXY", "no_proxy"),
+	})
+}
+
+// getenvAny returns the value of the first non-empty environment variable
+func getenvAny(keys ...string) string {
+	for _, key := range keys {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// env returns the proxy variables in both spellings since pip, requests
+// and urllib do not agree on which one they read
+func (p ProxyConfig) env() map[string]string {
+	env := make(map[string]string)
+	set := func(key, value string) {
+		if value != "" {
+			env[key] = value
+			env[strings.ToLower(key)] = value
+		}
+	}
+	set("HTTP_PROXY", p.HTTPProxy)
+	set("HTTPS_PROXY", p.HTTPSProxy)
+	set("NO_PROXY", p.NoProxy)
+	return env
+}
+
+// checkDaemonProxy warns when a proxy is configured for the manager but the
+// Docker daemon, which performs the actual image pull, has none
+func (pm *PyThaiNLPManager) checkDaemonProxy(ctx context.Context) {
+	if pm.proxy.IsZero() || pm.offline {
+		return
+	}
+
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		Logger.Debug().Err(err).Msg("Failed to get Docker client for proxy check")
+		return
+	}
+	defer dockerClient.Close()
+
+	info, err := dockerClient.Info(ctx)
+	if err != nil {
+		Logger.Debug().Err(err).Msg("Failed to query Docker daemon info for proxy check")
+		return
+	}
+
+	if info.HTTPProxy == "" && info.HTTPSProxy == "" {
+		Logger.Warn().
+			Str("http_proxy", pm.proxy.HTTPProxy).
+			Str("https_proxy", pm.proxy.HTTPSProxy).
+			Msg("A proxy is configured but the Docker daemon has none: the image pull may fail. " +
+				"Configure the daemon proxy (Docker Desktop settings or /etc/systemd/system/docker.service.d/http-proxy.conf)")
+	}
+}
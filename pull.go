@@ -0,0 +1,299 @@
+package pythainlp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/tassa-yoniso-manasi-karoto/dockerutil"
+)
+
+// PullPhase identifies the stage of an image pull
+type PullPhase string
+
+const (
+	PullPhaseWaiting     PullPhase = "waiting"
+	PullPhaseDownloading PullPhase = "downloading"
+	PullPhaseExtracting  PullPhase = "extracting"
+	PullPhaseComplete    PullPhase = "complete"
+)
+
+// LayerProgress reports the state of a single image layer.
+// Current and Total refer to the layer's current phase, as reported by the daemon.
+type LayerProgress struct {
+	ID      string    `json:"id"`
+	Phase   PullPhase `json:"phase"`
+	Current int64     `json:"current"`
+	Total   int64     `json:"total"`
+}
+
+// PullProgress is a detailed snapshot of an ongoing image pull
+type PullProgress struct {
+	Phase           PullPhase       `json:"phase"`            // Downloading until every layer is fetched, then extracting
+	Current         int64           `json:"current"`          // Bytes downloaded across all layers
+	Total           int64           `json:"total"`            // Total size of the layers whose size is known
+	CompletedLayers int             `json:"completed_layers"` // Layers fully downloaded and extracted
+	TotalLayers     int             `json:"total_layers"`
+	Layers          []LayerProgress `json:"layers"` // In the order the daemon announced them
+	ETA             time.Duration   `json:"eta"`    // Estimated download time remaining, 0 if unknown
+	Status          string          `json:"status"` // Raw status of the last daemon message
+}
+
+// WithPullProgress sets a callback receiving detailed, per-layer pull progress.
+// It is called alongside the callback set with WithDownloadProgressCallback.
+func WithPullProgress(cb func(PullProgress)) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.pullProgressCallback = cb
+	}
+}
+
+// layerState tracks a layer across the daemon's progress messages
+type layerState struct {
+	LayerProgress
+	size       int64 // Compressed size, learned while downloading
+	downloaded int64
+}
+
+// pullTracker aggregates daemon messages into PullProgress snapshots.
+// It survives retries so already fetched layers are not counted twice.
+type pullTracker struct {
+	order  []string
+	layers map[string]*layerState
+	start  time.Time
+	// bytes already downloaded when the tracker started, excluded from the rate
+	baseline int64
+}
+
+func newPullTracker() *pullTracker {
+	return &pullTracker{
+		layers: make(map[string]*layerState),
+		start:  time.Now(),
+	}
+}
+
+// update applies a daemon message to the tracked layer state
+func (t *pullTracker) update(msg jsonmessage.JSONMessage) {
+	if msg.ID == "" {
+		return
+	}
+	layer, ok := t.layers[msg.ID]
+	if !ok {
+		layer = &layerState{LayerProgress: LayerProgress{ID: msg.ID, Phase: PullPhaseWaiting}}
+		t.layers[msg.ID] = layer
+		t.order = append(t.order, msg.ID)
+	}
+
+	switch msg.Status {
+	case "Pulling fs layer", "Waiting":
+		layer.Phase = PullPhaseWaiting
+	case "Downloading":
+		layer.Phase = PullPhaseDownloading
+		if msg.Progress != nil {
+			layer.size = msg.Progress.Total
+			layer.downloaded = msg.Progress.Current
+		}
+	case "Verifying Checksum", "Download complete":
+		layer.Phase = PullPhaseDownloading
+		layer.downloaded = layer.size
+	case "Extracting":
+		layer.Phase = PullPhaseExtracting
+		layer.downloaded = layer.size
+	case "Pull complete":
+		layer.Phase = PullPhaseComplete
+		layer.downloaded = layer.size
+	case "Already exists":
+		layer.Phase = PullPhaseComplete
+		// Cached layers are not downloaded and their size is unknown
+	}
+
+	layer.Current, layer.Total = 0, 0
+	if msg.Progress != nil {
+		layer.Current, layer.Total = msg.Progress.Current, msg.Progress.Total
+	}
+	if layer.Phase == PullPhaseComplete {
+		layer.Current, layer.Total = layer.size, layer.size
+	}
+}
+
+// snapshot returns the aggregated progress
+func (t *pullTracker) snapshot(status string) PullProgress {
+	p := PullProgress{
+		Phase:       PullPhaseComplete,
+		TotalLayers: len(t.order),
+		Layers:      make([]LayerProgress, 0, len(t.order)),
+		Status:      status,
+	}
+
+	var downloading, extracting bool
+	for _, id := range t.order {
+		layer := t.layers[id]
+		p.Layers = append(p.Layers, layer.LayerProgress)
+		p.Current += layer.downloaded
+		p.Total += layer.size
+		switch layer.Phase {
+		case PullPhaseWaiting, PullPhaseDownloading:
+			downloading = true
+		case PullPhaseExtracting:
+			extracting = true
+		case PullPhaseComplete:
+			p.CompletedLayers++
+		}
+	}
+
+	switch {
+	case downloading:
+		p.Phase = PullPhaseDownloading
+	case extracting:
+		p.Phase = PullPhaseExtracting
+	case len(t.order) == 0:
+		p.Phase = PullPhaseWaiting
+	}
+
+	// Sizes of waiting layers are unknown, so the ETA is only a lower bound until
+	// every layer has started downloading
+	if elapsed := time.Since(t.start); p.Phase == PullPhaseDownloading && elapsed > time.Second {
+		rate := float64(p.Current-t.baseline) / elapsed.Seconds()
+		if rate > 0 && p.Total > p.Current {
+			p.ETA = time.Duration(float64(p.Total-p.Current) / rate * float64(time.Second))
+		}
+	}
+
+	return p
+}
+
+// hasCustomPull returns whether the image must be pulled by the manager itself
+// rather than left to dockerutil's defaults during Init
+func (pm *PyThaiNLPManager) hasCustomPull() bool {
+	return pm.registryCredentials != nil || pm.pullOptions != nil || pm.pullProgressCallback != nil
+}
+
+// pull pulls the image through the Docker API directly, which unlike
+// dockerutil supports registry credentials and per-layer progress
+func (pm *PyThaiNLPManager) pull(ctx context.Context) error {
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to get Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	if _, err := dockerClient.ImageInspect(ctx, pm.image); err == nil {
+		Logger.Debug().Str("image", pm.image).Msg("Image already exists, skipping pull")
+		return nil
+	}
+
+	opts := pm.pullOpts()
+	interval := opts.InitialInterval
+	if interval == 0 {
+		interval = 10 * time.Second
+	}
+	// Unlimited retries need a time limit, as in dockerutil
+	maxRetries := opts.MaxRetries
+	if maxRetries == 0 && opts.MaxElapsedTime <= 0 {
+		maxRetries = dockerutil.DefaultPullOptions().MaxRetries
+	}
+	if opts.MaxElapsedTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, opts.MaxElapsedTime, fmt.Errorf("image pull took longer than %s", opts.MaxElapsedTime))
+		defer cancel()
+	}
+	tracker := newPullTracker()
+
+	for attempt := uint64(1); ; attempt++ {
+		err := pm.pullAttempt(ctx, dockerClient, tracker, opts.ClientTimeout)
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w: %w", context.Cause(ctx), err)
+		}
+		if maxRetries > 0 && attempt > maxRetries {
+			return err
+		}
+
+		Logger.Warn().Err(err).Str("image", pm.image).Dur("next_retry_in", interval).Msg("Image pull failed, retrying...")
+		if opts.OnRetry != nil {
+			opts.OnRetry(err, interval)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", context.Cause(ctx), err)
+		case <-time.After(interval):
+		}
+		if interval *= 2; opts.MaxInterval > 0 && interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+		tracker.baseline = tracker.snapshot("").Current
+		tracker.start = time.Now()
+	}
+
+	if _, err := dockerClient.ImageInspect(ctx, pm.image); err != nil {
+		return fmt.Errorf("image verification failed after pull: %w", err)
+	}
+
+	Logger.Info().Str("image", pm.image).Msg("Image pull complete and verified")
+	return nil
+}
+
+// pullAttempt is pullOnce limited to timeout, if positive
+func (pm *PyThaiNLPManager) pullAttempt(ctx context.Context, dockerClient *client.Client, tracker *pullTracker, timeout time.Duration) error {
+	if timeout <= 0 {
+		return pm.pullOnce(ctx, dockerClient, tracker)
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, fmt.Errorf("pull attempt took longer than %s", timeout))
+	defer cancel()
+	err := pm.pullOnce(ctx, dockerClient, tracker)
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", context.Cause(ctx), err)
+	}
+	return err
+}
+
+// pullOnce performs a single pull attempt, reporting progress as it goes
+func (pm *PyThaiNLPManager) pullOnce(ctx context.Context, dockerClient *client.Client, tracker *pullTracker) error {
+	pullOptions := image.PullOptions{}
+	if pm.registryCredentials != nil {
+		auth, err := pm.registryAuth(ctx)
+		if err != nil {
+			return err
+		}
+		pullOptions.RegistryAuth = auth
+	}
+
+	Logger.Info().Str("image", pm.image).Msg("Pulling Docker image")
+
+	reader, err := dockerClient.ImagePull(ctx, pm.image, pullOptions)
+	if err != nil {
+		return fmt.Errorf("failed to initiate pull: %w", err)
+	}
+	defer reader.Close()
+
+	decoder := json.NewDecoder(reader)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to decode pull progress: %w", err)
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("pull error for layer %s: %s", msg.ID, msg.Error.Message)
+		}
+
+		tracker.update(msg)
+		progress := tracker.snapshot(msg.Status)
+		pm.startupProgress(StartupPull, string(progress.Phase), progress.Current, progress.Total)
+		if pm.pullProgressCallback != nil {
+			pm.pullProgressCallback(progress)
+		}
+		if pm.downloadProgressCallback != nil && progress.Total > 0 {
+			pm.downloadProgressCallback(progress.Current, progress.Total,
+				fmt.Sprintf("%s (%d/%d layers)", progress.Phase, progress.CompletedLayers, progress.TotalLayers))
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+)
+
+// PurgeOptions controls what Purge removes besides the containers
+type PurgeOptions struct {
+	// KeepImage leaves the service image in the local Docker cache
+	KeepImage bool
+	// RemoveData also deletes the data directory with downloaded corpora and models
+	RemoveData bool
+}
+
+// PurgeResult reports what Purge removed
+type PurgeResult struct {
+	ImageRemoved bool
+	ImageBytes   int64 // Size of the removed image
+	DataRemoved  bool
+	DataBytes    int64 // Size of the removed data directory
+}
+
+// ReclaimedBytes returns the total disk space freed
+func (r *PurgeResult) ReclaimedBytes() int64 {
+	return r.ImageBytes + r.DataBytes
+}
+
+// Purge removes the service containers, the image and optionally the data
+// directory. The manager cannot be used afterwards and must be recreated.
+func (pm *PyThaiNLPManager) Purge(ctx context.Context, opts PurgeOptions) (*PurgeResult, error) {
+	pm.mu.Lock()
+	pm.serviceReady = false
+	pm.mu.Unlock()
+	pm.setHealth(HealthStopped)
+
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	result := &PurgeResult{}
+
+	// The data volume is measured before it is emptied, from the container if
+	// it still runs
+	namedVolume := pm.usesDataVolume()
+	var volumeBytes int64
+	if opts.RemoveData && namedVolume {
+		if _, models, _, caches, err := pm.volumeUsage(ctx, dockerClient); err == nil {
+			volumeBytes = models + caches
+		} else if volumeBytes, err = volumeSize(ctx, dockerClient, pm.dataVolume()); err != nil {
+			Logger.Debug().Err(err).Msg("Could not measure the data volume")
+		}
+	}
+
+	if opts.RemoveData {
+		// Files written by the container may be owned by root on the host,
+		// so empty the bind mount from inside while the container still runs
+		if _, err := pm.execCommand(ctx, dockerClient, []string{"find", "/workspace", "-mindepth", "1", "-delete"}); err != nil {
+			Logger.Debug().Err(err).Msg("Could not clear data directory from inside the container")
+		}
+	}
+
+	Logger.Info().Str("project", pm.projectName).Msg("Removing containers")
+	if err := pm.docker.Down(); err != nil {
+		return nil, fmt.Errorf("failed to remove containers: %w", err)
+	}
+
+	// The data volume is external, so Down keeps it and it is removed here
+	if opts.RemoveData && namedVolume {
+		volumeName := pm.dataVolume()
+		if err := dockerClient.VolumeRemove(ctx, volumeName, false); err != nil && !client.IsErrNotFound(err) {
+			return result, fmt.Errorf("failed to remove data volume %s: %w", volumeName, err)
+		}
+	}
+
+	if !opts.KeepImage {
+		size, err := removeImage(ctx, dockerClient, pm.image)
+		if err != nil {
+			return result, err
+		}
+		result.ImageRemoved = size > 0
+		result.ImageBytes = size
+	}
+
+	if opts.RemoveData {
+		size, err := dirSize(pm.dataDir)
+		if err != nil {
+			return result, fmt.Errorf("failed to measure data directory: %w", err)
+		}
+		if err := os.RemoveAll(pm.dataDir); err != nil {
+			return result, fmt.Errorf("failed to remove data directory %s: %w", pm.dataDir, err)
+		}
+		result.DataRemoved = true
+		result.DataBytes = size + volumeBytes
+	}
+
+	Logger.Info().
+		Int64("reclaimed_bytes", result.ReclaimedBytes()).
+		Bool("image_removed", result.ImageRemoved).
+		Bool("data_removed", result.DataRemoved).
+		Msg("Purge complete")
+	return result, nil
+}
+
+// removeImage deletes ref from the local cache and returns its size, or 0 if
+// it was not present
+func removeImage(ctx context.Context, dockerClient *client.Client, ref string) (int64, error) {
+	inspect, err := dockerClient.ImageInspect(ctx, ref)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to inspect image %s: %w", ref, err)
+	}
+
+	Logger.Info().Str("image", ref).Msg("Removing image")
+	if _, err := dockerClient.ImageRemove(ctx, ref, image.RemoveOptions{PruneChildren: true}); err != nil {
+		return 0, fmt.Errorf("failed to remove image %s: %w", ref, err)
+	}
+	return inspect.Size, nil
+}
@@ -0,0 +1,279 @@
+package pythainlptest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp"
+)
+
+// Fake is a deterministic in-memory pythainlp.ThaiNLP for unit tests that
+// should not need Docker. By default it splits text on whitespace and at
+// boundaries between Thai and other scripts, and romanizes letter by letter
+// with a fixed table; that output is stable but is not any real engine's.
+// Tests that depend on specific segmentations or romanizations register them
+// in the maps, keyed by the exact input text. Configure the Fake before use:
+// it is safe for concurrent calls but not for concurrent modification.
+type Fake struct {
+	Tokens    map[string][]string // Tokenize results
+	Romanized map[string]string   // Romanization of texts and of individual tokens
+	Phonetic  map[string]string   // Transliterate results
+	Syllables map[string][]string // SyllableTokenize results
+
+	// Err, if set, is returned by every method
+	Err error
+}
+
+var _ pythainlp.ThaiNLP = (*Fake)(nil)
+
+// NewFake returns a Fake with empty maps
+func NewFake() *Fake {
+	return &Fake{
+		Tokens:    make(map[string][]string),
+		Romanized: make(map[string]string),
+		Phonetic:  make(map[string]string),
+		Syllables: make(map[string][]string),
+	}
+}
+
+// Tokenize splits text with the default engine
+func (f *Fake) Tokenize(ctx context.Context, text string, opts ...pythainlp.CallOption) (*pythainlp.TokenizeResult, error) {
+	return f.TokenizeWithOptions(ctx, text, pythainlp.NewTokenizeOptions(opts...))
+}
+
+// TokenizeWithOptions splits text, reporting the requested engine
+func (f *Fake) TokenizeWithOptions(ctx context.Context, text string, opts pythainlp.TokenizeOptions) (*pythainlp.TokenizeResult, error) {
+	if err := f.check(ctx, opts.Engine.Validate()); err != nil {
+		return nil, err
+	}
+	raw := f.tokens(text)
+	tokens := make([]pythainlp.Token, len(raw))
+	for i, token := range raw {
+		tokens[i] = pythainlp.Token{Surface: token, IsLexical: isThai(token)}
+	}
+	return &pythainlp.TokenizeResult{
+		Tokens: tokens,
+		Raw:    raw,
+		Meta:   pythainlp.Metadata{Engine: string(cmp(opts.Engine, pythainlp.EngineNewMM))},
+	}, nil
+}
+
+// Romanize romanizes text with the default engine
+func (f *Fake) Romanize(ctx context.Context, text string, opts ...pythainlp.CallOption) (*pythainlp.RomanizeResult, error) {
+	return f.RomanizeWithOptions(ctx, text, pythainlp.NewRomanizeOptions(opts...))
+}
+
+// RomanizeWithOptions romanizes text, token by token if TokenizeFirst,
+// Overrides or ProperNouns are set. It finds no entities.
+func (f *Fake) RomanizeWithOptions(ctx context.Context, text string, opts pythainlp.RomanizeOptions) (*pythainlp.RomanizeResult, error) {
+	if err := f.check(ctx, opts.Engine.Validate()); err != nil {
+		return nil, err
+	}
+	result := &pythainlp.RomanizeResult{Meta: pythainlp.Metadata{Engine: string(cmp(opts.Engine, pythainlp.EngineRoyin))}}
+	if !opts.TokenizeFirst && len(opts.Overrides) == 0 && !opts.ProperNouns {
+		result.Text = f.romanize(text)
+		return result, nil
+	}
+	result.Tokens = f.tokens(text)
+	result.RomanizedParts = f.romanizeAll(result.Tokens)
+	for i, token := range result.Tokens {
+		if romanized, ok := opts.Overrides[token]; ok {
+			result.RomanizedParts[i] = romanized
+		}
+	}
+	result.Text = strings.Join(result.RomanizedParts, " ")
+	return result, nil
+}
+
+// Transliterate transliterates text with the default engine
+func (f *Fake) Transliterate(ctx context.Context, text string, opts ...pythainlp.CallOption) (*pythainlp.TransliterateResult, error) {
+	return f.TransliterateWithOptions(ctx, text, pythainlp.NewTransliterateOptions(opts...))
+}
+
+// TransliterateWithOptions returns the registered phonetic form, or the
+// romanization between slashes
+func (f *Fake) TransliterateWithOptions(ctx context.Context, text string, opts pythainlp.TransliterateOptions) (*pythainlp.TransliterateResult, error) {
+	if err := f.check(ctx, opts.Engine.Validate()); err != nil {
+		return nil, err
+	}
+	return &pythainlp.TransliterateResult{
+		Phonetic: f.phonetic(text),
+		Meta:     pythainlp.Metadata{Engine: string(cmp(opts.Engine, pythainlp.EngineThaig2p))},
+	}, nil
+}
+
+// SyllableTokenize splits text into syllables with the default engine
+func (f *Fake) SyllableTokenize(ctx context.Context, text string, opts ...pythainlp.CallOption) (*pythainlp.SyllableTokenizeResult, error) {
+	return f.SyllableTokenizeWithOptions(ctx, text, pythainlp.NewSyllableTokenizeOptions(opts...))
+}
+
+// SyllableTokenizeWithOptions returns the registered syllables, or the tokens
+func (f *Fake) SyllableTokenizeWithOptions(ctx context.Context, text string, opts pythainlp.SyllableTokenizeOptions) (*pythainlp.SyllableTokenizeResult, error) {
+	if err := f.check(ctx, opts.Engine.Validate()); err != nil {
+		return nil, err
+	}
+	return &pythainlp.SyllableTokenizeResult{
+		Syllables: f.syllables(text),
+		Meta:      pythainlp.Metadata{Engine: string(cmp(opts.Engine, pythainlp.EngineSyllableHanSolo))},
+	}, nil
+}
+
+// AnalyzeText tokenizes and romanizes text
+func (f *Fake) AnalyzeText(ctx context.Context, text string, opts ...pythainlp.CallOption) (*pythainlp.AnalyzeResult, error) {
+	return f.AnalyzeWithOptions(ctx, text, pythainlp.NewAnalyzeOptions(opts...))
+}
+
+// AnalyzeWithOptions combines the other methods like the service does
+func (f *Fake) AnalyzeWithOptions(ctx context.Context, text string, opts pythainlp.AnalyzeOptions) (*pythainlp.AnalyzeResult, error) {
+	if err := f.check(ctx, errors.Join(
+		opts.TokenizeEngine.Validate(),
+		opts.RomanizeEngine.Validate(),
+		opts.TransliterateEngine.Validate(),
+		opts.SyllableEngine.Validate(),
+	)); err != nil {
+		return nil, err
+	}
+
+	features := opts.Features
+	if len(features) == 0 {
+		features = []string{"tokenize", "romanize"}
+	}
+	result := &pythainlp.AnalyzeResult{
+		Features: features,
+		Meta:     pythainlp.Metadata{Engine: string(cmp(opts.TokenizeEngine, pythainlp.EngineNewMM))},
+	}
+
+	tokens := f.tokens(text)
+	if slices.Contains(features, "tokenize") {
+		result.RawTokens = tokens
+	}
+	if slices.Contains(features, "romanize") {
+		result.RomanizedParts = f.romanizeAll(tokens)
+		result.Romanized = strings.Join(result.RomanizedParts, " ")
+	}
+	if slices.Contains(features, "transliterate") {
+		result.Phonetic = f.phonetic(text)
+		for _, token := range tokens {
+			result.PhoneticParts = append(result.PhoneticParts, f.phonetic(token))
+		}
+	}
+	if slices.Contains(features, "syllable") {
+		result.Syllables = f.syllables(text)
+	}
+
+	if len(result.RawTokens) > 0 {
+		result.Tokens = make([]pythainlp.Token, len(result.RawTokens))
+		for i, token := range result.RawTokens {
+			result.Tokens[i] = pythainlp.Token{Surface: token, IsLexical: isThai(token)}
+			if i < len(result.RomanizedParts) {
+				result.Tokens[i].Romanization = result.RomanizedParts[i]
+			}
+			if i < len(result.PhoneticParts) {
+				result.Tokens[i].IPA = result.PhoneticParts[i]
+			}
+		}
+	}
+	return result, nil
+}
+
+// check returns f.Err, then the context error, then err
+func (f *Fake) check(ctx context.Context, err error) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+func (f *Fake) tokens(text string) []string {
+	if tokens, ok := f.Tokens[text]; ok {
+		return tokens
+	}
+	var tokens []string
+	for _, field := range strings.Fields(text) {
+		start := 0
+		runes := []rune(field)
+		for i := 1; i < len(runes); i++ {
+			if isThaiRune(runes[i]) != isThaiRune(runes[i-1]) {
+				tokens = append(tokens, string(runes[start:i]))
+				start = i
+			}
+		}
+		tokens = append(tokens, string(runes[start:]))
+	}
+	return tokens
+}
+
+func (f *Fake) romanize(text string) string {
+	if romanized, ok := f.Romanized[text]; ok {
+		return romanized
+	}
+	var b strings.Builder
+	for _, r := range text {
+		if latin, ok := romanTable[r]; ok {
+			b.WriteString(latin)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (f *Fake) romanizeAll(tokens []string) []string {
+	parts := make([]string, len(tokens))
+	for i, token := range tokens {
+		parts[i] = f.romanize(token)
+	}
+	return parts
+}
+
+func (f *Fake) phonetic(text string) string {
+	if phonetic, ok := f.Phonetic[text]; ok {
+		return phonetic
+	}
+	return "/" + f.romanize(text) + "/"
+}
+
+func (f *Fake) syllables(text string) []string {
+	if syllables, ok := f.Syllables[text]; ok {
+		return syllables
+	}
+	return f.tokens(text)
+}
+
+// cmp returns e, or def if e is empty
+func cmp[E ~string](e, def E) E {
+	if e == "" {
+		return def
+	}
+	return e
+}
+
+func isThai(s string) bool {
+	return strings.IndexFunc(s, isThaiRune) >= 0
+}
+
+func isThaiRune(r rune) bool {
+	return unicode.Is(unicode.Thai, r)
+}
+
+// romanTable maps each Thai letter to a Latin approximation. Tone marks and
+// other signs map to nothing.
+var romanTable = map[rune]string{
+	'ก': "k", 'ข': "kh", 'ฃ': "kh", 'ค': "kh", 'ฅ': "kh", 'ฆ': "kh", 'ง': "ng",
+	'จ': "ch", 'ฉ': "ch", 'ช': "ch", 'ซ': "s", 'ฌ': "ch", 'ญ': "y",
+	'ฎ': "d", 'ฏ': "t", 'ฐ': "th", 'ฑ': "th", 'ฒ': "th", 'ณ': "n",
+	'ด': "d", 'ต': "t", 'ถ': "th", 'ท': "th", 'ธ': "th", 'น': "n",
+	'บ': "b", 'ป': "p", 'ผ': "ph", 'ฝ': "f", 'พ': "ph", 'ฟ': "f", 'ภ': "ph", 'ม': "m",
+	'ย': "y", 'ร': "r", 'ฤ': "rue", 'ล': "l", 'ฦ': "lue", 'ว': "w",
+	'ศ': "s", 'ษ': "s", 'ส': "s", 'ห': "h", 'ฬ': "l", 'อ': "o", 'ฮ': "h",
+	'ะ': "a", 'ั': "a", 'า': "a", 'ำ': "am", 'ิ': "i", 'ี': "i", 'ึ': "ue", 'ื': "ue",
+	'ุ': "u", 'ู': "u", 'เ': "e", 'แ': "ae", 'โ': "o", 'ใ': "ai", 'ไ': "ai",
+	'ๅ': "", 'ฯ': "", 'ๆ': "", '็': "", '่': "", '้': "", '๊': "", '๋': "", '์': "", 'ํ': "", 'ฺ': "",
+	'๐': "0", '๑': "1", '๒': "2", '๓': "3", '๔': "4", '๕': "5", '๖': "6", '๗': "7", '๘': "8", '๙': "9",
+}
@@ -0,0 +1,88 @@
+// Package pythainlptest helps test code that uses PyThaiNLP.
+//
+// New starts throwaway instances for integration tests against the real
+// Docker engine.
+//
+// Each instance gets a unique project and container name, so packages can run
+// their tests in parallel, and is removed when the test finishes:
+//
+//	func TestTokenize(t *testing.T) {
+//		mgr := pythainlptest.New(t)
+//		res, err := mgr.Tokenize(context.Background(), "สวัสดีครับ")
+//		...
+//	}
+//
+// Starting an instance takes a few seconds once the image is cached, so a
+// suite should share one instance across subtests rather than call New in
+// each of them.
+//
+// Fake implements pythainlp.ThaiNLP in memory for unit tests of code that
+// accepts the interface:
+//
+//	fake := pythainlptest.NewFake()
+//	fake.Tokens["สวัสดีครับ"] = []string{"สวัสดี", "ครับ"}
+//	got := myapp.CountWords(ctx, fake, "สวัสดีครับ")
+package pythainlptest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp"
+)
+
+const (
+	// StartupTimeout bounds image pull and service startup. It is generous
+	// because the first run on a machine has to pull the image.
+	StartupTimeout = 15 * time.Minute
+
+	// QueryTimeout bounds each request made by the tests
+	QueryTimeout = 30 * time.Second
+)
+
+// New starts an ephemeral instance and registers its removal with t.Cleanup.
+// The test is skipped in -short mode, when the manager cannot be created and
+// when Docker is not running, is too old or has no image for the machine's
+// architecture; a failure to start the instance fails the test. Options are applied after the defaults.
+func New(t testing.TB, opts ...pythainlp.ManagerOption) *pythainlp.PyThaiNLPManager {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("skipping PyThaiNLP integration test in short mode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), StartupTimeout)
+	defer cancel()
+
+	defaults := []pythainlp.ManagerOption{
+		pythainlp.WithEphemeralInstance(),
+		pythainlp.WithQueryTimeout(QueryTimeout),
+		pythainlp.WithStartupTimeout(StartupTimeout),
+	}
+	mgr, err := pythainlp.NewManager(ctx, append(defaults, opts...)...)
+	if err != nil {
+		// Unless a version option is invalid, NewManager fails on the machine:
+		// no data directory, free port or Docker client
+		t.Skipf("pythainlptest: failed to create manager: %v", err)
+	}
+
+	if _, err := mgr.CheckEnvironment(ctx); errors.Is(err, pythainlp.ErrDockerNotRunning) ||
+		errors.Is(err, pythainlp.ErrDockerAPITooOld) || errors.Is(err, pythainlp.ErrUnsupportedArchitecture) {
+		t.Skipf("pythainlptest: %v", err)
+	}
+
+	// Registered before Init so a half-started instance is removed too
+	t.Cleanup(func() {
+		if err := mgr.Close(); err != nil {
+			t.Logf("pythainlptest: failed to remove instance %s: %v", mgr.ProjectName(), err)
+		}
+	})
+
+	if err := mgr.Init(ctx); err != nil {
+		t.Fatalf("pythainlptest: failed to start instance %s: %v", mgr.ProjectName(), err)
+	}
+
+	return mgr
+}
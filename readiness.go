@@ -0,0 +1,80 @@
+package pythainlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// Exit codes of readinessProbe
+const (
+	probeReady    = 0 // server.py is listening
+	probeStarting = 1
+	probeCrashed  = 2 // server.py exited crashLoopRestarts times
+)
+
+// readinessProbe prints the startup status server.py last wrote, then exits
+// with probeReady once it listens or probeCrashed once it keeps exiting. The
+// supervisor removes the status when server.py exits, so it is never stale.
+var readinessProbe = fmt.Sprintf(`cat %[1]s 2>/dev/null; echo
+grep -q '"phase": "ready"' %[1]s 2>/dev/null && exit %[3]d
+read restarts code < %[2]s 2>/dev/null && [ "$restarts" -ge %[4]d ] && exit %[5]d
+exit %[6]d`, startupStatusPath, serviceStatusPath, probeReady, crashLoopRestarts, probeCrashed, probeStarting)
+
+// probeService runs readinessProbe in the container, records the phase
+// server.py reports while it starts and returns the exit code of the probe
+func (pm *PyThaiNLPManager) probeService(ctx context.Context, dockerClient *client.Client) (int, error) {
+	output, err := pm.execCommand(ctx, dockerClient, []string{readinessProbe})
+	code := probeReady
+	var execErr *execError
+	if errors.As(err, &execErr) {
+		code = execErr.exitCode
+	} else if err != nil {
+		return 0, err
+	}
+
+	// The manager records the ready phase itself once the service answers
+	line, _, _ := bytes.Cut(output, []byte("\n"))
+	var s serviceStartup
+	if code != probeReady && json.Unmarshal(line, &s) == nil && s.Phase != "" {
+		pm.startupProgress(s.Phase, s.Message, s.Current, s.Total)
+	}
+	return code, nil
+}
+
+// watchContainer reports on the returned channel the container dying or
+// running out of memory, until ctx is done. Without docker events, such as
+// with an engine refusing them, startup failures are still found by probing.
+func (pm *PyThaiNLPManager) watchContainer(ctx context.Context, dockerClient *client.Client) <-chan error {
+	messages, errs := dockerClient.Events(ctx, events.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("type", string(events.ContainerEventType)),
+			filters.Arg("container", pm.containerName),
+			filters.Arg("event", string(events.ActionDie)),
+			filters.Arg("event", string(events.ActionOOM)),
+		),
+	})
+
+	died := make(chan error, 1)
+	go func() {
+		select {
+		case msg := <-messages:
+			if msg.Action == events.ActionOOM {
+				died <- fmt.Errorf("container %s ran out of memory: %w", pm.containerName, ErrContainerCrashed)
+				return
+			}
+			died <- fmt.Errorf("container %s exited with code %s: %w", pm.containerName, msg.Actor.Attributes["exitCode"], ErrContainerCrashed)
+		case err := <-errs:
+			if ctx.Err() == nil {
+				Logger.Debug().Err(err).Msg("Docker events unavailable, relying on readiness probes")
+			}
+		}
+	}()
+	return died
+}
@@ -0,0 +1,101 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/registry"
+	"github.com/tassa-yoniso-manasi-karoto/dockerutil"
+)
+
+// RegistryCredentials holds the credentials used to pull from a private registry
+type RegistryCredentials struct {
+	Username      string
+	Password      string
+	IdentityToken string // OAuth identity token, used instead of Username/Password
+}
+
+// RegistryCredentialsFunc returns the credentials for the given registry host.
+// It is called on every authenticated pull so short-lived tokens can be refreshed.
+type RegistryCredentialsFunc func(ctx context.Context, registryHost string) (RegistryCredentials, error)
+
+// WithRegistryMirror pulls the image through a mirror instead of ghcr.io.
+// The mirror must expose the same repository path, e.g. "registry.corp:5000"
+// yields "registry.corp:5000/tassa-yoniso-manasi-karoto/langkit-pythainlp:latest".
+func WithRegistryMirror(mirror string) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.image = mirrorImage(ghcrImage, mirror)
+	}
+}
+
+// WithRegistryCredentials sets a callback supplying pull credentials
+func WithRegistryCredentials(fn RegistryCredentialsFunc) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.registryCredentials = fn
+	}
+}
+
+// WithPullOptions overrides the retry and timeout settings used for image
+// pulls. MaxElapsedTime bounds the pull, retries included, and ClientTimeout
+// each attempt. A zero MaxRetries retries until MaxElapsedTime, or is the
+// default of 3 without it.
+func WithPullOptions(opts dockerutil.PullOptions) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.pullOptions = &opts
+	}
+}
+
+// Image returns the image reference used by the manager
+func (pm *PyThaiNLPManager) Image() string {
+	return pm.image
+}
+
+// mirrorImage swaps the registry host of an image reference for the mirror's
+func mirrorImage(ref, mirror string) string {
+	mirror = strings.TrimSuffix(mirror, "/")
+	mirror = strings.TrimPrefix(strings.TrimPrefix(mirror, "https://"), "http://")
+	if mirror == "" {
+		return ref
+	}
+	_, path, _ := strings.Cut(ref, "/")
+	return mirror + "/" + path
+}
+
+// registryHost returns the registry host part of an image reference
+func registryHost(ref string) string {
+	host, _, _ := strings.Cut(ref, "/")
+	return host
+}
+
+// pullOpts returns the pull options configured for this manager
+func (pm *PyThaiNLPManager) pullOpts() dockerutil.PullOptions {
+	opts := dockerutil.DefaultPullOptions()
+	if pm.pullOptions != nil {
+		opts = *pm.pullOptions
+	}
+	if pm.downloadProgressCallback != nil {
+		opts.OnProgress = pm.downloadProgressCallback
+	}
+	return opts
+}
+
+// registryAuth returns the encoded credentials for the image's registry
+func (pm *PyThaiNLPManager) registryAuth(ctx context.Context) (string, error) {
+	host := registryHost(pm.image)
+	creds, err := pm.registryCredentials(ctx, host)
+	if err != nil {
+		return "", fmt.Errorf("failed to get registry credentials for %s: %w", host, err)
+	}
+
+	auth, err := registry.EncodeAuthConfig(registry.AuthConfig{
+		Username:      creds.Username,
+		Password:      creds.Password,
+		IdentityToken: creds.IdentityToken,
+		ServerAddress: host,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode registry credentials: %w", err)
+	}
+	return auth, nil
+}
@@ -0,0 +1,25 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+)
+
+// ReloadService restarts the Python process of the service, but not its
+// container, and waits for it to be ready. The embedded server.py is copied
+// into the container if it differs from the one the image ships, and the
+// plugins of WithPlugins are copied again, so a rebuilt program or edited
+// plugins take effect in seconds rather than the time a container restart
+// takes. Loaded models are lost and reload on first use.
+func (pm *PyThaiNLPManager) ReloadService(ctx context.Context) error {
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to get Docker client: %w", err)
+	}
+
+	Logger.Info().Msg("Reloading service")
+	if err := pm.restartService(ctx, dockerClient); err != nil {
+		return fmt.Errorf("failed to reload service: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package pythainlp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// recordEnv, set to 1, makes a manager with WithReplayDir query the real
+// service and overwrite existing recordings
+const recordEnv = "PYTHAINLP_RECORD"
+
+// WithReplayDir answers requests from recordings in dir. A request without a
+// recording starts the service, is sent to it, and its response is saved in
+// dir for later runs; with every response recorded, Init and all requests
+// work without Docker. Set PYTHAINLP_RECORD=1 to record again against the
+// real engine, e.g. after upgrading PyThaiNLP.
+//
+// Recordings are keyed by endpoint and request body, one JSON file each, and
+// are meant to be committed alongside the tests that use them.
+func WithReplayDir(dir string) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.replayDir = dir
+	}
+}
+
+// recording is the file format of one recorded exchange
+type recording struct {
+	Method   string          `json:"method"`
+	Path     string          `json:"path"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Status   int             `json:"status"`
+	Response json.RawMessage `json:"response"`
+}
+
+// replayTransport serves recorded responses and records missing ones
+type replayTransport struct {
+	dir    string
+	record bool
+	start  func(ctx context.Context) error
+	next   http.RoundTripper
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+	path := filepath.Join(t.dir, recordingName(req.Method, req.URL.Path, body))
+
+	if !t.record {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			var rec recording
+			if err := json.Unmarshal(data, &rec); err != nil {
+				return nil, fmt.Errorf("invalid recording %s: %w", path, err)
+			}
+			return rec.response(req), nil
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read recording: %w", err)
+		}
+	}
+
+	if err := t.start(req.Context()); err != nil {
+		return nil, fmt.Errorf("no recording at %s and the service could not start: %w", path, err)
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	rec := recording{
+		Method:   req.Method,
+		Path:     req.URL.Path,
+		Request:  rawJSON(body),
+		Status:   resp.StatusCode,
+		Response: rawJSON(respBody),
+	}
+	if err := rec.save(path); err != nil {
+		Logger.Warn().Err(err).Str("path", path).Msg("Failed to save recording")
+	} else {
+		Logger.Debug().Str("path", path).Msg("Recorded response")
+	}
+	return resp, nil
+}
+
+// recordingName derives the file name from the endpoint and a hash of the
+// request, so that the same call maps to the same file in every run
+func recordingName(method, path string, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", method, path)
+	h.Write(body)
+	endpoint := strings.ReplaceAll(strings.Trim(path, "/"), "/", "_")
+	return fmt.Sprintf("%s-%x.json", endpoint, h.Sum(nil)[:8])
+}
+
+// rawJSON returns b as a JSON value, or nil if it is not one so that the
+// field is omitted rather than producing an invalid file
+func rawJSON(b []byte) json.RawMessage {
+	if !json.Valid(b) {
+		return nil
+	}
+	return b
+}
+
+func (rec recording) save(path string) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func (rec recording) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(rec.Response)),
+		ContentLength: int64(len(rec.Response)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,158 @@
+package pythainlp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ResourceUsage reports the resource consumption of the service container
+type ResourceUsage struct {
+	CPUPercent   float64 `json:"cpu_percent"`    // 100 per fully used core
+	MemoryRSS    uint64  `json:"memory_rss"`     // Resident anonymous memory, excluding page cache
+	MemoryUsage  uint64  `json:"memory_usage"`   // Total cgroup usage, including page cache
+	MemoryLimit  uint64  `json:"memory_limit"`   // Container limit, or the VM/host memory if unlimited
+	ModelDirSize int64   `json:"model_dir_size"` // Bytes in the PyThaiNLP data directory
+}
+
+// MemoryPercent returns the RSS as a percentage of the memory limit
+func (u ResourceUsage) MemoryPercent() float64 {
+	if u.MemoryLimit == 0 {
+		return 0
+	}
+	return float64(u.MemoryRSS) / float64(u.MemoryLimit) * 100
+}
+
+// ResourceUsage samples CPU and memory usage through the Docker stats API and
+// measures the model directory. The sample takes about one second since the
+// daemon needs two CPU readings to compute a percentage.
+func (pm *PyThaiNLPManager) ResourceUsage(ctx context.Context) (*ResourceUsage, error) {
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	resp, err := dockerClient.ContainerStats(ctx, pm.containerName, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container stats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var stats container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("failed to parse container stats: %w", err)
+	}
+
+	usage := &ResourceUsage{
+		CPUPercent:  cpuPercent(stats),
+		MemoryRSS:   memoryRSS(stats.MemoryStats),
+		MemoryUsage: stats.MemoryStats.Usage,
+		MemoryLimit: stats.MemoryStats.Limit,
+	}
+
+	if pm.usesDataVolume() {
+		_, usage.ModelDirSize, _, _, err = pm.volumeUsage(ctx, dockerClient)
+	} else {
+		usage.ModelDirSize, err = dirSize(pm.modelDir())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure model directory: %w", err)
+	}
+
+	return usage, nil
+}
+
+// ServiceInfo reports what the service itself knows: uptime, request
+// counters, queue depth and the models it has loaded with their approximate
+// memory footprint. Unlike other methods it neither starts a lazy or idle
+// service nor counts as activity for the idle timeout.
+func (pm *PyThaiNLPManager) ServiceInfo(ctx context.Context) (*HealthResponse, error) {
+	if !pm.IsReady() {
+		return nil, ErrServiceNotReady
+	}
+	info, err := pm.client.Health(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query service info: %w", err)
+	}
+	return info, nil
+}
+
+// ServiceStats reports the requests, errors and average latency of each
+// endpoint of the service, and how often engine calls found their model
+// loaded. Latencies are measured in the service, without the HTTP round trip.
+// Like ServiceInfo it leaves a stopped service stopped.
+func (pm *PyThaiNLPManager) ServiceStats(ctx context.Context) (*StatsResponse, error) {
+	if !pm.IsReady() {
+		return nil, ErrServiceNotReady
+	}
+	stats, err := pm.client.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query service stats: %w", err)
+	}
+	return stats, nil
+}
+
+// modelDir returns the host path of the PyThaiNLP data directory
+func (pm *PyThaiNLPManager) modelDir() string {
+	return filepath.Join(pm.dataDir, "pythainlp-data")
+}
+
+// cpuPercent computes the CPU usage the same way `docker stats` does
+func cpuPercent(stats container.StatsResponse) float64 {
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+
+	cpus := float64(stats.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	return cpuDelta / systemDelta * cpus * 100
+}
+
+// memoryRSS returns the resident memory from cgroup v1 ("rss") or v2 ("anon") stats
+func memoryRSS(mem container.MemoryStats) uint64 {
+	if rss, ok := mem.Stats["rss"]; ok {
+		return rss
+	}
+	if anon, ok := mem.Stats["anon"]; ok {
+		return anon
+	}
+	// Fall back to usage minus reclaimable page cache
+	if inactive := mem.Stats["inactive_file"]; inactive < mem.Usage {
+		return mem.Usage - inactive
+	}
+	return mem.Usage
+}
+
+// dirSize returns the total size of the regular files under root,
+// or 0 if root does not exist yet
+func dirSize(root string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,172 @@
+package pythainlp
+
+import "cmp"
+
+// Metadata describes how the service produced a result
+type Metadata struct {
+	Engine         string                 `json:"engine"`                  // Engine that produced the result
+	ProcessingTime float64                `json:"processing_time_ms"`      // Time spent in the service, in milliseconds
+	Version        string                 `json:"version,omitempty"`       // PyThaiNLP version
+	CacheHit       bool                   `json:"cache_hit,omitempty"`     // Whether the engines used were already loaded in the service
+	RequestID      string                 `json:"request_id,omitempty"`    // ID of the service request, to match its logs and audit records
+	Deterministic  bool                   `json:"deterministic,omitempty"` // Reproducible output, see WithSeed
+	Granularity    Granularity            `json:"granularity,omitempty"`   // Compound segmentation, if not the engine's
+	Extra          map[string]interface{} `json:"extra,omitempty"`         // Other metadata reported for the call
+}
+
+// Result is implemented by the results of the text-processing calls, for
+// code handling or logging them generically
+type Result interface {
+	Engine() string
+	ProcessingTime() float64 // Milliseconds
+	Metadata() Metadata
+}
+
+var (
+	_ Result = (*TokenizeResult)(nil)
+	_ Result = (*RomanizeResult)(nil)
+	_ Result = (*TransliterateResult)(nil)
+	_ Result = (*SyllableTokenizeResult)(nil)
+	_ Result = (*AnalyzeResult)(nil)
+	_ Result = (*ReverseTransliterateResult)(nil)
+	_ Result = (*SyllablePhoneticsResult)(nil)
+	_ Result = (*DocumentResult)(nil)
+	_ Result = (*ParagraphResult)(nil)
+	_ Result = (*NERResult)(nil)
+	_ Result = (*PersonNamesResult)(nil)
+	_ Result = (*PIIResult)(nil)
+	_ Result = (*WordDifficultyResult)(nil)
+)
+
+// newMetadata reads the metadata of a service response. The engine reported
+// there, such as the one EngineAuto chose or a fallback engine, takes
+// precedence over the engine requested.
+func newMetadata(m ResponseMeta, engine string) Metadata {
+	return Metadata{
+		Engine:         cmp.Or(m.Engine, engine),
+		ProcessingTime: m.ProcessingTimeMS,
+		Version:        m.ModelVersion,
+		CacheHit:       m.CacheHit,
+		RequestID:      m.RequestID,
+		Deterministic:  m.Deterministic,
+		Granularity:    Granularity(m.Granularity),
+		Extra:          m.Extra,
+	}
+}
+
+// Engine returns the engine that produced the result
+func (r *TokenizeResult) Engine() string { return r.Meta.Engine }
+
+// ProcessingTime returns the time spent in the service, in milliseconds
+func (r *TokenizeResult) ProcessingTime() float64 { return r.Meta.ProcessingTime }
+
+// Metadata returns the metadata of the result
+func (r *TokenizeResult) Metadata() Metadata { return r.Meta }
+
+// Engine returns the engine that produced the result
+func (r *RomanizeResult) Engine() string { return r.Meta.Engine }
+
+// ProcessingTime returns the time spent in the service, in milliseconds
+func (r *RomanizeResult) ProcessingTime() float64 { return r.Meta.ProcessingTime }
+
+// Metadata returns the metadata of the result
+func (r *RomanizeResult) Metadata() Metadata { return r.Meta }
+
+// Engine returns the engine that produced the result
+func (r *TransliterateResult) Engine() string { return r.Meta.Engine }
+
+// ProcessingTime returns the time spent in the service, in milliseconds
+func (r *TransliterateResult) ProcessingTime() float64 { return r.Meta.ProcessingTime }
+
+// Metadata returns the metadata of the result
+func (r *TransliterateResult) Metadata() Metadata { return r.Meta }
+
+// Engine returns the engine that produced the result
+func (r *SyllableTokenizeResult) Engine() string { return r.Meta.Engine }
+
+// ProcessingTime returns the time spent in the service, in milliseconds
+func (r *SyllableTokenizeResult) ProcessingTime() float64 { return r.Meta.ProcessingTime }
+
+// Metadata returns the metadata of the result
+func (r *SyllableTokenizeResult) Metadata() Metadata { return r.Meta }
+
+// Engine returns the tokenization engine of the analysis
+func (r *AnalyzeResult) Engine() string { return r.Meta.Engine }
+
+// ProcessingTime returns the time spent in the service, in milliseconds
+func (r *AnalyzeResult) ProcessingTime() float64 { return r.Meta.ProcessingTime }
+
+// Metadata returns the metadata of the result
+func (r *AnalyzeResult) Metadata() Metadata { return r.Meta }
+
+// Engine returns the engine that produced the result
+func (r *ReverseTransliterateResult) Engine() string { return r.Meta.Engine }
+
+// ProcessingTime returns the time spent in the service, in milliseconds
+func (r *ReverseTransliterateResult) ProcessingTime() float64 { return r.Meta.ProcessingTime }
+
+// Metadata returns the metadata of the result
+func (r *ReverseTransliterateResult) Metadata() Metadata { return r.Meta }
+
+// Engine returns the syllable engine that produced the result
+func (r *SyllablePhoneticsResult) Engine() string { return r.Meta.Engine }
+
+// ProcessingTime returns the time spent in the service, in milliseconds
+func (r *SyllablePhoneticsResult) ProcessingTime() float64 { return r.Meta.ProcessingTime }
+
+// Metadata returns the metadata of the result
+func (r *SyllablePhoneticsResult) Metadata() Metadata { return r.Meta }
+
+// Engine returns the word engine that produced the result
+func (r *DocumentResult) Engine() string { return r.Meta.Engine }
+
+// ProcessingTime returns the time spent in the service, in milliseconds
+func (r *DocumentResult) ProcessingTime() float64 { return r.Meta.ProcessingTime }
+
+// Metadata returns the metadata of the result
+func (r *DocumentResult) Metadata() Metadata { return r.Meta }
+
+// Engine returns the paragraph engine that produced the result
+func (r *ParagraphResult) Engine() string { return r.Meta.Engine }
+
+// ProcessingTime returns the time spent in the service, in milliseconds
+func (r *ParagraphResult) ProcessingTime() float64 { return r.Meta.ProcessingTime }
+
+// Metadata returns the metadata of the result
+func (r *ParagraphResult) Metadata() Metadata { return r.Meta }
+
+// Engine returns the NER engine that produced the result
+func (r *NERResult) Engine() string { return r.Meta.Engine }
+
+// ProcessingTime returns the time spent in the service, in milliseconds
+func (r *NERResult) ProcessingTime() float64 { return r.Meta.ProcessingTime }
+
+// Metadata returns the metadata of the result
+func (r *NERResult) Metadata() Metadata { return r.Meta }
+
+// Engine returns the NER engine that produced the result
+func (r *PersonNamesResult) Engine() string { return r.Meta.Engine }
+
+// ProcessingTime returns the time spent in the service, in milliseconds
+func (r *PersonNamesResult) ProcessingTime() float64 { return r.Meta.ProcessingTime }
+
+// Metadata returns the metadata of the result
+func (r *PersonNamesResult) Metadata() Metadata { return r.Meta }
+
+// Engine returns the NER engine that produced the result
+func (r *PIIResult) Engine() string { return r.Meta.Engine }
+
+// ProcessingTime returns the time spent in the service, in milliseconds
+func (r *PIIResult) ProcessingTime() float64 { return r.Meta.ProcessingTime }
+
+// Metadata returns the metadata of the result
+func (r *PIIResult) Metadata() Metadata { return r.Meta }
+
+// Engine returns the corpus the difficulty is based on
+func (r *WordDifficultyResult) Engine() string { return r.Meta.Engine }
+
+// ProcessingTime returns the time spent in the service, in milliseconds
+func (r *WordDifficultyResult) ProcessingTime() float64 { return r.Meta.ProcessingTime }
+
+// Metadata returns the metadata of the result
+func (r *WordDifficultyResult) Metadata() Metadata { return r.Meta }
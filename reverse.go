@@ -0,0 +1,68 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+)
+
+// ReverseLanguage is the language of romanized text given to
+// ReverseTransliterate
+type ReverseLanguage string
+
+const (
+	ReverseJapanese   ReverseLanguage = "jp" // Hepburn romaji
+	ReverseKorean     ReverseLanguage = "ko" // Revised Romanization
+	ReverseVietnamese ReverseLanguage = "vi" // Vietnamese alphabet
+	ReverseMandarin   ReverseLanguage = "zh" // Pinyin
+)
+
+// ReverseTransliterateOptions configures ReverseTransliterate
+type ReverseTransliterateOptions struct {
+	Lang  ReverseLanguage        // Language of the input (default Japanese)
+	Extra map[string]interface{} // Options of wunsen's transliterate, e.g. "jp_input" or "zh_sandhi"
+}
+
+// ReverseTransliterateResult contains text written in Thai script
+type ReverseTransliterateResult struct {
+	Thai string
+	Lang ReverseLanguage
+
+	Meta Metadata `json:"metadata"`
+}
+
+// ReverseTransliterate writes romanized Japanese in Thai script, following
+// the Royal Institute's transcription rules, e.g. for input methods or to
+// expand search queries with the Thai spelling of foreign names
+func (pm *PyThaiNLPManager) ReverseTransliterate(ctx context.Context, text string) (*ReverseTransliterateResult, error) {
+	return pm.ReverseTransliterateWithOptions(ctx, text, ReverseTransliterateOptions{Lang: ReverseJapanese})
+}
+
+// ReverseTransliterateWithOptions writes romanized text of any supported
+// language in Thai script. It uses the wunsen engine, which is only installed
+// in full mode; otherwise the error wraps ErrEngineUnavailable. PyThaiNLP has
+// no engine turning romanized Thai back into Thai script.
+func (pm *PyThaiNLPManager) ReverseTransliterateWithOptions(ctx context.Context, text string, opts ReverseTransliterateOptions) (*ReverseTransliterateResult, error) {
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+
+	req := &ReverseTransliterateRequest{
+		Text:    text,
+		Lang:    string(opts.Lang),
+		Options: opts.Extra,
+	}
+	if req.Lang == "" {
+		req.Lang = string(ReverseJapanese)
+	}
+
+	resp, err := pm.client.ReverseTransliterate(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("reverse transliteration failed: %w", err)
+	}
+
+	return &ReverseTransliterateResult{
+		Thai: resp.Thai,
+		Lang: ReverseLanguage(req.Lang),
+		Meta: newMetadata(resp.Metadata, "wunsen"),
+	}, nil
+}
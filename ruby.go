@@ -0,0 +1,65 @@
+package pythainlp
+
+import (
+	"html"
+	"strings"
+)
+
+// RubyFormat selects the markup produced by RenderRuby
+type RubyFormat int
+
+const (
+	// RubyHTML produces <ruby> elements, with <rp> parentheses for browsers
+	// without ruby support
+	RubyHTML RubyFormat = iota
+	// RubyText produces the plain-text format of Anki's furigana filter,
+	// " token[annotation]", where the space before each annotated token marks
+	// where its base text starts
+	RubyText
+)
+
+// RubyOptions configures RenderRuby
+type RubyOptions struct {
+	Format RubyFormat
+	// IPA annotates tokens with Token.IPA instead of Token.Romanization
+	IPA bool
+}
+
+// RenderRuby interleaves the tokens of an analysis with their romanization
+// (or IPA) as ruby markup, for language-learning material. The analysis must
+// include the tokenize feature, and romanize or, for IPA, transliterate.
+// Tokens without an annotation, such as punctuation and foreign words, are
+// rendered as plain text.
+func RenderRuby(res *AnalyzeResult, opts RubyOptions) string {
+	var b strings.Builder
+	for _, token := range res.Tokens {
+		annotation := token.Romanization
+		if opts.IPA {
+			annotation = token.IPA
+		}
+		if !token.IsLexical || annotation == "" || annotation == token.Surface {
+			if opts.Format == RubyHTML {
+				b.WriteString(html.EscapeString(token.Surface))
+			} else {
+				b.WriteString(token.Surface)
+			}
+			continue
+		}
+
+		switch opts.Format {
+		case RubyHTML:
+			b.WriteString("<ruby>")
+			b.WriteString(html.EscapeString(token.Surface))
+			b.WriteString("<rp>(</rp><rt>")
+			b.WriteString(html.EscapeString(annotation))
+			b.WriteString("</rt><rp>)</rp></ruby>")
+		case RubyText:
+			b.WriteString(" ")
+			b.WriteString(token.Surface)
+			b.WriteString("[")
+			b.WriteString(annotation)
+			b.WriteString("]")
+		}
+	}
+	return strings.TrimPrefix(b.String(), " ")
+}
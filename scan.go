@@ -0,0 +1,131 @@
+package pythainlp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// scanChunkSize is the size above which ScanThaiWords tokenizes buffered
+	// input that contains no whitespace
+	scanChunkSize = 16 * 1024
+
+	// scanCacheSize is the number of chunks whose words ScanThaiWords keeps
+	scanCacheSize = 256
+)
+
+// ScanThaiWords returns a bufio.SplitFunc that splits input into words with
+// the tokenizer of nlp, dropping whitespace like bufio.ScanWords. Input is
+// tokenized a chunk at a time, up to the last whitespace buffered, and the
+// words of a chunk are kept until the scanner has consumed them, so a chunk
+// costs a single request. The words of the last chunks are also cached, so
+// that a chunk seen before, such as a repeated line of input read line by
+// line, costs none. Requests are made with ctx.
+//
+// The returned function keeps state and must be used by one Scanner only.
+//
+//	scanner := bufio.NewScanner(file)
+//	scanner.Split(pythainlp.ScanThaiWords(ctx, manager, pythainlp.NewTokenizeOptions()))
+//	for scanner.Scan() {
+//		fmt.Println(scanner.Text())
+//	}
+func ScanThaiWords(ctx context.Context, nlp ThaiNLP, opts TokenizeOptions) bufio.SplitFunc {
+	var (
+		words    []Span // pending words, with byte offsets into the chunk
+		chunkLen int    // length of the chunk the words come from
+		consumed int    // bytes of the chunk already returned to the scanner
+		cache    = NewResultCache(scanCacheSize)
+	)
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if len(words) == 0 {
+			// Skip leading whitespace
+			start := 0
+			for start < len(data) {
+				r, size := utf8.DecodeRune(data[start:])
+				if !unicode.IsSpace(r) {
+					break
+				}
+				start += size
+			}
+			if start > 0 {
+				return start, nil, nil
+			}
+			end := scanBoundary(data, atEOF)
+			if end == 0 {
+				return 0, nil, nil // request more data
+			}
+			chunk := string(data[:end])
+			if cachedWords, ok := cache.get(chunk); ok {
+				words = cachedWords.([]Span)
+			} else {
+				res, err := nlp.TokenizeWithOptions(ctx, chunk, opts)
+				if err != nil {
+					return 0, nil, err
+				}
+				if words, err = byteSpans(chunk, res.Raw); err != nil {
+					return 0, nil, err
+				}
+				cache.add(chunk, words)
+			}
+			chunkLen, consumed = end, 0
+			if len(words) == 0 {
+				return end, nil, nil
+			}
+		}
+
+		word := words[0]
+		words = words[1:]
+		token = data[word.Start-consumed : word.End-consumed]
+		end := word.End
+		if len(words) == 0 {
+			end = chunkLen // also consume the whitespace ending the chunk
+		}
+		advance = end - consumed
+		consumed = end
+		return advance, token, nil
+	}
+}
+
+// scanBoundary returns the length of the prefix of data to tokenize: up to
+// and including its last whitespace, or 0 if more data is needed
+func scanBoundary(data []byte, atEOF bool) int {
+	if atEOF {
+		return len(data)
+	}
+	boundary, last := 0, 0
+	for i := 0; i < len(data) && utf8.FullRune(data[i:]); {
+		r, size := utf8.DecodeRune(data[i:])
+		i += size
+		last = i
+		if unicode.IsSpace(r) {
+			boundary = i
+		}
+	}
+	if boundary == 0 && last >= scanChunkSize {
+		return last
+	}
+	return boundary
+}
+
+// byteSpans locates the non-whitespace tokens in chunk, in bytes
+func byteSpans(chunk string, tokens []string) ([]Span, error) {
+	var spans []Span
+	pos := 0
+	for _, token := range tokens {
+		if strings.TrimSpace(token) == "" {
+			continue
+		}
+		i := strings.Index(chunk[pos:], token)
+		if i < 0 {
+			return nil, fmt.Errorf("token %q not found in input after byte %d", token, pos)
+		}
+		pos += i
+		spans = append(spans, Span{Start: pos, End: pos + len(token), Token: Token{Surface: token}})
+		pos += len(token)
+	}
+	return spans, nil
+}
@@ -0,0 +1,201 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Script classifies the characters of a ScriptSpan
+type Script int
+
+const (
+	ScriptThai  Script = iota // Thai letters, vowels, tone marks, digits and signs
+	ScriptLatin               // Latin letters, accented ones included
+	ScriptDigit               // Non-Thai decimal digits
+	ScriptPunct               // Punctuation and symbols
+	ScriptSpace               // Whitespace
+	ScriptOther               // Anything else, e.g. CJK
+)
+
+func (s Script) String() string {
+	switch s {
+	case ScriptThai:
+		return "thai"
+	case ScriptLatin:
+		return "latin"
+	case ScriptDigit:
+		return "digit"
+	case ScriptPunct:
+		return "punct"
+	case ScriptSpace:
+		return "space"
+	default:
+		return "other"
+	}
+}
+
+// ScriptSpan is a run of characters of the same Script. Start and End are
+// byte offsets, so Text == text[Start:End].
+type ScriptSpan struct {
+	Script Script
+	Start  int
+	End    int
+	Text   string
+}
+
+// ScriptSpans splits text into contiguous runs of the same script, without
+// calling the service. Together the spans cover all of text.
+func ScriptSpans(text string) []ScriptSpan {
+	var spans []ScriptSpan
+	for i, r := range text {
+		script := scriptOf(r)
+		if n := len(spans); n > 0 && spans[n-1].Script == script {
+			spans[n-1].End = i + utf8.RuneLen(r)
+			continue
+		}
+		spans = append(spans, ScriptSpan{Script: script, Start: i, End: i + utf8.RuneLen(r)})
+	}
+	for i := range spans {
+		spans[i].Text = text[spans[i].Start:spans[i].End]
+	}
+	return spans
+}
+
+func scriptOf(r rune) Script {
+	switch {
+	case unicode.Is(unicode.Thai, r):
+		return ScriptThai
+	case unicode.Is(unicode.Latin, r):
+		return ScriptLatin
+	case unicode.IsDigit(r):
+		return ScriptDigit
+	case unicode.IsSpace(r):
+		return ScriptSpace
+	case unicode.IsPunct(r) || unicode.IsSymbol(r):
+		return ScriptPunct
+	default:
+		return ScriptOther
+	}
+}
+
+// analyzeThaiSpans implements AnalyzeOptions.ThaiOnly: each Thai span is
+// analyzed on its own, and every other run of non-space characters is passed
+// to opts.NonThai or kept as a single non-lexical token
+func (pm *PyThaiNLPManager) analyzeThaiSpans(ctx context.Context, text string, opts AnalyzeOptions) (*AnalyzeResult, error) {
+	opts.ThaiOnly = false
+	if len(opts.Features) == 0 {
+		opts.Features = []string{"tokenize", "romanize"}
+	}
+	romanize := slices.Contains(opts.Features, "romanize")
+	transliterate := slices.Contains(opts.Features, "transliterate")
+	syllable := slices.Contains(opts.Features, "syllable")
+	lemma := slices.Contains(opts.Features, "lemma")
+
+	result := &AnalyzeResult{Features: opts.Features}
+	var phonetic, analyzed strings.Builder
+	for _, seg := range thaiSegments(text) {
+		var tokens []Token
+		switch {
+		case seg.Script == ScriptThai:
+			res, err := pm.AnalyzeWithOptions(ctx, seg.Text, opts)
+			if err != nil {
+				return nil, err
+			}
+			tokens = res.Tokens
+			if res.Text != "" {
+				analyzed.WriteString(res.Text)
+			} else {
+				analyzed.WriteString(seg.Text)
+			}
+			result.Syllables = append(result.Syllables, res.Syllables...)
+			phonetic.WriteString(res.Phonetic)
+			result.Meta.ProcessingTime += res.Meta.ProcessingTime
+			result.Meta.Engine = res.Meta.Engine
+		case seg.Script != ScriptSpace && opts.NonThai != nil:
+			var err error
+			if tokens, err = opts.NonThai(ctx, seg.Text); err != nil {
+				return nil, fmt.Errorf("non-Thai segment at byte %d: %w", seg.Start, err)
+			}
+		default:
+			tokens = []Token{{Surface: seg.Text}}
+			if romanize {
+				tokens[0].Romanization = seg.Text
+			}
+			if transliterate {
+				tokens[0].IPA = seg.Text
+			}
+			if lemma && seg.Script != ScriptSpace {
+				tokens[0].Lemma = strings.ToLower(seg.Text)
+			}
+		}
+
+		if seg.Script != ScriptThai {
+			analyzed.WriteString(seg.Text)
+			for _, token := range tokens {
+				phonetic.WriteString(token.IPA)
+				if syllable {
+					result.Syllables = append(result.Syllables, token.Surface)
+				}
+			}
+		}
+		alignTokens(seg, tokens)
+		for _, token := range tokens {
+			result.Tokens = append(result.Tokens, token)
+			result.RawTokens = append(result.RawTokens, token.Surface)
+			if romanize {
+				result.RomanizedParts = append(result.RomanizedParts, token.Romanization)
+			}
+			if transliterate {
+				result.PhoneticParts = append(result.PhoneticParts, token.IPA)
+			}
+		}
+	}
+	// As computed by the service
+	result.Romanized = strings.Join(result.RomanizedParts, " ")
+	result.Phonetic = phonetic.String()
+	if opts.Preprocess != nil {
+		result.Text = analyzed.String()
+	}
+	return result, nil
+}
+
+// thaiSegments groups the script spans of text into Thai spans, whitespace
+// spans, and runs of everything else, so that URLs and numbers with
+// punctuation stay whole
+func thaiSegments(text string) []ScriptSpan {
+	var segments []ScriptSpan
+	for _, span := range ScriptSpans(text) {
+		n := len(segments)
+		if n > 0 && isPassThrough(span.Script) && isPassThrough(segments[n-1].Script) {
+			segments[n-1].End = span.End
+			segments[n-1].Text = text[segments[n-1].Start:span.End]
+			segments[n-1].Script = ScriptOther
+			continue
+		}
+		segments = append(segments, span)
+	}
+	return segments
+}
+
+func isPassThrough(s Script) bool {
+	return s != ScriptThai && s != ScriptSpace
+}
+
+// alignTokens sets the offsets of tokens produced from seg. A token whose
+// surface is not found covers the rest of the segment.
+func alignTokens(seg ScriptSpan, tokens []Token) {
+	pos := 0
+	for i := range tokens {
+		if j := strings.Index(seg.Text[pos:], tokens[i].Surface); j >= 0 {
+			tokens[i].Start = seg.Start + pos + j
+			pos += j + len(tokens[i].Surface)
+			tokens[i].End = seg.Start + pos
+		} else {
+			tokens[i].Start, tokens[i].End = seg.Start+pos, seg.End
+		}
+	}
+}
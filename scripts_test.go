@@ -0,0 +1,108 @@
+package pythainlp
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// scriptRun is a span without its offsets, which checkSpans verifies
+type scriptRun struct {
+	Script Script
+	Text   string
+}
+
+// checkSpans reports spans that do not cover text in order or whose Text
+// is not text[Start:End], and returns the spans as runs
+func checkSpans(t *testing.T, name, text string, spans []ScriptSpan) []scriptRun {
+	t.Helper()
+	var runs []scriptRun
+	end := 0
+	for _, s := range spans {
+		if s.Start != end || s.End <= s.Start || s.End > len(text) {
+			t.Errorf("%s(%q): span %+v does not follow byte %d", name, text, s, end)
+			return nil
+		}
+		if text[s.Start:s.End] != s.Text {
+			t.Errorf("%s(%q): span %+v, want text %q", name, text, s, text[s.Start:s.End])
+		}
+		end = s.End
+		runs = append(runs, scriptRun{s.Script, s.Text})
+	}
+	if end != len(text) {
+		t.Errorf("%s(%q): spans end at byte %d, want %d", name, text, end, len(text))
+	}
+	return runs
+}
+
+func TestScriptSpans(t *testing.T) {
+	for _, c := range []struct {
+		text  string
+		spans []scriptRun
+	}{
+		{"", nil},
+		{"ราคา 100 บาท (USD3)", []scriptRun{
+			{ScriptThai, "ราคา"}, {ScriptSpace, " "}, {ScriptDigit, "100"}, {ScriptSpace, " "},
+			{ScriptThai, "บาท"}, {ScriptSpace, " "}, {ScriptPunct, "("}, {ScriptLatin, "USD"},
+			{ScriptDigit, "3"}, {ScriptPunct, ")"},
+		}},
+		{"๑๒ km", []scriptRun{{ScriptThai, "๑๒"}, {ScriptSpace, " "}, {ScriptLatin, "km"}}},
+		{"\tcafé\n", []scriptRun{{ScriptSpace, "\t"}, {ScriptLatin, "café"}, {ScriptSpace, "\n"}}},
+		{"日本ไทย", []scriptRun{{ScriptOther, "日本"}, {ScriptThai, "ไทย"}}},
+	} {
+		if got := checkSpans(t, "ScriptSpans", c.text, ScriptSpans(c.text)); !slices.Equal(got, c.spans) {
+			t.Errorf("ScriptSpans(%q) = %+v, want %+v", c.text, got, c.spans)
+		}
+	}
+}
+
+func TestThaiSegments(t *testing.T) {
+	for _, c := range []struct {
+		text     string
+		segments []scriptRun
+	}{
+		{"ราคา 100 บาท (USD3)", []scriptRun{
+			{ScriptThai, "ราคา"}, {ScriptSpace, " "}, {ScriptDigit, "100"}, {ScriptSpace, " "},
+			{ScriptThai, "บาท"}, {ScriptSpace, " "}, {ScriptOther, "(USD3)"},
+		}},
+		// A URL stays whole
+		{"ไปที่ https://example.com/a?b=1 ครับ", []scriptRun{
+			{ScriptThai, "ไปที่"}, {ScriptSpace, " "}, {ScriptOther, "https://example.com/a?b=1"},
+			{ScriptSpace, " "}, {ScriptThai, "ครับ"},
+		}},
+		{"iPhone15ใหม่", []scriptRun{{ScriptOther, "iPhone15"}, {ScriptThai, "ใหม่"}}},
+	} {
+		if got := checkSpans(t, "thaiSegments", c.text, thaiSegments(c.text)); !slices.Equal(got, c.segments) {
+			t.Errorf("thaiSegments(%q) = %+v, want %+v", c.text, got, c.segments)
+		}
+	}
+}
+
+func TestAlignTokens(t *testing.T) {
+	text := "ฉัน ไปโรงเรียน a b"
+	for _, c := range []struct {
+		name   string
+		seg    string // Found in text
+		tokens []string
+		want   []string // text[Start:End] of each token
+	}{
+		{"Thai", "ไปโรงเรียน", []string{"ไป", "โรงเรียน"}, []string{"ไป", "โรงเรียน"}},
+		{"whitespace not in tokens", "a b", []string{"a", "b"}, []string{"a", "b"}},
+		{"surface not found", "ไปโรงเรียน", []string{"ไป", "รร."}, []string{"ไป", "โรงเรียน"}},
+	} {
+		start := strings.Index(text, c.seg)
+		seg := ScriptSpan{Start: start, End: start + len(c.seg), Text: c.seg}
+		tokens := make([]Token, len(c.tokens))
+		for i, s := range c.tokens {
+			tokens[i].Surface = s
+		}
+		alignTokens(seg, tokens)
+		var got []string
+		for _, tok := range tokens {
+			got = append(got, text[tok.Start:tok.End])
+		}
+		if !slices.Equal(got, c.want) {
+			t.Errorf("%s: %q, want %q", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package pythainlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// SoundexEngine names a Thai soundex algorithm
+type SoundexEngine string
+
+const (
+	SoundexLK82      SoundexEngine = "lk82"
+	SoundexUdom83    SoundexEngine = "udom83"
+	SoundexMetasound SoundexEngine = "metasound"
+)
+
+var soundexEngines = []SoundexEngine{SoundexLK82, SoundexUdom83, SoundexMetasound}
+
+// Validate returns an error wrapping ErrEngineUnavailable if the engine is
+// unknown. The empty engine disables soundex keys and is valid.
+func (e SoundexEngine) Validate() error {
+	return validateEngine("soundex", e, soundexEngines)
+}
+
+// SearchOptions configures NormalizeForSearchWithOptions
+type SearchOptions struct {
+	TokenizeEngine TokenizeEngine // Engine for tokenization (default newmm)
+	KeepStopwords  bool           // Keep PyThaiNLP's Thai stopwords
+	Soundex        SoundexEngine  // Add a soundex key to Thai terms, for fuzzy matching (default none)
+	RomanizeEngine RomanizeEngine // Add a romanization key to Thai terms, for Latin-script queries (default none)
+}
+
+// SearchTerm is an index-ready term
+type SearchTerm struct {
+	Term      string `json:"term"`                // Normalized, lowercased token
+	Position  int    `json:"position"`            // Index among the non-space tokens of the text, gaps included
+	Soundex   string `json:"soundex,omitempty"`   // Soundex key, Thai terms only
+	Romanized string `json:"romanized,omitempty"` // Romanization key, Thai terms only
+}
+
+// NormalizeForSearch turns text into the terms to index, or to query, in a
+// full-text search engine such as Bleve or Elasticsearch: the text is
+// normalized and tokenized, then punctuation and Thai stopwords are dropped
+func (pm *PyThaiNLPManager) NormalizeForSearch(ctx context.Context, text string) ([]SearchTerm, error) {
+	return pm.NormalizeForSearchWithOptions(ctx, text, SearchOptions{})
+}
+
+// NormalizeForSearchWithOptions is NormalizeForSearch with stopword and
+// phonetic key options
+func (pm *PyThaiNLPManager) NormalizeForSearchWithOptions(ctx context.Context, text string, opts SearchOptions) ([]SearchTerm, error) {
+	if err := errors.Join(
+		opts.TokenizeEngine.Validate(),
+		opts.Soundex.Validate(),
+		opts.RomanizeEngine.Validate(),
+	); err != nil {
+		return nil, err
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+
+	req := &SearchTermsRequest{
+		Text:           text,
+		Engine:         string(opts.TokenizeEngine),
+		KeepStopwords:  opts.KeepStopwords,
+		SoundexEngine:  string(opts.Soundex),
+		RomanizeEngine: string(opts.RomanizeEngine),
+	}
+	if req.Engine == "" {
+		req.Engine = string(EngineNewMM)
+	}
+
+	terms, err := pm.client.SearchTerms(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("search normalization failed: %w", err)
+	}
+	return terms, nil
+}
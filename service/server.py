@@ -5,32 +5,416 @@ PyThaiNLP HTTP Service using aiohttp
 Provides RESTful API for Go client
 """
 
+import hmac
+import importlib.util
 import json
+import os
+import re
 import time
 import sys
+import threading
 import traceback
+import uuid
 from aiohttp import web
 import asyncio
+import functools
+from concurrent.futures import ThreadPoolExecutor
 from typing import Dict, List, Any, Optional
 
+def rss_bytes() -> int:
+    """Resident memory of this process, 0 if unavailable"""
+    try:
+        with open("/proc/self/statm") as f:
+            return int(f.read().split()[1]) * os.sysconf("SC_PAGE_SIZE")
+    except (OSError, ValueError, IndexError):
+        return 0
+
+
+# Where the startup phase is written for the Go manager, which reads it while
+# waiting for the service (startupStatusPath in install.go)
+STARTUP_STATUS_PATH = "/tmp/pythainlp-startup.json"
+
+
+def startup_status(phase: str, message: str = "", current: int = 0, total: int = 0) -> None:
+    """Record the startup phase, replacing the file atomically"""
+    try:
+        with open(STARTUP_STATUS_PATH + ".tmp", "w") as f:
+            json.dump({"phase": phase, "message": message, "current": current, "total": total}, f)
+        os.replace(STARTUP_STATUS_PATH + ".tmp", STARTUP_STATUS_PATH)
+    except OSError:
+        pass
+
+
 # Pre-load PyThaiNLP modules at startup
 print("Loading PyThaiNLP modules...", file=sys.stderr)
+startup_status("model_load", "Importing PyThaiNLP")
 start_time = time.time()
 
 try:
-    from pythainlp.tokenize import word_tokenize, syllable_tokenize
+    from pythainlp.tokenize import word_tokenize, syllable_tokenize, sent_tokenize
     from pythainlp.transliterate import romanize, transliterate, pronunciate
+    from pythainlp.util import normalize
     from pythainlp import __version__ as pythainlp_version
+    import pythainlp.corpus.core as _corpus_core
+    
+    # Report corpus downloads, which can take minutes on first use
+    _download = _corpus_core.download
+    
+    def _reporting_download(name, *args, **kwargs):
+        startup_status("corpus_download", f"Downloading {name}")
+        try:
+            return _download(name, *args, **kwargs)
+        finally:
+            startup_status("model_load", f"Downloaded {name}")
+    
+    _corpus_core.download = _reporting_download
+    startup_status("model_load", "Loading newmm and royin")
     
-    # Pre-load engines to warm up
+    # Pre-load engines to warm up, measuring what each one adds to memory
+    _rss = rss_bytes()
     _ = word_tokenize("ทดสอบ", engine="newmm")
+    _newmm_bytes = rss_bytes() - _rss
+    _rss = rss_bytes()
     _ = romanize("ทดสอบ")
+    _royin_bytes = rss_bytes() - _rss
     
     print(f"PyThaiNLP {pythainlp_version} loaded in {time.time() - start_time:.2f}s", file=sys.stderr)
 except Exception as e:
     print(f"Failed to load PyThaiNLP: {e}", file=sys.stderr)
     sys.exit(1)
 
+# Number of times the in-container supervisor restarted this service
+RESTART_COUNT = int(os.environ.get("PYTHAINLP_RESTARTS", "0"))
+
+# Service API version, checked by the Go library. Bump together with
+# protocolVersion in protocol.go on incompatible changes.
+PROTOCOL_VERSION = 2
+
+# Service statistics reported by /health and /stats. ENDPOINT_STATS holds the
+# requests, errors and total latency of each route; MODEL_CACHE counts the
+# engine calls finding their model already loaded (hits) or loading it.
+# Engines run on worker threads, so STATS_LOCK guards these counters and
+# LOADED_MODELS.
+START_TIME = time.time()
+STATS_LOCK = threading.Lock()
+STATS = {"requests": 0, "errors": 0, "in_flight": 0}
+ENDPOINT_STATS: Dict[str, Dict[str, float]] = {}
+MODEL_CACHE = {"hits": 0, "misses": 0}
+
+# Engines loaded so far, keyed by "operation/engine", with the memory their
+# first call added to the process
+LOADED_MODELS: Dict[str, Dict[str, Any]] = {
+    "tokenize/newmm": {"operation": "tokenize", "engine": "newmm", "memory_bytes": max(_newmm_bytes, 0)},
+    "romanize/royin": {"operation": "romanize", "engine": "royin", "memory_bytes": max(_royin_bytes, 0)},
+}
+
+
+def run_engine(operation: str, engine: str, fn, /, *args, **kwargs):
+    """Call an engine function, recording its memory footprint on first use"""
+    key = f"{operation}/{engine}"
+    with STATS_LOCK:
+        loaded = key in LOADED_MODELS
+        MODEL_CACHE["hits" if loaded else "misses"] += 1
+    if loaded:
+        return fn(*args, **kwargs)
+    # The lock is not held while the model loads, which can take minutes
+    before = rss_bytes()
+    result = fn(*args, **kwargs)
+    with STATS_LOCK:
+        LOADED_MODELS.setdefault(key, {
+            "operation": operation,
+            "engine": engine,
+            "memory_bytes": max(rss_bytes() - before, 0),
+        })
+    return result
+
+
+# Engines run in a thread pool so that the event loop stays responsive and,
+# with more than one worker, concurrent requests are served in parallel
+# wherever the engine releases the GIL (CRF, ONNX and torch based ones do)
+SERVICE_WORKERS = max(int(os.environ.get("PYTHAINLP_SERVICE_WORKERS", "1")), 1)
+WORKER_POOL = ThreadPoolExecutor(max_workers=SERVICE_WORKERS, thread_name_prefix="engine")
+
+
+def seeded(fn, /, *args, **kwargs):
+    """Call fn after reseeding the generators, on the thread that runs it"""
+    seed_engines()
+    return fn(*args, **kwargs)
+
+
+async def in_worker(fn, /, *args, **kwargs):
+    """Run fn in the worker pool, with PYTHAINLP_SEED reseeding first"""
+    loop = asyncio.get_running_loop()
+    if SEED is not None:
+        return await loop.run_in_executor(WORKER_POOL, functools.partial(seeded, fn, *args, **kwargs))
+    return await loop.run_in_executor(WORKER_POOL, functools.partial(fn, *args, **kwargs))
+
+
+# Per-instance secret generated by the Go manager; every request must present it
+SERVICE_TOKEN = os.environ.get("PYTHAINLP_SERVICE_TOKEN", "")
+
+# Request limits, advertised by /health so that the Go client checks them
+# before sending. Longer texts and larger batches get a 413 with a
+# TEXT_TOO_LONG or BATCH_TOO_LARGE error instead of failing in an engine.
+MAX_TEXT_LENGTH = int(os.environ.get("PYTHAINLP_MAX_TEXT_LENGTH", "500000"))  # characters
+MAX_BATCH_SIZE = int(os.environ.get("PYTHAINLP_MAX_BATCH_SIZE", "1000"))  # texts
+MAX_REQUEST_BYTES = int(os.environ.get("PYTHAINLP_MAX_REQUEST_BYTES", str(32 << 20)))
+
+# Reproducible neural engines: with PYTHAINLP_SEED, the random generators are
+# seeded on the worker thread right before each call in_worker makes, so that
+# a request's output does not depend on the others, and torch is restricted to
+# deterministic algorithms. DETERMINISTIC is the effective state, which
+# metadata_middleware reports in every response: false if torch refused, or
+# with several workers since concurrent requests share the generators.
+SEED = int(os.environ["PYTHAINLP_SEED"]) if os.environ.get("PYTHAINLP_SEED") else None
+DETERMINISTIC = False
+
+
+def seed_engines() -> None:
+    """Reset the random generators the engines may draw from"""
+    import random
+    random.seed(SEED)
+    try:
+        import numpy
+        numpy.random.seed(SEED % 2**32)
+    except ImportError:
+        pass
+    try:
+        import torch
+        torch.manual_seed(SEED)
+    except ImportError:
+        pass
+
+
+def setup_determinism() -> bool:
+    """Seed the generators and make torch deterministic, returning whether
+    outputs are reproducible"""
+    # cuBLAS needs this before CUDA starts to use deterministic algorithms
+    os.environ.setdefault("CUBLAS_WORKSPACE_CONFIG", ":4096:8")
+    try:
+        import torch
+        torch.use_deterministic_algorithms(True)
+        torch.backends.cudnn.deterministic = True
+        torch.backends.cudnn.benchmark = False
+    except ImportError:
+        pass
+    except Exception as e:
+        print(f"Deterministic torch unavailable: {e}", file=sys.stderr)
+        return False
+    seed_engines()
+    return SERVICE_WORKERS == 1
+
+
+if SEED is not None:
+    DETERMINISTIC = setup_determinism()
+
+# In offline mode corpus downloads are refused instead of hanging on the network
+OFFLINE_MODE = os.environ.get("PYTHAINLP_OFFLINE") == "1"
+
+
+class OfflineModelMissing(Exception):
+    """Raised when a model or corpus must be downloaded while offline"""
+
+
+if OFFLINE_MODE:
+    import pythainlp.corpus.core as corpus_core
+
+    def _offline_download(name, *args, **kwargs):
+        raise OfflineModelMissing(name)
+
+    corpus_core.download = _offline_download
+    print("Offline mode enabled - corpus downloads disabled", file=sys.stderr)
+
+
+# How many traceback frames an INTERNAL_ERROR carries, keeping the innermost:
+# 0 sends all of them and a negative number none
+TRACEBACK_LIMIT = int(os.environ.get("PYTHAINLP_TRACEBACK_LIMIT", "0"))
+
+
+def exception_name(e: BaseException) -> str:
+    """The class of e, qualified with its module unless it is a builtin"""
+    cls = type(e)
+    if cls.__module__ == "builtins":
+        return cls.__qualname__
+    return f"{cls.__module__}.{cls.__qualname__}"
+
+
+def internal_error_response(e: Exception) -> web.Response:
+    """Build the error response for an unexpected exception, with its class
+    and traceback"""
+    details = {"exception": exception_name(e)}
+    if TRACEBACK_LIMIT >= 0:
+        details["traceback"] = "".join(traceback.format_exception(
+            type(e), e, e.__traceback__, limit=-TRACEBACK_LIMIT if TRACEBACK_LIMIT else None))
+    return web.json_response({
+        "data": None,
+        "metadata": {},
+        "error": {
+            "code": "INTERNAL_ERROR",
+            "message": str(e),
+            "details": details
+        }
+    }, status=500)
+
+
+def offline_error_response(e: OfflineModelMissing) -> web.Response:
+    """Build the error response for a model missing in offline mode"""
+    return web.json_response({
+        "data": None,
+        "metadata": {},
+        "error": {
+            "code": "MODEL_MISSING",
+            "message": f"Model '{e}' is not installed and offline mode forbids downloading it; "
+                       f"copy it into the PyThaiNLP data directory from a connected host",
+            "details": {"model": str(e)}
+        }
+    }, status=503)
+
+
+@web.middleware
+async def auth_middleware(request: web.Request, handler):
+    """Reject requests without the bearer token when one is configured"""
+    if SERVICE_TOKEN:
+        given = request.headers.get("Authorization", "").encode()
+        expected = f"Bearer {SERVICE_TOKEN}".encode()
+        if not hmac.compare_digest(given, expected):
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "UNAUTHORIZED",
+                    "message": "Missing or invalid bearer token"
+                }
+            }, status=401)
+    return await handler(request)
+
+
+@web.middleware
+async def protocol_middleware(request: web.Request, handler):
+    """Reject requests from a Go library speaking another protocol version.
+    /health stays open so the client can read the service protocol."""
+    client_protocol = request.headers.get("X-PyThaiNLP-Protocol")
+    if (request.path != "/health" and client_protocol is not None
+            and client_protocol != str(PROTOCOL_VERSION)):
+        return web.json_response({
+            "data": None,
+            "metadata": {},
+            "error": {
+                "code": "PROTOCOL_MISMATCH",
+                "message": f"Client speaks protocol {client_protocol}, service speaks {PROTOCOL_VERSION}",
+                "details": {"client": client_protocol, "service": PROTOCOL_VERSION}
+            }
+        }, status=426)
+    return await handler(request)
+
+
+@web.middleware
+async def stats_middleware(request: web.Request, handler):
+    """Count requests, errors, requests in progress and latency per endpoint"""
+    if request.path in ("/health", "/stats"):
+        return await handler(request)
+    with STATS_LOCK:
+        STATS["requests"] += 1
+        STATS["in_flight"] += 1
+    # Unknown paths are not tracked per endpoint, so they cannot grow the table
+    endpoint = None
+    if request.match_info.route.resource is not None:
+        endpoint = ENDPOINT_STATS.setdefault(request.path, {"requests": 0, "errors": 0, "total_ms": 0.0})
+        endpoint["requests"] += 1
+    started = time.perf_counter()
+    failed = True
+    try:
+        response = await handler(request)
+        failed = response.status >= 400
+        return response
+    finally:
+        with STATS_LOCK:
+            STATS["in_flight"] -= 1
+            if failed:
+                STATS["errors"] += 1
+        if endpoint is not None:
+            endpoint["total_ms"] += (time.perf_counter() - started) * 1000
+            if failed:
+                endpoint["errors"] += 1
+
+
+def limit_error(code: str, limit: str, size: int, maximum: int) -> web.Response:
+    """The 413 response for a request over one of the limits"""
+    return web.json_response({
+        "data": None,
+        "metadata": {},
+        "error": {
+            "code": code,
+            "message": f"{limit} {size} exceeds the maximum of {maximum}",
+            "details": {"limit": limit, "size": size, "max": maximum}
+        }
+    }, status=413)
+
+
+@web.middleware
+async def limits_middleware(request: web.Request, handler):
+    """Reject texts, batches and bodies over the limits before any engine runs"""
+    if request.method != "POST":
+        return await handler(request)
+    try:
+        data = await request.json()
+    except web.HTTPRequestEntityTooLarge:
+        return limit_error("REQUEST_TOO_LARGE", "request_bytes", request.content_length or 0, MAX_REQUEST_BYTES)
+    except Exception:
+        # Invalid JSON is reported by the handler
+        return await handler(request)
+    if isinstance(data, dict):
+        text = data.get("text")
+        if isinstance(text, str) and len(text) > MAX_TEXT_LENGTH:
+            return limit_error("TEXT_TOO_LONG", "text_length", len(text), MAX_TEXT_LENGTH)
+        texts = data.get("texts")
+        if isinstance(texts, list):
+            if len(texts) > MAX_BATCH_SIZE:
+                return limit_error("BATCH_TOO_LARGE", "batch_size", len(texts), MAX_BATCH_SIZE)
+            longest = max((len(t) for t in texts if isinstance(t, str)), default=0)
+            if longest > MAX_TEXT_LENGTH:
+                return limit_error("TEXT_TOO_LONG", "text_length", longest, MAX_TEXT_LENGTH)
+    return await handler(request)
+
+
+@web.middleware
+async def metadata_middleware(request: web.Request, handler):
+    """Add the request ID to every response, in the X-Request-ID header and in
+    the metadata, and to the metadata of successful ones whether the engines
+    the request used were already loaded (cache_hit; a model another request
+    loaded meanwhile also counts as a miss) and, with PYTHAINLP_SEED, the
+    determinism state"""
+    if request.method != "POST":
+        return await handler(request)
+    request_id = request.headers.get("X-Request-ID") or uuid.uuid4().hex
+    misses = MODEL_CACHE["misses"]
+    try:
+        response = await handler(request)
+    except web.HTTPException as e:
+        e.headers["X-Request-ID"] = request_id
+        raise
+    if not isinstance(response, web.Response) or response.content_type != "application/json":
+        response.headers["X-Request-ID"] = request_id
+        return response
+    try:
+        body = json.loads(response.body)
+    except Exception:
+        body = None
+    if not isinstance(body, dict):
+        response.headers["X-Request-ID"] = request_id
+        return response
+    metadata = body.get("metadata")
+    if not isinstance(metadata, dict):
+        metadata = body["metadata"] = {}
+    metadata["request_id"] = request_id
+    if response.status < 400:
+        metadata["cache_hit"] = MODEL_CACHE["misses"] == misses
+        if SEED is not None:
+            metadata["deterministic"] = DETERMINISTIC
+            metadata["seed"] = SEED
+    return web.json_response(body, status=response.status, headers={"X-Request-ID": request_id})
+
+
 # Dynamically detect available engines
 def detect_available_engines():
     """Detect which engines are actually available based on installed dependencies"""
@@ -38,6 +422,7 @@ def detect_available_engines():
     romanize_engines = []
     transliterate_engines = []
     syllable_engines = []
+    sentence_engines = []
     
     # Always available tokenizers (dictionary-based)
     tokenize_engines.extend(["newmm", "longest", "nercut", "tltk"])
@@ -113,24 +498,1315 @@ def detect_available_engines():
     except ImportError:
         pass
     
-    return tokenize_engines, romanize_engines, transliterate_engines, syllable_engines
+    # Sentence engines - crfcut uses python-crfsuite, the whitespace ones need nothing
+    sentence_engines.extend(["crfcut", "whitespace+newline", "whitespace"])
+    
+    try:
+        import tltk
+        sentence_engines.append("tltk")
+    except ImportError:
+        pass
+    
+    # wtp needs wtpsplit (full mode), imported on first use since it loads torch
+    if importlib.util.find_spec("wtpsplit"):
+        sentence_engines.append("wtp")
+    
+    return tokenize_engines, romanize_engines, transliterate_engines, syllable_engines, sentence_engines
+
+# Detect available engines at startup
+TOKENIZE_ENGINES, ROMANIZE_ENGINES, TRANSLITERATE_ENGINES, SYLLABLE_ENGINES, SENTENCE_ENGINES = detect_available_engines()
+print(f"Available tokenizers: {TOKENIZE_ENGINES}", file=sys.stderr)
+print(f"Available romanizers: {ROMANIZE_ENGINES}", file=sys.stderr)
+print(f"Available transliterators: {TRANSLITERATE_ENGINES}", file=sys.stderr)
+print(f"Available syllable engines: {SYLLABLE_ENGINES}", file=sys.stderr)
+print(f"Available sentence engines: {SENTENCE_ENGINES}", file=sys.stderr)
+
+# Reverse transliteration (romanized text to Thai script) needs wunsen, a full mode dependency
+try:
+    from pythainlp.transliterate.wunsen import WunsenTransliterate
+    REVERSE_ENGINES = ["wunsen"]
+except ImportError:
+    REVERSE_ENGINES = []
+print(f"Available reverse transliterators: {REVERSE_ENGINES}", file=sys.stderr)
+
+# Sentence similarity needs sentence-transformers (full mode), imported on
+# first use since it loads torch
+SIMILARITY_ENGINES = ["sentence_transformers"] if importlib.util.find_spec("sentence_transformers") else []
+print(f"Available similarity engines: {SIMILARITY_ENGINES}", file=sys.stderr)
+
+# Lemmas come from dependency parsers (full mode), imported on first use;
+# tokens they do not cover fall back to their normalized form
+LEMMA_ENGINES = [name for name in ("esupar", "spacy_thai") if importlib.util.find_spec(name)]
+print(f"Available lemma engines: {LEMMA_ENGINES}", file=sys.stderr)
+
+
+PRELOAD_FUNCTIONS = {
+    "tokenize": (word_tokenize, TOKENIZE_ENGINES),
+    "romanize": (romanize, ROMANIZE_ENGINES),
+    "transliterate": (transliterate, TRANSLITERATE_ENGINES),
+    "syllable": (syllable_tokenize, SYLLABLE_ENGINES),
+    "sentence": (sent_tokenize, SENTENCE_ENGINES),
+    # The word ranks of the Thai National Corpus, sorted on first use by the
+    # frequency feature and /word_difficulty
+    "frequency": (lambda _text, engine: word_ranks(), ["tnc"]),
+}
+
+
+def preload_engines(spec: str) -> None:
+    """Load the comma-separated "operation/engine" list before serving, so the
+    first request does not pay for importing and loading models"""
+    items = list(filter(None, spec.split(",")))
+    for i, item in enumerate(items):
+        startup_status("model_load", f"Preloading {item}", i, len(items))
+        operation, _, engine = item.partition("/")
+        fn, available = PRELOAD_FUNCTIONS.get(operation, (None, []))
+        if engine not in available:
+            print(f"Cannot preload {item}: engine not available", file=sys.stderr)
+            continue
+        start = time.time()
+        try:
+            run_engine(operation, engine, fn, "ทดสอบภาษาไทย", engine=engine)
+        except Exception as e:
+            print(f"Failed to preload {item}: {e}", file=sys.stderr)
+            continue
+        print(f"Preloaded {item} in {time.time() - start:.1f}s", file=sys.stderr)
+
+
+# Cold engines load on their own thread when a request sets a load budget, so
+# that the fallback engine answering meanwhile is not queued behind them
+LOADER_POOL = ThreadPoolExecutor(max_workers=1, thread_name_prefix="loader")
+LOADING = {}
+
+
+async def engine_within_budget(operation: str, engine: str, data: Dict[str, Any], available: List[str]) -> str:
+    """Return engine if it is loaded or loads within the request's
+    load_budget_ms, otherwise its fallback_engine, leaving engine loading in
+    the background for the next requests"""
+    budget = data.get("load_budget_ms")
+    fallback = data.get("fallback_engine")
+    key = f"{operation}/{engine}"
+    if not budget or not fallback or fallback == engine or fallback not in available or key in LOADED_MODELS:
+        return engine
+    if key not in LOADING:
+        fn, _ = PRELOAD_FUNCTIONS[operation]
+        loop = asyncio.get_running_loop()
+        LOADING[key] = loop.run_in_executor(LOADER_POOL, functools.partial(run_engine, operation, engine, fn, "ทดสอบ", engine=engine))
+        LOADING[key].add_done_callback(lambda _: LOADING.pop(key, None))
+    try:
+        await asyncio.wait_for(asyncio.shield(LOADING[key]), budget / 1000)
+        return engine
+    except asyncio.TimeoutError:
+        print(f"{key} not loaded within {budget} ms, using {fallback}", file=sys.stderr)
+        return fallback
+
+
+EMOJI = "[\U0001F000-\U0001FAFF\u2600-\u27BF]\uFE0F?"
+PROTECT_PATTERNS = {
+    "url": r"(?:https?://|www\.)[^\s<>\"]+",
+    "email": r"[\w.+-]+@[\w-]+(?:\.[\w-]+)+",
+    "hashtag": r"#[^\s#@]+",
+    "mention": r"@[^\s#@]+",
+    # Keycaps, flags and ZWJ sequences such as family emoji, with skin tones
+    "emoji": rf"[0-9#*]\uFE0F?\u20E3|[\U0001F1E6-\U0001F1FF]{{2}}|{EMOJI}(?:\u200D{EMOJI})*",
+}
+
+
+def compile_protect(patterns: List[str]):
+    """Combine named (PROTECT_PATTERNS) and custom regex patterns; raises
+    re.error for invalid ones"""
+    return re.compile("|".join(f"(?:{PROTECT_PATTERNS.get(p, p)})" for p in patterns))
+
+
+def protect_text(text: str, regex) -> tuple:
+    """Replace each match of regex with a placeholder made of Latin letters,
+    which tokenizers keep whole, and return the text and the originals by
+    placeholder"""
+    originals = {}
+
+    def placeholder(m):
+        # Digits written with the letters A-J, so that no key is a prefix of another
+        key = "XPROTECTED" + "".join(chr(ord("A") + int(d)) for d in str(len(originals))) + "X"
+        originals[key] = m.group(0)
+        return key
+
+    return regex.sub(placeholder, text), originals
+
+
+def restore_tokens(tokens: List[str], originals: Dict[str, str]) -> List[str]:
+    """Put the protected text back in place of the placeholders, including
+    those a tokenizer glued to neighbouring characters"""
+    if not originals:
+        return tokens
+    keys = re.compile("|".join(map(re.escape, originals)))
+    return [keys.sub(lambda m: originals[m.group(0)], token) for token in tokens]
+
+
+ZERO_WIDTH = re.compile("[\u200b\u200c\u2060\ufeff]|\u200d(?![\U0001F000-\U0001FAFF\u2600-\u27BF])")
+EMOJI_SEQUENCE = re.compile(PROTECT_PATTERNS["emoji"])
+
+
+def expand_maiyamok(text: str) -> str:
+    """Replace each ๆ with the word before it, e.g. เด็กๆ -> เด็กเด็ก"""
+    if "ๆ" not in text:
+        return text
+    out = []
+    for token in word_tokenize(text, engine="newmm", keep_whitespace=True):
+        if token.strip() == "ๆ":
+            previous = next((t for t in reversed(out) if t.strip() and t != "ๆ"), "")
+            out.append(previous)
+        else:
+            out.append(token)
+    return "".join(out)
+
+
+def preprocess_text(text: str, options: Optional[Dict[str, Any]]) -> str:
+    """Clean up informal text before analysis. Steps run in a fixed order,
+    each enabled by its key in options."""
+    if not options:
+        return text
+    if options.get("strip_zero_width"):
+        text = ZERO_WIDTH.sub("", text)
+    if options.get("normalize"):
+        text = normalize(text)
+    repeats = int(options.get("collapse_repeats") or 0)
+    if repeats > 0:
+        text = re.sub(rf"(.)\1{{{repeats},}}", lambda m: m.group(1) * repeats, text)
+    if options.get("expand_maiyamok"):
+        text = expand_maiyamok(text)
+    if options.get("separate_emoji"):
+        text = EMOJI_SEQUENCE.sub(space_emoji, text)
+    return text
+
+
+def space_emoji(m) -> str:
+    """Surround an emoji sequence with single spaces, one between neighbours"""
+    s = m.string
+    before = m.start() > 0 and not s[m.start() - 1].isspace()
+    after = m.end() < len(s) and not s[m.end()].isspace() and not EMOJI_SEQUENCE.match(s, m.end())
+    return (" " if before else "") + m.group(0) + (" " if after else "")
+
+
+AUTO_LONG_TEXT = 10_000  # characters
+AUTO_OOV_THRESHOLD = 0.15
+SOCIAL_MARKERS = re.compile(rf"https?://|[#@][^\s#@]|(.)\1{{3,}}|{EMOJI}")
+THAI_CHAR = re.compile("[\u0e00-\u0e7f]")
+
+
+def choose_tokenizer(text: str) -> tuple:
+    """Pick a tokenizer for the "auto" engine. Long text goes to the fastest
+    engine. Otherwise newmm is kept unless the text looks like social media
+    or too much of it, weighted by characters, is outside the dictionary, in
+    which case a neural engine copes better. Returns the engine, the newmm
+    tokens if they are the answer, and the reason for the choice."""
+    if len(text) > AUTO_LONG_TEXT:
+        return ("nlpo3" if "nlpo3" in TOKENIZE_ENGINES else "newmm"), None, "long text"
+    tokens = run_engine("tokenize", "newmm", word_tokenize, text, engine="newmm")
+    neural = next((e for e in ("attacut", "deepcut") if e in TOKENIZE_ENGINES), None)
+    if neural is None:
+        return "newmm", tokens, "no neural engine available"
+    if SOCIAL_MARKERS.search(text):
+        return neural, None, "social media markers"
+    from pythainlp.corpus import thai_words
+    words = thai_words()
+    thai = [t for t in tokens if THAI_CHAR.search(t)]
+    total = sum(len(t) for t in thai)
+    oov = sum(len(t) for t in thai if t not in words)
+    if total and oov / total > AUTO_OOV_THRESHOLD:
+        return neural, None, f"out-of-vocabulary rate {oov / total:.2f}"
+    return "newmm", tokens, "in-vocabulary text"
+
+
+# word_tokenize flags a tokenize request can set, with their defaults
+WORD_TOKENIZE_DEFAULTS = {"keep_whitespace": True, "join_broken_num": True}
+
+
+def word_tokenize_options(data: Dict[str, Any]) -> Dict[str, Any]:
+    """Keyword arguments of word_tokenize for a tokenize request: the engine
+    options, and the word_tokenize flags the request sets"""
+    options = dict(data.get("options") or {})
+    for key in WORD_TOKENIZE_DEFAULTS:
+        if key in data:
+            options[key] = bool(data[key])
+    return options
+
+
+def tokenize_auto(text: str, **options) -> tuple:
+    """Tokenize with the engine choose_tokenizer picks; returns the tokens,
+    the engine and the reason"""
+    engine, tokens, reason = choose_tokenizer(text)
+    if tokens is None or any(WORD_TOKENIZE_DEFAULTS.get(k) != v for k, v in options.items()):
+        tokens = run_engine("tokenize", engine, word_tokenize, text, engine=engine, **options)
+    return tokens, engine, reason
+
+
+# Segmentation granularities: the engine's own, compounds merged into the
+# longest dictionary words, or split into as many as cover them
+GRANULARITIES = ["", "coarse", "fine"]
+
+# Longest dictionary word tried when splitting a token
+MAX_WORD_LENGTH = 20
+
+
+@functools.lru_cache(maxsize=1)
+def dictionary_words() -> frozenset:
+    """PyThaiNLP's dictionary, for compound merging and splitting"""
+    from pythainlp.corpus import thai_words
+    return frozenset(thai_words())
+
+
+def merge_compounds(tokens: List[str], words: frozenset, max_parts: int = 4) -> List[str]:
+    """Join runs of up to max_parts adjacent tokens that together form a
+    dictionary word, the longest run first"""
+    result, i = [], 0
+    while i < len(tokens):
+        j = i + 1
+        for end in range(min(len(tokens), i + max_parts), i + 1, -1):
+            run = tokens[i:end]
+            if all(t and t.strip() == t for t in run) and "".join(run) in words:
+                j = end
+                break
+        result.append("".join(tokens[i:j]))
+        i = j
+    return result
+
+
+def split_compound(token: str, words: frozenset) -> List[str]:
+    """Split a Thai token into the most dictionary words of two characters or
+    more that cover it exactly, or keep it whole"""
+    if len(token) < 4 or not any("\u0e00" <= c <= "\u0e7f" for c in token):
+        return [token]
+    best = [None] * (len(token) + 1)
+    best[0] = []
+    for end in range(2, len(token) + 1):
+        for start in range(max(0, end - MAX_WORD_LENGTH), end - 1):
+            if best[start] is None or token[start:end] not in words:
+                continue
+            if best[end] is None or len(best[start]) + 1 > len(best[end]):
+                best[end] = best[start] + [token[start:end]]
+    parts = best[-1]
+    return parts if parts and len(parts) > 1 else [token]
+
+
+def apply_granularity(tokens: List[str], granularity: str) -> List[str]:
+    """Merge or split the compounds among tokens as granularity asks"""
+    if granularity == "coarse":
+        return merge_compounds(tokens, dictionary_words())
+    if granularity == "fine":
+        words = dictionary_words()
+        return [part for token in tokens for part in split_compound(token, words)]
+    return tokens
+
+
+def invalid_granularity_response(granularity: str) -> web.Response:
+    return web.json_response({
+        "data": None,
+        "metadata": {},
+        "error": {
+            "code": "INVALID_GRANULARITY",
+            "message": f"Granularity '{granularity}' not supported",
+            "details": {"supported_granularities": GRANULARITIES}
+        }
+    }, status=400)
+
+
+async def handle_tokenize(request: web.Request) -> web.Response:
+    """Handle tokenization requests"""
+    try:
+        data = await request.json()
+        text = data.get("text", "")
+        engine = data.get("engine", "newmm")
+        options = word_tokenize_options(data)
+        granularity = data.get("granularity", "")
+        
+        if not text:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "EMPTY_TEXT",
+                    "message": "Text parameter is required"
+                }
+            }, status=400)
+        if granularity not in GRANULARITIES:
+            return invalid_granularity_response(granularity)
+        result = {}
+        if data.get("preprocess"):
+            text = result["text"] = await in_worker(preprocess_text, text, data["preprocess"])
+        
+        if engine != "auto" and engine not in TOKENIZE_ENGINES:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "INVALID_ENGINE",
+                    "message": f"Engine '{engine}' not supported",
+                    "details": {"supported_engines": TOKENIZE_ENGINES}
+                }
+            }, status=400)
+        
+        if engine != "auto":
+            engine = await engine_within_budget("tokenize", engine, data, TOKENIZE_ENGINES)
+        
+        originals = {}
+        if data.get("protect"):
+            try:
+                regex = compile_protect(data["protect"])
+            except re.error as e:
+                return web.json_response({
+                    "data": None,
+                    "metadata": {},
+                    "error": {
+                        "code": "INVALID_PATTERN",
+                        "message": f"Invalid protected pattern: {e}",
+                        "details": {"named_patterns": list(PROTECT_PATTERNS)}
+                    }
+                }, status=400)
+            text, originals = protect_text(text, regex)
+
+        start = time.time()
+        metadata = {}
+        if engine == "auto":
+            tokens, engine, metadata["auto_reason"] = await in_worker(tokenize_auto, text, **options)
+        else:
+            tokens = await in_worker(run_engine, "tokenize", engine, word_tokenize, text, engine=engine, **options)
+        if granularity:
+            tokens = await in_worker(apply_granularity, tokens, granularity)
+            metadata["granularity"] = granularity
+        result["tokens"] = restore_tokens(tokens, originals)
+        processing_time = (time.time() - start) * 1000
+        
+        return web.json_response({
+            "data": result,
+            "metadata": {
+                **metadata,
+                "engine": engine,
+                "version": pythainlp_version,
+                "processing_time_ms": round(processing_time, 2)
+            },
+            "error": None
+        })
+        
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
+    except Exception as e:
+        return internal_error_response(e)
+
+
+async def handle_tokenize_batch(request: web.Request) -> web.Response:
+    """Handle tokenization of many texts in one request"""
+    try:
+        data = await request.json()
+        texts = data.get("texts", [])
+        engine = data.get("engine", "newmm")
+        options = word_tokenize_options(data)
+        granularity = data.get("granularity", "")
+        
+        if granularity not in GRANULARITIES:
+            return invalid_granularity_response(granularity)
+        if engine not in TOKENIZE_ENGINES:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "INVALID_ENGINE",
+                    "message": f"Engine '{engine}' not supported",
+                    "details": {"supported_engines": TOKENIZE_ENGINES}
+                }
+            }, status=400)
+        
+        start = time.time()
+        tokens = await in_worker(lambda: [apply_granularity(run_engine("tokenize", engine, word_tokenize, text, engine=engine, **options), granularity) if text else [] for text in texts])
+        processing_time = (time.time() - start) * 1000
+        
+        return web.json_response({
+            "data": {
+                "tokens": tokens
+            },
+            "metadata": {
+                **({"granularity": granularity} if granularity else {}),
+                "engine": engine,
+                "version": pythainlp_version,
+                "processing_time_ms": round(processing_time, 2)
+            },
+            "error": None
+        })
+        
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
+    except Exception as e:
+        return internal_error_response(e)
+
+
+@functools.lru_cache(maxsize=8)
+def words_trie(words: frozenset):
+    """PyThaiNLP's dictionary with extra words, cached for repeated override sets"""
+    from pythainlp.corpus import thai_words
+    from pythainlp.util import dict_trie
+    return dict_trie(set(thai_words()) | words)
+
+
+def tokenize_with_words(text: str, words: frozenset) -> List[str]:
+    """Tokenize with newmm, keeping each of words a single token"""
+    return run_engine("tokenize", "newmm", word_tokenize, text, custom_dict=words_trie(words), engine="newmm")
+
+
+# Named entity classes romanized as proper nouns
+PROPER_NOUN_TAGS = {"PERSON", "LOCATION"}
+
+
+@functools.lru_cache(maxsize=1)
+def ner_tagger():
+    """The thainer named entity tagger, loaded on first use"""
+    from pythainlp.tag import NER
+    return NER(engine="thainer")
+
+
+def ner_tags(text: str) -> List[tuple]:
+    """The words of text with their thainer BIO tags"""
+    return run_engine("ner", "thainer", ner_tagger().tag, text, pos=False)
+
+
+def romanize_proper_nouns(text: str, engine: str, options: Dict[str, Any], overrides: Dict[str, str]):
+    """Romanize text word by word, romanizing the person and place names
+    thainer finds as names: each one a single token, its words looked up in
+    the name table when the lookup engine is available, run together and
+    capitalized. Returns the tokens, their romanizations and the entities."""
+    tagged = ner_tags(text)
+
+    # Merge the B-/I- runs of each entity into one token
+    groups, kinds = [], []
+    for word, tag in tagged:
+        kind = tag[2:] if tag[2:] in PROPER_NOUN_TAGS else None
+        if kind and tag.startswith("I-") and kinds and kinds[-1] == kind:
+            groups[-1].append(word)
+        else:
+            groups.append([word])
+            kinds.append(kind)
+
+    name_engine, name_options = engine, options
+    if engine != "lookup" and "lookup" in ROMANIZE_ENGINES:
+        name_engine, name_options = "lookup", {"engine": "lookup", "fallback_engine": engine}
+
+    def romanize_name(words: List[str]) -> str:
+        parts, current = [], ""
+        for word in words:
+            if word.isspace():
+                if current:
+                    parts.append(current)
+                current = ""
+            elif word in overrides:
+                current += overrides[word]
+            else:
+                current += run_engine("romanize", name_engine, romanize, word, **name_options)
+        if current:
+            parts.append(current)
+        return " ".join(part[:1].upper() + part[1:] for part in parts)
+
+    tokens, romanized_tokens, entities = [], [], []
+    for words, kind in zip(groups, kinds):
+        token = "".join(words)
+        if token in overrides:
+            romanized = overrides[token]
+        elif kind:
+            romanized = romanize_name(words)
+        else:
+            romanized = run_engine("romanize", engine, romanize, token, **options)
+        if kind:
+            entities.append({"text": token, "type": kind, "romanized": romanized, "token": len(tokens)})
+        tokens.append(token)
+        romanized_tokens.append(romanized)
+    return tokens, romanized_tokens, entities
+
+
+# thai2rom mini-batch sizes when the request gives none: a GPU keeps far
+# more words busy at once than CPU threads do
+THAI2ROM_BATCH_SIZE = {"cpu": 32, "cuda": 256}
+THAI2ROM_BATCH_BROKEN = False
+
+
+def thai2rom_batch(words: List[str], batch_size: int) -> List[str]:
+    """Romanize words with thai2rom a mini-batch at a time, greedy decoding
+    the batch like the model does a single word. The model sits on the GPU
+    when torch finds one."""
+    import torch
+    from pythainlp.transliterate import thai2rom as t2r
+    model = t2r._THAI_TO_ROM
+    net = model._network
+    end = net.target_end_token
+
+    results = [""] * len(words)
+    # The encoder packs sequences longest first
+    order = sorted(range(len(words)), key=lambda i: -len(words[i]))
+    with torch.no_grad():
+        for offset in range(0, len(order), batch_size):
+            batch = order[offset:offset + batch_size]
+            sequences = [model._prepare_sequence_in(words[i]) for i in batch]
+            lengths = torch.tensor([len(s) for s in sequences], dtype=torch.int)
+            source = torch.nn.utils.rnn.pad_sequence(
+                sequences, batch_first=True, padding_value=net.pad_idx).to(t2r.device)
+
+            encoder_outputs, encoder_hidden = net.encoder(source, lengths)
+            hidden = torch.cat([encoder_hidden[0][0], encoder_hidden[0][1]], dim=1).unsqueeze(dim=0)
+            mask = net.create_mask(source[:, 0:encoder_outputs.size(1)])
+            decoder_input = torch.full((len(batch), 1), net.target_start_token, dtype=torch.long, device=t2r.device)
+            done = torch.zeros(len(batch), dtype=torch.bool, device=t2r.device)
+            steps = []
+            for _ in range(net.max_length):
+                output, hidden = net.decoder(decoder_input, hidden, encoder_outputs, mask)
+                decoder_input = output.topk(1)[1].detach()
+                steps.append(decoder_input.squeeze(1))
+                done |= steps[-1] == end
+                if done.all():
+                    break
+
+            for i, row in zip(batch, torch.stack(steps, dim=1).cpu().tolist()):
+                chars = []
+                for t in row:
+                    if t == end:
+                        break
+                    chars.append(model._index_to_target_char[t])
+                results[i] = "".join(chars)
+    return results
+
+
+def romanize_tokens(tokens: List[str], engine: str, options: Dict[str, Any], batch_size: int = 0) -> List[str]:
+    """Romanize each token, thai2rom in mini-batches of distinct words"""
+    global THAI2ROM_BATCH_BROKEN
+    if engine == "thai2rom" and not THAI2ROM_BATCH_BROKEN:
+        words = sorted({token for token in tokens if token.strip()})
+        try:
+            # Load the model, and record its memory, like a single call would
+            run_engine("romanize", engine, romanize, "ทดสอบ", **options)
+            from pythainlp.transliterate import thai2rom as t2r
+            if batch_size <= 0:
+                batch_size = THAI2ROM_BATCH_SIZE.get(t2r.device.type, THAI2ROM_BATCH_SIZE["cpu"])
+            romanized = dict(zip(words, thai2rom_batch(words, batch_size)))
+            return [romanized.get(token, token) for token in tokens]
+        except OfflineModelMissing:
+            raise
+        except Exception as e:
+            # The batch decoder relies on thai2rom internals; if this
+            # PyThaiNLP version differs, go word by word from now on
+            THAI2ROM_BATCH_BROKEN = True
+            print(f"thai2rom batching unavailable, romanizing word by word: {e}", file=sys.stderr)
+    return [run_engine("romanize", engine, romanize, token, **options) for token in tokens]
+
+async def handle_romanize(request: web.Request) -> web.Response:
+    """Handle romanization requests"""
+    try:
+        data = await request.json()
+        text = data.get("text", "")
+        engine = data.get("engine", "royin")
+        
+        if not text:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "EMPTY_TEXT",
+                    "message": "Text parameter is required"
+                }
+            }, status=400)
+        if data.get("preprocess"):
+            text = await in_worker(preprocess_text, text, data["preprocess"])
+        
+        if engine not in ROMANIZE_ENGINES:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "INVALID_ENGINE",
+                    "message": f"Engine '{engine}' not supported",
+                    "details": {"supported_engines": ROMANIZE_ENGINES}
+                }
+            }, status=400)
+        
+        engine = await engine_within_budget("romanize", engine, data, ROMANIZE_ENGINES)
+        
+        options = {"engine": engine}
+        lookup_fallback = data.get("lookup_fallback")
+        if engine == "lookup" and lookup_fallback:
+            if lookup_fallback not in ROMANIZE_ENGINES or lookup_fallback == "lookup":
+                return web.json_response({
+                    "data": None,
+                    "metadata": {},
+                    "error": {
+                        "code": "INVALID_ENGINE",
+                        "message": f"Fallback engine '{lookup_fallback}' not supported",
+                        "details": {"supported_engines": ROMANIZE_ENGINES}
+                    }
+                }, status=400)
+            options["fallback_engine"] = lookup_fallback
+        overrides = data.get("overrides") or {}
+        
+        start = time.time()
+        
+        metadata = {"engine": engine}
+        
+        if data.get("proper_nouns"):
+            tokens, romanized_tokens, entities = await in_worker(
+                romanize_proper_nouns, text, engine, options, overrides)
+            result = {
+                "romanized": " ".join(romanized_tokens),
+                "tokens": tokens,
+                "romanized_tokens": romanized_tokens,
+                "entities": entities
+            }
+            metadata["ner_engine"] = "thainer"
+        # Tokenize first if requested, or to apply the overrides word by word
+        elif data.get("tokenize", False) or overrides:
+            if overrides:
+                tokens = await in_worker(tokenize_with_words, text, frozenset(overrides))
+            else:
+                tokens = await in_worker(run_engine, "tokenize", "newmm", word_tokenize, text)
+            romanized = iter(await in_worker(romanize_tokens, [token for token in tokens if token not in overrides],
+                                             engine, options, data.get("batch_size", 0)))
+            romanized_tokens = [overrides[token] if token in overrides else next(romanized) for token in tokens]
+            romanized_text = " ".join(romanized_tokens)
+            result = {
+                "romanized": romanized_text,
+                "tokens": tokens,
+                "romanized_tokens": romanized_tokens
+            }
+        else:
+            romanized_text = await in_worker(run_engine, "romanize", engine, romanize, text, **options)
+            result = {"romanized": romanized_text}
+        
+        if data.get("preprocess"):
+            result["text"] = text
+        
+        processing_time = (time.time() - start) * 1000
+        metadata["version"] = pythainlp_version
+        metadata["processing_time_ms"] = round(processing_time, 2)
+        
+        return web.json_response({
+            "data": result,
+            "metadata": metadata,
+            "error": None
+        })
+        
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
+    except Exception as e:
+        return internal_error_response(e)
+
+
+async def handle_romanize_batch(request: web.Request) -> web.Response:
+    """Handle romanization of many texts in one request"""
+    try:
+        data = await request.json()
+        texts = data.get("texts", [])
+        engine = data.get("engine", "royin")
+        
+        lookup_fallback = data.get("lookup_fallback")
+        for name in (engine, lookup_fallback or engine):
+            if name not in ROMANIZE_ENGINES:
+                return web.json_response({
+                    "data": None,
+                    "metadata": {},
+                    "error": {
+                        "code": "INVALID_ENGINE",
+                        "message": f"Engine '{name}' not supported",
+                        "details": {"supported_engines": ROMANIZE_ENGINES}
+                    }
+                }, status=400)
+        options = {"engine": engine}
+        if engine == "lookup" and lookup_fallback and lookup_fallback != "lookup":
+            options["fallback_engine"] = lookup_fallback
+        
+        start = time.time()
+        romanized = await in_worker(lambda: [run_engine("romanize", engine, romanize, text, **options) if text else "" for text in texts])
+        processing_time = (time.time() - start) * 1000
+        
+        return web.json_response({
+            "data": {
+                "romanized": romanized
+            },
+            "metadata": {
+                "engine": engine,
+                "version": pythainlp_version,
+                "processing_time_ms": round(processing_time, 2)
+            },
+            "error": None
+        })
+        
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
+    except Exception as e:
+        return internal_error_response(e)
+
+
+async def handle_transliterate(request: web.Request) -> web.Response:
+    """Handle transliteration (phonetic) requests"""
+    try:
+        data = await request.json()
+        text = data.get("text", "")
+        engine = data.get("engine", "thaig2p")
+        
+        if not text:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "EMPTY_TEXT",
+                    "message": "Text parameter is required"
+                }
+            }, status=400)
+        if data.get("preprocess"):
+            text = await in_worker(preprocess_text, text, data["preprocess"])
+        
+        if engine not in TRANSLITERATE_ENGINES:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "INVALID_ENGINE",
+                    "message": f"Engine '{engine}' not supported",
+                    "details": {"supported_engines": TRANSLITERATE_ENGINES}
+                }
+            }, status=400)
+        
+        engine = await engine_within_budget("transliterate", engine, data, TRANSLITERATE_ENGINES)
+        
+        start = time.time()
+        phonetic = await in_worker(run_engine, "transliterate", engine, transliterate, text, engine=engine)
+        result = {"phonetic": phonetic}
+        if data.get("preprocess"):
+            result["text"] = text
+        
+        # Also transliterate token by token if requested, for alignment
+        if data.get("tokenize", False):
+            tokens = await in_worker(run_engine, "tokenize", "newmm", word_tokenize, text)
+            result["tokens"] = tokens
+            result["phonetic_tokens"] = await in_worker(lambda: [run_engine("transliterate", engine, transliterate, token, engine=engine) if token.strip() else token for token in tokens])
+        processing_time = (time.time() - start) * 1000
+        
+        return web.json_response({
+            "data": result,
+            "metadata": {
+                "engine": engine,
+                "version": pythainlp_version,
+                "processing_time_ms": round(processing_time, 2)
+            },
+            "error": None
+        })
+        
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
+    except Exception as e:
+        return internal_error_response(e)
+
+
+async def handle_syllable_tokenize(request: web.Request) -> web.Response:
+    """Handle syllable tokenization requests"""
+    try:
+        data = await request.json()
+        text = data.get("text", "")
+        engine = data.get("engine", "han_solo")  # Default engine
+        keep_whitespace = data.get("keep_whitespace", True)
+        
+        if not text:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "EMPTY_TEXT",
+                    "message": "Text parameter is required"
+                }
+            }, status=400)
+        if data.get("preprocess"):
+            text = await in_worker(preprocess_text, text, data["preprocess"])
+        
+        if engine not in SYLLABLE_ENGINES:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "INVALID_ENGINE",
+                    "message": f"Engine '{engine}' not supported",
+                    "details": {"supported_engines": SYLLABLE_ENGINES}
+                }
+            }, status=400)
+        
+        start = time.time()
+        syllables = await in_worker(run_engine, "syllable", engine, syllable_tokenize, text, engine=engine, keep_whitespace=keep_whitespace)
+        processing_time = (time.time() - start) * 1000
+        result = {"syllables": syllables}
+        if data.get("preprocess"):
+            result["text"] = text
+        
+        return web.json_response({
+            "data": result,
+            "metadata": {
+                "engine": engine,
+                "version": pythainlp_version,
+                "processing_time_ms": round(processing_time, 2)
+            },
+            "error": None
+        })
+        
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
+    except Exception as e:
+        return internal_error_response(e)
+
+
+# tone_detector codes, spelled out for the Go side
+TONE_NAMES = {"l": "low", "m": "mid", "h": "high", "r": "rising", "f": "falling"}
+
+
+def syllable_tone(syllable: str) -> str:
+    """The tone of a syllable, or "" when PyThaiNLP cannot tell"""
+    try:
+        from pythainlp.util import tone_detector
+        return TONE_NAMES.get(tone_detector(syllable), "")
+    except Exception:
+        return ""
+
+
+def syllable_phonetics(text: str, syllable_engine: str, ipa_engine: str, romanize_engine: str) -> List[Dict[str, str]]:
+    """IPA, tone and romanization of each syllable of text, skipping whitespace"""
+    syllables = run_engine("syllable", syllable_engine, syllable_tokenize, text, engine=syllable_engine, keep_whitespace=False)
+    result = []
+    for syllable in syllables:
+        if not syllable.strip():
+            continue
+        result.append({
+            "syllable": syllable,
+            "ipa": run_engine("transliterate", ipa_engine, transliterate, syllable, engine=ipa_engine),
+            "tone": syllable_tone(syllable),
+            "romanized": run_engine("romanize", romanize_engine, romanize, syllable, engine=romanize_engine),
+        })
+    return result
+
+
+async def handle_syllable_phonetics(request: web.Request) -> web.Response:
+    """Handle per-syllable IPA, tone and romanization requests"""
+    try:
+        data = await request.json()
+        text = data.get("text", "")
+        syllable_engine = data.get("syllable_engine") or "han_solo"
+        ipa_engine = data.get("transliterate_engine") or "tltk_ipa"
+        romanize_engine = data.get("romanize_engine") or "royin"
+
+        if not text:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "EMPTY_TEXT",
+                    "message": "Text parameter is required"
+                }
+            }, status=400)
+
+        for engine, supported in ((syllable_engine, SYLLABLE_ENGINES),
+                                  (ipa_engine, TRANSLITERATE_ENGINES),
+                                  (romanize_engine, ROMANIZE_ENGINES)):
+            if engine not in supported:
+                return web.json_response({
+                    "data": None,
+                    "metadata": {},
+                    "error": {
+                        "code": "INVALID_ENGINE",
+                        "message": f"Engine '{engine}' not supported",
+                        "details": {"supported_engines": supported}
+                    }
+                }, status=400)
+
+        start = time.time()
+        syllables = await in_worker(syllable_phonetics, text, syllable_engine, ipa_engine, romanize_engine)
+        processing_time = (time.time() - start) * 1000
+
+        return web.json_response({
+            "data": {
+                "syllables": syllables
+            },
+            "metadata": {
+                "engine": syllable_engine,
+                "transliterate_engine": ipa_engine,
+                "romanize_engine": romanize_engine,
+                "version": pythainlp_version,
+                "processing_time_ms": round(processing_time, 2)
+            },
+            "error": None
+        })
+
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
+    except Exception as e:
+        return internal_error_response(e)
+
+
+def analyze_document(text: str, sentence_engine: str, word_engine: str, syllable_engine: str) -> List[Dict[str, Any]]:
+    """Paragraphs of text, split at line breaks, then sentences, words and
+    syllables. Whitespace-only pieces are dropped at every level."""
+    paragraphs = []
+    for paragraph in text.splitlines():
+        if not paragraph.strip():
+            continue
+        sentences = []
+        for sentence in run_engine("sentence", sentence_engine, sent_tokenize, paragraph, engine=sentence_engine):
+            if not sentence.strip():
+                continue
+            words = []
+            for word in run_engine("tokenize", word_engine, word_tokenize, sentence, engine=word_engine, keep_whitespace=False):
+                if not word.strip():
+                    continue
+                syllables = [word]
+                if THAI_CHAR.search(word):
+                    syllables = [s for s in run_engine("syllable", syllable_engine, syllable_tokenize, word,
+                                                       engine=syllable_engine, keep_whitespace=False) if s.strip()]
+                words.append({"text": word, "syllables": syllables})
+            sentences.append({"text": sentence, "words": words})
+        paragraphs.append({"text": paragraph, "sentences": sentences})
+    return paragraphs
+
+
+async def handle_analyze_document(request: web.Request) -> web.Response:
+    """Handle paragraph, sentence, word and syllable segmentation in one request"""
+    try:
+        data = await request.json()
+        text = data.get("text", "")
+        sentence_engine = data.get("sentence_engine") or "crfcut"
+        word_engine = data.get("tokenize_engine") or "newmm"
+        syllable_engine = data.get("syllable_engine") or "han_solo"
+
+        if not text:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "EMPTY_TEXT",
+                    "message": "Text parameter is required"
+                }
+            }, status=400)
+
+        for engine, supported in ((sentence_engine, SENTENCE_ENGINES),
+                                  (word_engine, TOKENIZE_ENGINES),
+                                  (syllable_engine, SYLLABLE_ENGINES)):
+            if engine not in supported:
+                return web.json_response({
+                    "data": None,
+                    "metadata": {},
+                    "error": {
+                        "code": "INVALID_ENGINE",
+                        "message": f"Engine '{engine}' not supported",
+                        "details": {"supported_engines": supported}
+                    }
+                }, status=400)
+
+        start = time.time()
+        paragraphs = await in_worker(analyze_document, text, sentence_engine, word_engine, syllable_engine)
+        processing_time = (time.time() - start) * 1000
+
+        return web.json_response({
+            "data": {
+                "paragraphs": paragraphs
+            },
+            "metadata": {
+                "engine": word_engine,
+                "sentence_engine": sentence_engine,
+                "syllable_engine": syllable_engine,
+                "version": pythainlp_version,
+                "processing_time_ms": round(processing_time, 2)
+            },
+            "error": None
+        })
+
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
+    except Exception as e:
+        return internal_error_response(e)
+
+
+# Paragraph splitters: line break heuristics, or wtpsplit (full mode) through
+# pythainlp's paragraph_tokenize within each block between blank lines
+PARAGRAPH_ENGINES = ["newline"] + (["wtp"] if importlib.util.find_spec("wtpsplit") else [])
+
+# Widest line of hard-wrapped text; outside this range every line break ends
+# a paragraph
+MIN_WRAP_WIDTH, MAX_WRAP_WIDTH = 40, 120
+LIST_ITEM = re.compile(r"\s|[-*•]\s|[0-9๐-๙]{1,3}[.)]\s")
+
+
+def text_blocks(text: str) -> List[List[tuple]]:
+    """Character spans of the non-blank lines of text, grouped in blocks
+    separated by blank lines"""
+    blocks, current, last_end = [], [], 0
+    for m in re.finditer(r"[^\r\n]+", text):
+        blank = not m.group().strip() or text.count("\n", last_end, m.start()) > 1
+        if blank and current:
+            blocks.append(current)
+            current = []
+        if m.group().strip():
+            current.append(m.span())
+        last_end = m.end()
+    if current:
+        blocks.append(current)
+    return blocks
+
+
+def newline_paragraphs(text: str, lines: List[tuple]) -> List[tuple]:
+    """Spans of the paragraphs of a block. A line break ends a paragraph
+    unless the block looks hard-wrapped, its widest line 40 to 120
+    characters, the line before the break is about as wide as the widest
+    and the next one is neither indented nor a list item."""
+    widths = [end - start for start, end in lines]
+    wrap = max(widths) if len(lines) > 1 and MIN_WRAP_WIDTH <= max(widths) <= MAX_WRAP_WIDTH else 0
+    groups = [[lines[0]]]
+    for (prev_start, prev_end), (start, end) in zip(lines, lines[1:]):
+        if wrap and prev_end - prev_start >= 0.8 * wrap and not LIST_ITEM.match(text, start):
+            groups[-1].append((start, end))
+        else:
+            groups.append([(start, end)])
+    return [(group[0][0], group[-1][1]) for group in groups]
+
+
+def wtp_paragraphs(text: str, start: int, end: int) -> List[tuple]:
+    """Spans of the paragraphs wtpsplit finds in text[start:end]"""
+    from pythainlp.tokenize import paragraph_tokenize
+    block = text[start:end]
+    spans, pos = [], 0
+    for paragraph in run_engine("paragraph", "wtp", paragraph_tokenize, block, engine="wtp-mini"):
+        first = last = None
+        for sentence in paragraph:
+            sentence = sentence.strip()
+            at = block.find(sentence, pos) if sentence else -1
+            if at < 0:
+                continue
+            first = at if first is None else first
+            last = pos = at + len(sentence)
+        if first is not None:
+            spans.append((start + first, start + last))
+    return spans
+
+
+def paragraph_tokenize_text(text: str, engine: str) -> List[str]:
+    """Paragraphs of text, blank lines always separating them, without
+    surrounding whitespace"""
+    spans = []
+    for lines in text_blocks(text):
+        if engine == "wtp":
+            spans.extend(wtp_paragraphs(text, lines[0][0], lines[-1][1]))
+        else:
+            spans.extend(newline_paragraphs(text, lines))
+    return [text[start:end].strip() for start, end in spans if text[start:end].strip()]
+
+
+async def handle_paragraph_tokenize(request: web.Request) -> web.Response:
+    """Handle paragraph segmentation requests"""
+    try:
+        data = await request.json()
+        text = data.get("text", "")
+        engine = data.get("engine") or "newline"
+
+        if not text:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "EMPTY_TEXT",
+                    "message": "Text parameter is required"
+                }
+            }, status=400)
+        if engine not in PARAGRAPH_ENGINES:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "INVALID_ENGINE",
+                    "message": f"Engine '{engine}' not supported",
+                    "details": {"supported_engines": PARAGRAPH_ENGINES}
+                }
+            }, status=400)
+
+        start = time.time()
+        paragraphs = await in_worker(paragraph_tokenize_text, text, engine)
+        processing_time = (time.time() - start) * 1000
+
+        return web.json_response({
+            "data": {
+                "paragraphs": paragraphs
+            },
+            "metadata": {
+                "engine": engine,
+                "version": pythainlp_version,
+                "processing_time_ms": round(processing_time, 2)
+            },
+            "error": None
+        })
+
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
+    except Exception as e:
+        return internal_error_response(e)
+
+
+async def handle_ner(request: web.Request) -> web.Response:
+    """Handle named entity recognition, returning thainer's BIO-tagged words"""
+    try:
+        data = await request.json()
+        text = data.get("text", "")
+
+        if not text:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "EMPTY_TEXT",
+                    "message": "Text parameter is required"
+                }
+            }, status=400)
+
+        start = time.time()
+        tagged = await in_worker(ner_tags, text)
+        processing_time = (time.time() - start) * 1000
+
+        return web.json_response({
+            "data": {
+                "tokens": [{"text": word, "tag": tag} for word, tag in tagged]
+            },
+            "metadata": {
+                "engine": "thainer",
+                "version": pythainlp_version,
+                "processing_time_ms": round(processing_time, 2)
+            },
+            "error": None
+        })
 
-# Detect available engines at startup
-TOKENIZE_ENGINES, ROMANIZE_ENGINES, TRANSLITERATE_ENGINES, SYLLABLE_ENGINES = detect_available_engines()
-print(f"Available tokenizers: {TOKENIZE_ENGINES}", file=sys.stderr)
-print(f"Available romanizers: {ROMANIZE_ENGINES}", file=sys.stderr)
-print(f"Available transliterators: {TRANSLITERATE_ENGINES}", file=sys.stderr)
-print(f"Available syllable engines: {SYLLABLE_ENGINES}", file=sys.stderr)
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
+    except Exception as e:
+        return internal_error_response(e)
 
 
-async def handle_tokenize(request: web.Request) -> web.Response:
-    """Handle tokenization requests"""
+# Titles written before Thai personal names, longest first so that นางสาว is
+# not read as นาง
+NAME_HONORIFICS = sorted(["นาย", "นาง", "นางสาว", "น.ส.", "เด็กชาย", "เด็กหญิง", "ด.ช.", "ด.ญ.",
+                          "คุณ", "ดร.", "นพ.", "พญ."], key=len, reverse=True)
+HONORIFIC_RE = re.compile("|".join(re.escape(h) for h in NAME_HONORIFICS))
+THAI_WORD_RE = re.compile("[\u0e01-\u0e4e]+")
+
+
+@functools.lru_cache(maxsize=1)
+def person_name_corpora():
+    """Given names and family names of PyThaiNLP's name corpora, bundled with
+    the package"""
+    from pythainlp.corpus import thai_female_names, thai_male_names, thai_family_names
+    return frozenset(thai_female_names()) | frozenset(thai_male_names()), frozenset(thai_family_names())
+
+
+def longest_known_prefix(word: str, known) -> str:
+    """The longest prefix of word in known, or "" if there is none"""
+    for end in range(len(word), 1, -1):
+        if word[:end] in known:
+            return word[:end]
+    return ""
+
+
+def skip_spaces(text: str, i: int) -> int:
+    """The index of the first character of text from i that is not a space"""
+    while i < len(text) and text[i] == " ":
+        i += 1
+    return i
+
+
+def parse_person_name(text: str) -> Dict[str, Any]:
+    """Split a candidate name into honorific, given and family name, noting
+    which names the corpora know"""
+    given_names, family_names = person_name_corpora()
+    text = text.strip()
+    honorific = ""
+    match = HONORIFIC_RE.match(text)
+    if match:
+        honorific = match.group()
+    parts = text[len(honorific):].split()
+    given = parts[0] if parts else ""
+    family = " ".join(parts[1:])
+    return {
+        "text": text,
+        "honorific": honorific,
+        "given_name": given,
+        "family_name": family,
+        "known_given_name": given in given_names,
+        "known_family_name": bool(family) and family in family_names,
+    }
+
+
+def person_name_verdict(token: str) -> Dict[str, Any]:
+    """Whether token is likely a person name: its given or family name is in
+    the corpora, or thainer tags it as a person"""
+    name = parse_person_name(token)
+    name["likely"], name["source"] = False, ""
+    if name["known_given_name"] or name["known_family_name"]:
+        name["likely"], name["source"] = True, "corpus"
+    elif name["given_name"]:
+        if any(tag.endswith("PERSON") for _, tag in ner_tags(name["text"])):
+            name["likely"], name["source"] = True, "ner"
+    return name
+
+
+def extract_person_names(text: str) -> List[Dict[str, Any]]:
+    """Person names of text, in order: thainer PERSON entities, and honorifics
+    followed by a given name the corpora know, with the family name after it
+    when they know that too. Overlapping finds are merged."""
+    given_names, family_names = person_name_corpora()
+    spans = []
+
+    pos, current = 0, None
+    for word, tag in ner_tags(text):
+        start = text.find(word, pos)
+        if start < 0:
+            current = None
+            continue
+        pos = start + len(word)
+        if tag == "B-PERSON" or (tag == "I-PERSON" and current is None):
+            current = [start, pos, "ner"]
+            spans.append(current)
+        elif tag == "I-PERSON":
+            current[1] = pos
+        else:
+            current = None
+
+    for match in HONORIFIC_RE.finditer(text):
+        rest = THAI_WORD_RE.match(text, skip_spaces(text, match.end()))
+        if not rest:
+            continue
+        given = longest_known_prefix(rest.group(), given_names)
+        if not given:
+            continue
+        end = rest.start() + len(given)
+        family = THAI_WORD_RE.match(text, skip_spaces(text, end))
+        if family and family.start() > end:
+            known = longest_known_prefix(family.group(), family_names)
+            if known:
+                end = family.start() + len(known)
+        spans.append([match.start(), end, "corpus"])
+
+    merged = []
+    for start, end, source in sorted(spans):
+        if merged and start < merged[-1][1]:
+            merged[-1][1] = max(merged[-1][1], end)
+            if source not in merged[-1][2]:
+                merged[-1][2] += "," + source
+        else:
+            merged.append([start, end, source])
+
+    names = []
+    for start, end, source in merged:
+        name = parse_person_name(text[start:end])
+        name["source"] = source
+        names.append(name)
+    return names
+
+
+async def handle_person_names(request: web.Request) -> web.Response:
+    """Handle person name checks of a token, or extraction from a text"""
     try:
         data = await request.json()
         text = data.get("text", "")
-        engine = data.get("engine", "newmm")
-        options = data.get("options", {})
-        
+        extract = bool(data.get("extract"))
+
         if not text:
             return web.json_response({
                 "data": None,
@@ -140,28 +1816,102 @@ async def handle_tokenize(request: web.Request) -> web.Response:
                     "message": "Text parameter is required"
                 }
             }, status=400)
-        
-        if engine not in TOKENIZE_ENGINES:
+
+        start = time.time()
+        if extract:
+            result = {"names": await in_worker(extract_person_names, text)}
+        else:
+            result = {"name": await in_worker(person_name_verdict, text)}
+        processing_time = (time.time() - start) * 1000
+
+        return web.json_response({
+            "data": result,
+            "metadata": {
+                "engine": "thainer",
+                "version": pythainlp_version,
+                "processing_time_ms": round(processing_time, 2)
+            },
+            "error": None
+        })
+
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
+    except Exception as e:
+        return internal_error_response(e)
+
+
+@functools.lru_cache(maxsize=1)
+def word_ranks() -> Dict[str, tuple]:
+    """Map each word of the Thai National Corpus to its frequency rank, 1 for
+    the most frequent, and its count"""
+    from pythainlp.corpus import tnc
+    ranked = sorted(tnc.word_freqs(), key=lambda wf: -wf[1])
+    return {word: (rank, freq) for rank, (word, freq) in enumerate(ranked, 1)}
+
+
+def word_difficulty(words: List[str]) -> List[Dict[str, Any]]:
+    """Report the corpus rank and count and the number of syllables of each word"""
+    ranks = word_ranks()
+    engine = "han_solo" if "han_solo" in SYLLABLE_ENGINES else "dict"
+    result = []
+    for word in words:
+        rank, freq = ranks.get(word, (0, 0))
+        syllables = 0
+        if word.strip():
+            syllables = len([s for s in run_engine("syllable", engine, syllable_tokenize, word, engine=engine) if s.strip()])
+        result.append({"word": word, "rank": rank, "frequency": freq, "syllables": syllables})
+    return result
+
+
+async def handle_word_difficulty(request: web.Request) -> web.Response:
+    """Handle word frequency and syllable count requests"""
+    try:
+        data = await request.json()
+        words = data.get("words") or []
+
+        if not words:
             return web.json_response({
                 "data": None,
                 "metadata": {},
                 "error": {
-                    "code": "INVALID_ENGINE",
-                    "message": f"Engine '{engine}' not supported",
-                    "details": {"supported_engines": TOKENIZE_ENGINES}
+                    "code": "EMPTY_TEXT",
+                    "message": "Words parameter is required"
                 }
             }, status=400)
-        
+
+        start = time.time()
+        result = await in_worker(word_difficulty, words)
+        processing_time = (time.time() - start) * 1000
+
+        return web.json_response({
+            "data": {"words": result, "corpus_size": len(word_ranks())},
+            "metadata": {
+                "engine": "tnc",
+                "version": pythainlp_version,
+                "processing_time_ms": round(processing_time, 2)
+            },
+            "error": None
+        })
+
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
+    except Exception as e:
+        return internal_error_response(e)
+
+
+async def handle_preprocess(request: web.Request) -> web.Response:
+    """Handle standalone preprocessing requests"""
+    try:
+        data = await request.json()
         start = time.time()
-        tokens = word_tokenize(text, engine=engine, **options)
+        text = await in_worker(preprocess_text, data.get("text", ""), data.get("preprocess") or {})
         processing_time = (time.time() - start) * 1000
         
         return web.json_response({
             "data": {
-                "tokens": tokens
+                "text": text
             },
             "metadata": {
-                "engine": engine,
                 "version": pythainlp_version,
                 "processing_time_ms": round(processing_time, 2)
             },
@@ -169,23 +1919,28 @@ async def handle_tokenize(request: web.Request) -> web.Response:
         })
         
     except Exception as e:
-        return web.json_response({
-            "data": None,
-            "metadata": {},
-            "error": {
-                "code": "INTERNAL_ERROR",
-                "message": str(e),
-                "details": {"traceback": traceback.format_exc()}
-            }
-        }, status=500)
+        return internal_error_response(e)
 
 
-async def handle_romanize(request: web.Request) -> web.Response:
-    """Handle romanization requests"""
+REVERSE_LANGUAGES = ["jp", "ko", "vi", "zh"]
+_wunsen = None
+
+
+def reverse_transliterate(text: str, lang: str, **options) -> str:
+    """Write romanized Japanese, Korean, Vietnamese or Mandarin in Thai script"""
+    global _wunsen
+    if _wunsen is None:
+        _wunsen = WunsenTransliterate()
+    return _wunsen.transliterate(text, lang=lang, **options)
+
+
+async def handle_reverse_transliterate(request: web.Request) -> web.Response:
+    """Handle reverse transliteration (romanized to Thai script) requests"""
     try:
         data = await request.json()
         text = data.get("text", "")
-        engine = data.get("engine", "royin")
+        lang = data.get("lang", "jp")
+        options = data.get("options", {})
         
         if not text:
             return web.json_response({
@@ -197,39 +1952,39 @@ async def handle_romanize(request: web.Request) -> web.Response:
                 }
             }, status=400)
         
-        if engine not in ROMANIZE_ENGINES:
+        if not REVERSE_ENGINES:
             return web.json_response({
                 "data": None,
                 "metadata": {},
                 "error": {
                     "code": "INVALID_ENGINE",
-                    "message": f"Engine '{engine}' not supported",
-                    "details": {"supported_engines": ROMANIZE_ENGINES}
+                    "message": "Reverse transliteration requires wunsen, installed in full mode",
+                    "details": {"supported_engines": REVERSE_ENGINES}
                 }
             }, status=400)
         
-        start = time.time()
-        
-        # Tokenize first if requested
-        if data.get("tokenize", False):
-            tokens = word_tokenize(text)
-            romanized_tokens = [romanize(token, engine=engine) for token in tokens]
-            romanized_text = " ".join(romanized_tokens)
-            result = {
-                "romanized": romanized_text,
-                "tokens": tokens,
-                "romanized_tokens": romanized_tokens
-            }
-        else:
-            romanized_text = romanize(text, engine=engine)
-            result = {"romanized": romanized_text}
+        if lang not in REVERSE_LANGUAGES:
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "INVALID_LANGUAGE",
+                    "message": f"Language '{lang}' not supported",
+                    "details": {"supported_languages": REVERSE_LANGUAGES}
+                }
+            }, status=400)
         
+        start = time.time()
+        thai = await in_worker(run_engine, "reverse_transliterate", "wunsen", reverse_transliterate, text, lang, **options)
         processing_time = (time.time() - start) * 1000
         
         return web.json_response({
-            "data": result,
+            "data": {
+                "thai": thai
+            },
             "metadata": {
-                "engine": engine,
+                "engine": "wunsen",
+                "lang": lang,
                 "version": pythainlp_version,
                 "processing_time_ms": round(processing_time, 2)
             },
@@ -237,55 +1992,62 @@ async def handle_romanize(request: web.Request) -> web.Response:
         })
         
     except Exception as e:
-        return web.json_response({
-            "data": None,
-            "metadata": {},
-            "error": {
-                "code": "INTERNAL_ERROR",
-                "message": str(e),
-                "details": {"traceback": traceback.format_exc()}
-            }
-        }, status=500)
+        return internal_error_response(e)
 
 
-async def handle_transliterate(request: web.Request) -> web.Response:
-    """Handle transliteration (phonetic) requests"""
+DEFAULT_SIMILARITY_MODEL = "sentence-transformers/paraphrase-multilingual-MiniLM-L12-v2"
+_similarity_models = {}
+
+
+def similarity_scores(text: str, candidates: List[str], model: str) -> List[float]:
+    """Cosine similarity of the sentence embeddings of text and each candidate"""
+    from sentence_transformers import SentenceTransformer, util
+    if model not in _similarity_models:
+        _similarity_models[model] = SentenceTransformer(model)
+    embeddings = _similarity_models[model].encode([text] + candidates, convert_to_tensor=True, normalize_embeddings=True)
+    return util.cos_sim(embeddings[0:1], embeddings[1:])[0].tolist()
+
+
+async def handle_similarity(request: web.Request) -> web.Response:
+    """Handle sentence similarity requests: one text against many candidates"""
     try:
         data = await request.json()
         text = data.get("text", "")
-        engine = data.get("engine", "thaig2p")
+        candidates = data.get("candidates", [])
+        model = data.get("model") or DEFAULT_SIMILARITY_MODEL
         
-        if not text:
+        if not text or not candidates:
             return web.json_response({
                 "data": None,
                 "metadata": {},
                 "error": {
                     "code": "EMPTY_TEXT",
-                    "message": "Text parameter is required"
+                    "message": "Text and candidates parameters are required"
                 }
             }, status=400)
         
-        if engine not in TRANSLITERATE_ENGINES:
+        if not SIMILARITY_ENGINES:
             return web.json_response({
                 "data": None,
                 "metadata": {},
                 "error": {
                     "code": "INVALID_ENGINE",
-                    "message": f"Engine '{engine}' not supported",
-                    "details": {"supported_engines": TRANSLITERATE_ENGINES}
+                    "message": "Sentence similarity requires sentence-transformers, installed in full mode",
+                    "details": {"supported_engines": SIMILARITY_ENGINES}
                 }
             }, status=400)
         
         start = time.time()
-        phonetic = transliterate(text, engine=engine)
+        scores = await in_worker(run_engine, "similarity", "sentence_transformers", similarity_scores, text, candidates, model)
         processing_time = (time.time() - start) * 1000
         
         return web.json_response({
             "data": {
-                "phonetic": phonetic
+                "scores": scores
             },
             "metadata": {
-                "engine": engine,
+                "engine": "sentence_transformers",
+                "model": model,
                 "version": pythainlp_version,
                 "processing_time_ms": round(processing_time, 2)
             },
@@ -293,24 +2055,43 @@ async def handle_transliterate(request: web.Request) -> web.Response:
         })
         
     except Exception as e:
-        return web.json_response({
-            "data": None,
-            "metadata": {},
-            "error": {
-                "code": "INTERNAL_ERROR",
-                "message": str(e),
-                "details": {"traceback": traceback.format_exc()}
-            }
-        }, status=500)
+        return internal_error_response(e)
 
 
-async def handle_syllable_tokenize(request: web.Request) -> web.Response:
-    """Handle syllable tokenization requests"""
+SOUNDEX_ENGINES = ["lk82", "udom83", "metasound"]
+
+
+def search_terms(text: str, engine: str, keep_stopwords: bool, soundex_engine: str, romanize_engine: str) -> List[Dict[str, Any]]:
+    """Normalize and tokenize text into index terms, without punctuation and,
+    unless kept, stopwords. Positions count every non-space token, so that
+    phrase queries still see the gaps left by dropped ones."""
+    from pythainlp.corpus import thai_stopwords
+    from pythainlp.soundex import soundex
+    stopwords = frozenset() if keep_stopwords else thai_stopwords()
+    tokens = run_engine("tokenize", engine, word_tokenize, normalize(text), engine=engine, keep_whitespace=False)
+    terms = []
+    for position, token in enumerate(t for t in tokens if t.strip()):
+        term = token.strip().lower()
+        if not any(c.isalnum() for c in term) or term in stopwords:
+            continue
+        entry = {"term": term, "position": position}
+        if any("\u0e00" <= c <= "\u0e7f" for c in term):
+            if soundex_engine:
+                entry["soundex"] = soundex(term, engine=soundex_engine)
+            if romanize_engine:
+                entry["romanized"] = run_engine("romanize", romanize_engine, romanize, term, engine=romanize_engine)
+        terms.append(entry)
+    return terms
+
+
+async def handle_search_terms(request: web.Request) -> web.Response:
+    """Handle search normalization requests"""
     try:
         data = await request.json()
         text = data.get("text", "")
-        engine = data.get("engine", "han_solo")  # Default engine
-        keep_whitespace = data.get("keep_whitespace", True)
+        engine = data.get("engine", "newmm")
+        soundex_engine = data.get("soundex_engine", "")
+        romanize_engine = data.get("romanize_engine", "")
         
         if not text:
             return web.json_response({
@@ -322,24 +2103,25 @@ async def handle_syllable_tokenize(request: web.Request) -> web.Response:
                 }
             }, status=400)
         
-        if engine not in SYLLABLE_ENGINES:
-            return web.json_response({
-                "data": None,
-                "metadata": {},
-                "error": {
-                    "code": "INVALID_ENGINE",
-                    "message": f"Engine '{engine}' not supported",
-                    "details": {"supported_engines": SYLLABLE_ENGINES}
-                }
-            }, status=400)
+        for name, available in ((engine, TOKENIZE_ENGINES), (soundex_engine, SOUNDEX_ENGINES), (romanize_engine, ROMANIZE_ENGINES)):
+            if name and name not in available:
+                return web.json_response({
+                    "data": None,
+                    "metadata": {},
+                    "error": {
+                        "code": "INVALID_ENGINE",
+                        "message": f"Engine '{name}' not supported",
+                        "details": {"supported_engines": available}
+                    }
+                }, status=400)
         
         start = time.time()
-        syllables = syllable_tokenize(text, engine=engine, keep_whitespace=keep_whitespace)
+        terms = await in_worker(search_terms, text, engine, bool(data.get("keep_stopwords")), soundex_engine, romanize_engine)
         processing_time = (time.time() - start) * 1000
         
         return web.json_response({
             "data": {
-                "syllables": syllables
+                "terms": terms
             },
             "metadata": {
                 "engine": engine,
@@ -349,16 +2131,54 @@ async def handle_syllable_tokenize(request: web.Request) -> web.Response:
             "error": None
         })
         
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
     except Exception as e:
-        return web.json_response({
-            "data": None,
-            "metadata": {},
-            "error": {
-                "code": "INTERNAL_ERROR",
-                "message": str(e),
-                "details": {"traceback": traceback.format_exc()}
-            }
-        }, status=500)
+        return internal_error_response(e)
+
+
+def lemma_fallback(token: str) -> str:
+    """Canonical form of a token no parser gave a lemma for: vowels and tone
+    marks normalized, maiyamok dropped and Latin letters lowercased"""
+    return normalize(token).replace("ๆ", "").strip().lower()
+
+
+def parsed_lemmas(text: str, engine: str) -> Dict[tuple, str]:
+    """Map the character span of each word of a dependency parse of text to
+    its lemma, for the words the parser gave one"""
+    from pythainlp.parse import dependency_parsing
+    rows = run_engine("lemma", engine, dependency_parsing, text, engine=engine, tag="list")
+    lemmas, pos = {}, 0
+    for row in rows:
+        form, lemma = row[1], row[2]
+        start = text.find(form, pos)
+        if not form or start < 0:
+            continue
+        pos = start + len(form)
+        if lemma and lemma != "_":
+            lemmas[(start, pos)] = lemma
+    return lemmas
+
+
+def lemmatize_tokens(tokens: List[str], engine: str) -> List[str]:
+    """Lemma of each token: the parser's where one of its words spans exactly
+    the token, otherwise the normalized token. A lone ๆ repeats the word
+    before it and takes its lemma; whitespace has none."""
+    lemmas = parsed_lemmas("".join(tokens), engine) if engine else {}
+    result, pos, previous = [], 0, ""
+    for token in tokens:
+        span = (pos, pos + len(token))
+        pos = span[1]
+        if not token.strip():
+            result.append("")
+            continue
+        if token.strip() == "ๆ":
+            result.append(previous)
+            continue
+        lemma = lemmas.get(span) or lemma_fallback(token)
+        result.append(lemma)
+        previous = lemma
+    return result
 
 
 async def handle_analyze(request: web.Request) -> web.Response:
@@ -367,6 +2187,7 @@ async def handle_analyze(request: web.Request) -> web.Response:
         data = await request.json()
         text = data.get("text", "")
         features = data.get("features", ["tokenize", "romanize"])
+        granularity = data.get("granularity", "")
         
         if not text:
             return web.json_response({
@@ -377,58 +2198,342 @@ async def handle_analyze(request: web.Request) -> web.Response:
                     "message": "Text parameter is required"
                 }
             }, status=400)
+        if granularity not in GRANULARITIES:
+            return invalid_granularity_response(granularity)
+        if data.get("preprocess"):
+            text = await in_worker(preprocess_text, text, data["preprocess"])
         
         start = time.time()
         result = {}
         
         # Always tokenize first as base
-        tokens = word_tokenize(text, engine=data.get("tokenize_engine", "newmm"))
+        tokenize_engine = data.get("tokenize_engine", "newmm")
+        if tokenize_engine == "auto":
+            tokens, tokenize_engine, _ = await in_worker(tokenize_auto, text)
+        else:
+            tokens = await in_worker(run_engine, "tokenize", tokenize_engine, word_tokenize, text, engine=tokenize_engine)
+        tokens = await in_worker(apply_granularity, tokens, granularity)
         if "tokenize" in features:
             result["tokens"] = tokens
         
         if "romanize" in features:
             engine = data.get("romanize_engine", "royin")
-            romanized_tokens = [romanize(token, engine=engine) for token in tokens]
+            romanized_tokens = await in_worker(romanize_tokens, tokens, engine, {"engine": engine}, data.get("batch_size", 0))
             result["romanized"] = " ".join(romanized_tokens)
             result["romanized_tokens"] = romanized_tokens
         
         if "transliterate" in features:
             engine = data.get("transliterate_engine", "thaig2p")
-            result["phonetic"] = transliterate(text, engine=engine)
+            result["phonetic"] = await in_worker(run_engine, "transliterate", engine, transliterate, text, engine=engine)
+            result["phonetic_tokens"] = await in_worker(lambda: [run_engine("transliterate", engine, transliterate, token, engine=engine) if token.strip() else token for token in tokens])
         
         if "syllable" in features:
             engine = data.get("syllable_engine", "han_solo")
-            result["syllables"] = syllable_tokenize(text, engine=engine)
+            result["syllables"] = await in_worker(run_engine, "syllable", engine, syllable_tokenize, text, engine=engine)
+        
+        if "lemma" in features:
+            engine = data.get("lemma_engine", "")
+            if engine and engine not in LEMMA_ENGINES:
+                return web.json_response({
+                    "data": None,
+                    "metadata": {},
+                    "error": {
+                        "code": "INVALID_ENGINE",
+                        "message": f"Engine '{engine}' not supported",
+                        "details": {"supported_engines": LEMMA_ENGINES}
+                    }
+                }, status=400)
+            result["lemmas"] = await in_worker(lemmatize_tokens, tokens, engine)
+        
+        if "frequency" in features:
+            ranks = await in_worker(word_ranks)
+            counts = [ranks.get(token, (0, 0)) for token in tokens]
+            result["frequency_ranks"] = [rank for rank, _ in counts]
+            result["frequencies"] = [freq for _, freq in counts]
         
         processing_time = (time.time() - start) * 1000
         
         return web.json_response({
             "data": result,
             "metadata": {
+                **({"granularity": granularity} if granularity else {}),
                 "features": features,
+                "tokenize_engine": tokenize_engine,
                 "version": pythainlp_version,
                 "processing_time_ms": round(processing_time, 2)
             },
             "error": None
         })
         
+    except OfflineModelMissing as e:
+        return offline_error_response(e)
     except Exception as e:
+        return internal_error_response(e)
+
+
+# Engines whose dependencies are only in the full requirements
+FULL_MODE_ENGINES = {
+    "tokenize": {"icu", "attacut", "deepcut", "oskut", "sefr_cut"},
+    "romanize": {"thai2rom", "thai2rom_onnx"},
+    "transliterate": {"icu", "ipa", "thaig2p", "thaig2p_v2"},
+    "syllable": {"ssg"},
+    "sentence": {"wtp"},
+    "ner": set(),
+    "reverse_transliterate": {"wunsen"},
+    "similarity": {"sentence_transformers"},
+    "lemma": {"esupar", "spacy_thai"},
+}
+
+# Corpora downloaded on first use, with their approximate download size in bytes.
+# Engines not listed ship their model inside their Python package.
+ENGINE_CORPORA = {
+    ("tokenize", "nercut"): ("thainer", 10_000_000),
+    ("ner", "thainer"): ("thainer", 10_000_000),
+    ("romanize", "thai2rom"): ("thai2rom-pytorch-attn", 20_000_000),
+    ("romanize", "thai2rom_onnx"): ("thai2rom_onnx", 10_000_000),
+    ("transliterate", "thaig2p"): ("thai-g2p", 15_000_000),
+}
+
+# Every engine the Go library knows about, per operation
+KNOWN_ENGINES = {
+    "tokenize": ["newmm", "longest", "icu", "attacut", "deepcut", "nercut", "nlpo3", "oskut", "sefr_cut", "tltk"],
+    "romanize": ["royin", "thai2rom", "thai2rom_onnx", "tltk", "lookup"],
+    "transliterate": ["thaig2p", "icu", "ipa", "tltk_g2p", "iso_11940", "tltk_ipa", "thaig2p_v2"],
+    "syllable": ["dict", "han_solo", "ssg", "tltk"],
+    "sentence": ["crfcut", "whitespace+newline", "whitespace", "tltk", "wtp"],
+    "ner": ["thainer"],
+    "reverse_transliterate": ["wunsen"],
+    "similarity": ["sentence_transformers"],
+    "lemma": ["esupar", "spacy_thai"],
+}
+
+
+def corpus_size_on_disk(name: str) -> Optional[int]:
+    """Return the size of a downloaded corpus, or None if it is not downloaded"""
+    from pythainlp.corpus import get_corpus_db_detail
+    from pythainlp.tools import get_pythainlp_data_path
+
+    detail = get_corpus_db_detail(name)
+    if not detail or not detail.get("filename"):
+        return None
+    path = os.path.join(get_pythainlp_data_path(), detail["filename"])
+    if os.path.isfile(path):
+        return os.path.getsize(path)
+    total = 0
+    for root, _, files in os.walk(path):
+        for f in files:
+            total += os.path.getsize(os.path.join(root, f))
+    return total
+
+
+async def handle_engines(request: web.Request) -> web.Response:
+    """Report every known engine with its availability and model state"""
+    try:
+        available = {
+            "tokenize": TOKENIZE_ENGINES,
+            "romanize": ROMANIZE_ENGINES,
+            "transliterate": TRANSLITERATE_ENGINES,
+            "syllable": SYLLABLE_ENGINES,
+            "sentence": SENTENCE_ENGINES,
+            "ner": ["thainer"],  # CRF-based, uses python-crfsuite
+            "reverse_transliterate": REVERSE_ENGINES,
+            "similarity": SIMILARITY_ENGINES,
+            "lemma": LEMMA_ENGINES,
+        }
+        engines = []
+        for operation, names in KNOWN_ENGINES.items():
+            for name in names:
+                corpus, approx_size = ENGINE_CORPORA.get((operation, name), ("", 0))
+                size = corpus_size_on_disk(corpus) if corpus else None
+                engines.append({
+                    "operation": operation,
+                    "name": name,
+                    "available": name in available[operation],
+                    "requires_full_mode": name in FULL_MODE_ENGINES[operation],
+                    "corpus": corpus,
+                    "model_downloaded": not corpus or size is not None,
+                    "model_size": size if size is not None else approx_size,
+                })
+
         return web.json_response({
-            "data": None,
+            "data": {"engines": engines},
             "metadata": {},
-            "error": {
-                "code": "INTERNAL_ERROR",
-                "message": str(e),
-                "details": {"traceback": traceback.format_exc()}
-            }
-        }, status=500)
+            "error": None
+        })
+
+    except Exception as e:
+        return internal_error_response(e)
+
+
+# The module each engine imports, and the pip package providing it
+ENGINE_PACKAGES = {
+    ("tokenize", "icu"): ("icu", "pyicu"),
+    ("tokenize", "attacut"): ("attacut", "attacut"),
+    ("tokenize", "deepcut"): ("deepcut", "deepcut"),
+    ("tokenize", "nercut"): ("pycrfsuite", "python-crfsuite"),
+    ("tokenize", "nlpo3"): ("nlpo3", "nlpo3"),
+    ("tokenize", "oskut"): ("oskut", "oskut"),
+    ("tokenize", "sefr_cut"): ("sefr_cut", "sefr_cut"),
+    ("tokenize", "tltk"): ("tltk", "tltk"),
+    ("romanize", "thai2rom"): ("torch", "torch"),
+    ("romanize", "thai2rom_onnx"): ("onnxruntime", "onnxruntime"),
+    ("romanize", "tltk"): ("tltk", "tltk"),
+    ("transliterate", "icu"): ("icu", "pyicu"),
+    ("transliterate", "ipa"): ("epitran", "epitran"),
+    ("transliterate", "thaig2p"): ("torch", "torch"),
+    ("transliterate", "thaig2p_v2"): ("transformers", "transformers"),
+    ("transliterate", "tltk_g2p"): ("tltk", "tltk"),
+    ("transliterate", "tltk_ipa"): ("tltk", "tltk"),
+    ("syllable", "han_solo"): ("pycrfsuite", "python-crfsuite"),
+    ("syllable", "ssg"): ("ssg", "ssg"),
+    ("syllable", "tltk"): ("tltk", "tltk"),
+    ("sentence", "crfcut"): ("pycrfsuite", "python-crfsuite"),
+    ("sentence", "tltk"): ("tltk", "tltk"),
+    ("sentence", "wtp"): ("wtpsplit", "wtpsplit"),
+    ("ner", "thainer"): ("pycrfsuite", "python-crfsuite"),
+    ("reverse_transliterate", "wunsen"): ("wunsen", "wunsen"),
+    ("similarity", "sentence_transformers"): ("sentence_transformers", "sentence-transformers"),
+    ("lemma", "esupar"): ("esupar", "esupar"),
+    ("lemma", "spacy_thai"): ("spacy_thai", "spacy_thai"),
+}
+
+# Engines fetching their model from the Hugging Face hub rather than as a
+# PyThaiNLP corpus: a probe does not load them, as that could download it
+HUB_MODEL_ENGINES = {("transliterate", "thaig2p_v2"), ("sentence", "wtp"), ("similarity", "sentence_transformers"), ("lemma", "esupar")}
+
+
+def probe_engine(operation: str, engine: str) -> Dict[str, Any]:
+    """Import an engine's package and load its model if it is on disk, without
+    downloading anything, and report what is missing"""
+    corpus, approx_size = ENGINE_CORPORA.get((operation, engine), ("", 0))
+    module, package = ENGINE_PACKAGES.get((operation, engine), ("", ""))
+    key = f"{operation}/{engine}"
+    result = {
+        "operation": operation,
+        "engine": engine,
+        "status": "available",
+        "requires_full_mode": engine in FULL_MODE_ENGINES[operation],
+        "package": package,
+        "corpus": corpus,
+        "model_size": 0,
+        "loaded": key in LOADED_MODELS,
+        "message": "",
+    }
+    if module:
+        try:
+            importlib.import_module(module)
+        except ImportError as e:
+            result.update(status="missing_package", message=str(e))
+            return result
+        except Exception as e:
+            result.update(status="load_failed", message=str(e))
+            return result
+    if corpus:
+        size = corpus_size_on_disk(corpus)
+        if size is None:
+            result.update(status="missing_model", model_size=approx_size)
+            return result
+        result["model_size"] = size
+
+    fn, available = PRELOAD_FUNCTIONS.get(operation, (None, []))
+    if result["loaded"] or fn is None or engine not in available or (operation, engine) in HUB_MODEL_ENGINES:
+        return result
+    try:
+        run_engine(operation, engine, fn, "ทดสอบ", engine=engine)
+        result["loaded"] = True
+    except OfflineModelMissing as e:
+        result.update(status="missing_model", corpus=str(e), model_size=approx_size)
+    except Exception as e:
+        result.update(status="load_failed", message=str(e))
+    return result
+
+
+async def handle_probe_engine(request: web.Request) -> web.Response:
+    """Handle engine probes"""
+    try:
+        data = await request.json()
+        operation = data.get("operation", "")
+        engine = data.get("engine", "")
+
+        if engine not in KNOWN_ENGINES.get(operation, []):
+            return web.json_response({
+                "data": None,
+                "metadata": {},
+                "error": {
+                    "code": "INVALID_ENGINE",
+                    "message": f"Engine '{engine}' not supported for '{operation}'",
+                    "details": {"supported_engines": KNOWN_ENGINES.get(operation, [])}
+                }
+            }, status=400)
+
+        start_time = time.time()
+        result = await in_worker(probe_engine, operation, engine)
+        processing_time = (time.time() - start_time) * 1000
+
+        return web.json_response({
+            "data": result,
+            "metadata": {
+                "engine": engine,
+                "version": pythainlp_version,
+                "processing_time_ms": processing_time
+            },
+            "error": None
+        })
+
+    except Exception as e:
+        return internal_error_response(e)
+
+
+async def handle_provinces(request: web.Request) -> web.Response:
+    """Report the provinces of PyThaiNLP's corpus with their English names and
+    abbreviations"""
+    try:
+        from pythainlp.corpus import provinces
+        result = [{
+            "name_th": p.get("name_th", ""),
+            "abbr_th": p.get("abbr_th", ""),
+            "name_en": p.get("name_en", ""),
+            "abbr_en": p.get("abbr_en", ""),
+        } for p in provinces(details=True)]
+
+        return web.json_response({
+            "data": {"provinces": result},
+            "metadata": {"version": pythainlp_version},
+            "error": None
+        })
+
+    except Exception as e:
+        return internal_error_response(e)
 
 
 async def handle_health(request: web.Request) -> web.Response:
     """Health check endpoint"""
+    with STATS_LOCK:
+        loaded_models = list(LOADED_MODELS.values())
     return web.json_response({
         "status": "ready",
         "version": pythainlp_version,
+        "restarts": RESTART_COUNT,
+        "protocol": PROTOCOL_VERSION,
+        "uptime_seconds": round(time.time() - START_TIME, 1),
+        "memory_rss": rss_bytes(),
+        "requests": {
+            "total": STATS["requests"],
+            "errors": STATS["errors"],
+        },
+        "queue_depth": STATS["in_flight"],
+        "workers": SERVICE_WORKERS,
+        "seed": SEED,
+        "deterministic": DETERMINISTIC,
+        "loaded_models": loaded_models,
+        "plugins": LOADED_PLUGINS,
+        "plugin_hash": PLUGIN_HASH,
+        "preload": os.environ.get("PYTHAINLP_PRELOAD", ""),
+        "limits": {
+            "max_text_length": MAX_TEXT_LENGTH,
+            "max_batch_size": MAX_BATCH_SIZE,
+            "max_request_bytes": MAX_REQUEST_BYTES,
+        },
         "engines": {
             "tokenize": TOKENIZE_ENGINES,
             "romanize": ROMANIZE_ENGINES,
@@ -438,22 +2543,105 @@ async def handle_health(request: web.Request) -> web.Response:
     })
 
 
+async def handle_stats(request: web.Request) -> web.Response:
+    """Request counters per endpoint and model cache hits, in plain JSON like /health"""
+    with STATS_LOCK:
+        model_cache = dict(MODEL_CACHE)
+    return web.json_response({
+        "uptime_seconds": round(time.time() - START_TIME, 1),
+        "requests": {
+            "total": STATS["requests"],
+            "errors": STATS["errors"],
+        },
+        "endpoints": {
+            path: {
+                "requests": int(s["requests"]),
+                "errors": int(s["errors"]),
+                "avg_latency_ms": round(s["total_ms"] / s["requests"], 2) if s["requests"] else 0.0,
+            }
+            for path, s in ENDPOINT_STATS.items()
+        },
+        "model_cache": model_cache,
+    })
+
+
+# Plugin modules: every *.py file in PYTHAINLP_PLUGIN_DIR defines
+# register(app), which adds its routes to the application. Handlers can import
+# the helpers of this module (in_worker, run_engine...) from __main__.
+PLUGIN_DIR = os.environ.get("PYTHAINLP_PLUGIN_DIR", "")
+# Checksum of the plugin files, reported so the Go manager can tell when the
+# running service has other plugins than configured
+PLUGIN_HASH = os.environ.get("PYTHAINLP_PLUGIN_HASH", "")
+LOADED_PLUGINS: List[str] = []
+
+
+def load_plugins(app: web.Application):
+    """Import the plugins and let them register their routes. A plugin that
+    fails is reported and skipped, so the built-in endpoints keep working."""
+    if not PLUGIN_DIR or not os.path.isdir(PLUGIN_DIR):
+        return
+    for filename in sorted(os.listdir(PLUGIN_DIR)):
+        name, ext = os.path.splitext(filename)
+        if ext != ".py" or name.startswith("_"):
+            continue
+        try:
+            spec = importlib.util.spec_from_file_location(f"pythainlp_plugin_{name}", os.path.join(PLUGIN_DIR, filename))
+            module = importlib.util.module_from_spec(spec)
+            sys.modules[spec.name] = module
+            spec.loader.exec_module(module)
+            if not hasattr(module, "register"):
+                print(f"Plugin {name} has no register(app) function, skipped", file=sys.stderr)
+                continue
+            module.register(app)
+            LOADED_PLUGINS.append(name)
+            print(f"Loaded plugin {name}", file=sys.stderr)
+        except Exception:
+            print(f"Failed to load plugin {name}:\n{traceback.format_exc()}", file=sys.stderr)
+
+
 def create_app() -> web.Application:
     """Create and configure the web application"""
-    app = web.Application()
+    app = web.Application(middlewares=[metadata_middleware, auth_middleware, protocol_middleware, stats_middleware, limits_middleware],
+                          client_max_size=MAX_REQUEST_BYTES)
     
     # Add routes
     app.router.add_post('/tokenize', handle_tokenize)
+    app.router.add_post('/tokenize_batch', handle_tokenize_batch)
     app.router.add_post('/romanize', handle_romanize)
+    app.router.add_post('/romanize_batch', handle_romanize_batch)
     app.router.add_post('/transliterate', handle_transliterate)
     app.router.add_post('/syllable_tokenize', handle_syllable_tokenize)
+    app.router.add_post('/syllable_phonetics', handle_syllable_phonetics)
     app.router.add_post('/analyze', handle_analyze)
+    app.router.add_post('/analyze_document', handle_analyze_document)
+    app.router.add_post('/paragraph_tokenize', handle_paragraph_tokenize)
+    app.router.add_post('/ner', handle_ner)
+    app.router.add_post('/person_names', handle_person_names)
+    app.router.add_post('/word_difficulty', handle_word_difficulty)
+    app.router.add_post('/preprocess', handle_preprocess)
+    app.router.add_post('/reverse_transliterate', handle_reverse_transliterate)
+    app.router.add_post('/similarity', handle_similarity)
+    app.router.add_post('/search_terms', handle_search_terms)
+    app.router.add_post('/probe_engine', handle_probe_engine)
     app.router.add_get('/health', handle_health)
+    app.router.add_get('/stats', handle_stats)
+    app.router.add_get('/engines', handle_engines)
+    app.router.add_get('/provinces', handle_provinces)
     
+    load_plugins(app)
     return app
 
 
+def on_listening(message: str) -> None:
+    """Called by run_app once the server accepts connections"""
+    print(message, file=sys.stderr)
+    startup_status("ready")
+
+
 if __name__ == '__main__':
+    preload_engines(os.environ.get("PYTHAINLP_PRELOAD", ""))
     app = create_app()
-    print("Starting PyThaiNLP HTTP service on port __PYTHAINLP_SERVICE_PORT__...", file=sys.stderr)
-    web.run_app(app, host='0.0.0.0', port=__PYTHAINLP_SERVICE_PORT__)
\ No newline at end of file
+    startup_status("start", "Starting the HTTP server")
+    port = int(os.environ.get("PYTHAINLP_SERVICE_PORT", "8080"))
+    print(f"Starting PyThaiNLP HTTP service on port {port}...", file=sys.stderr)
+    web.run_app(app, host='0.0.0.0', port=port, print=on_listening)
\ No newline at end of file
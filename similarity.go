@@ -0,0 +1,79 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// WithSimilarityModel sets the sentence-transformers model used by
+// Similarity and RankBySimilarity, as a Hugging Face model ID (default
+// sentence-transformers/paraphrase-multilingual-MiniLM-L12-v2). The model is
+// downloaded on first use.
+func WithSimilarityModel(model string) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.similarityModel = model
+	}
+}
+
+// SimilarityMatch is a candidate scored by RankBySimilarity
+type SimilarityMatch struct {
+	Index int     // Position in the candidates
+	Text  string  // The candidate
+	Score float64 // Cosine similarity, from -1 to 1
+}
+
+// Similarity returns the cosine similarity of the sentence embeddings of a
+// and b, from -1 to 1, higher meaning closer in meaning. It needs full mode;
+// otherwise the error wraps ErrEngineUnavailable.
+func (pm *PyThaiNLPManager) Similarity(ctx context.Context, a, b string) (float64, error) {
+	scores, err := pm.similarities(ctx, a, []string{b})
+	if err != nil {
+		return 0, err
+	}
+	return scores[0], nil
+}
+
+// RankBySimilarity scores every candidate against query in a single request
+// and returns them from most to least similar
+func (pm *PyThaiNLPManager) RankBySimilarity(ctx context.Context, query string, candidates []string) ([]SimilarityMatch, error) {
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	scores, err := pm.similarities(ctx, query, candidates)
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]SimilarityMatch, len(candidates))
+	for i, candidate := range candidates {
+		matches[i] = SimilarityMatch{Index: i, Text: candidate, Score: scores[i]}
+	}
+	slices.SortStableFunc(matches, func(a, b SimilarityMatch) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+		return 0
+	})
+	return matches, nil
+}
+
+func (pm *PyThaiNLPManager) similarities(ctx context.Context, text string, candidates []string) ([]float64, error) {
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := pm.client.Similarity(ctx, &SimilarityRequest{
+		Text:       text,
+		Candidates: candidates,
+		Model:      pm.similarityModel,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("similarity failed: %w", err)
+	}
+	if len(resp.Scores) != len(candidates) {
+		return nil, fmt.Errorf("similarity failed: got %d scores for %d candidates", len(resp.Scores), len(candidates))
+	}
+	return resp.Scores, nil
+}
@@ -0,0 +1,176 @@
+package pythainlp
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Span locates a token in the analyzed text, in characters (code points)
+type Span struct {
+	Start int
+	End   int
+	Token Token
+}
+
+// TokenSpans aligns tokens with the text they were produced from. Whitespace
+// tokens are dropped. It fails if a token cannot be found in order in text,
+// e.g. when an engine normalized the text.
+func TokenSpans(text string, tokens []Token) ([]Span, error) {
+	var spans []Span
+	pos := 0  // byte position in text
+	char := 0 // character position of pos
+	for _, token := range tokens {
+		if strings.TrimSpace(token.Surface) == "" {
+			continue
+		}
+		i := strings.Index(text[pos:], token.Surface)
+		if i < 0 {
+			return nil, fmt.Errorf("token %q not found in text after character %d", token.Surface, char)
+		}
+		char += utf8.RuneCountInString(text[pos : pos+i])
+		start := char
+		char += utf8.RuneCountInString(token.Surface)
+		pos += i + len(token.Surface)
+		spans = append(spans, Span{Start: start, End: char, Token: token})
+	}
+	return spans, nil
+}
+
+// entityChars converts the byte offsets of entities found by NER in text to
+// characters. Entities that could not be located are dropped.
+func entityChars(text string, entities []Entity) []Entity {
+	var converted []Entity
+	for _, e := range entities {
+		if e.Start < 0 || e.End < e.Start || e.End > len(text) {
+			continue
+		}
+		start := utf8.RuneCountInString(text[:e.Start])
+		converted = append(converted, Entity{
+			Text:  e.Text,
+			Type:  e.Type,
+			Start: start,
+			End:   start + utf8.RuneCountInString(text[e.Start:e.End]),
+		})
+	}
+	return converted
+}
+
+// WriteBrat writes the tokens and the named entities (see NER, may be nil)
+// as a brat standoff .ann file for text, which goes in the matching .txt
+// file. Each token becomes a text-bound annotation typed with its POS tag,
+// or "Token" if it has none, and its romanization becomes an annotator note.
+// Each entity becomes a text-bound annotation typed with its entity type,
+// after the tokens.
+func WriteBrat(w io.Writer, text string, tokens []Token, entities []Entity) error {
+	spans, err := TokenSpans(text, tokens)
+	if err != nil {
+		return err
+	}
+	for i, span := range spans {
+		kind := span.Token.POS
+		if kind == "" {
+			kind = "Token"
+		}
+		if _, err := fmt.Fprintf(w, "T%d\t%s %d %d\t%s\n", i+1, kind, span.Start, span.End, span.Token.Surface); err != nil {
+			return err
+		}
+		if span.Token.Romanization != "" {
+			if _, err := fmt.Fprintf(w, "#%d\tAnnotatorNotes T%d\t%s\n", i+1, i+1, span.Token.Romanization); err != nil {
+				return err
+			}
+		}
+	}
+	for i, e := range entityChars(text, entities) {
+		if _, err := fmt.Fprintf(w, "T%d\t%s %d %d\t%s\n", len(spans)+i+1, e.Type, e.Start, e.End, e.Text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WebAnnotation is a W3C Web Annotation of one token
+type WebAnnotation struct {
+	Context string        `json:"@context"`
+	ID      string        `json:"id"`
+	Type    string        `json:"type"`
+	Body    []TextualBody `json:"body,omitempty"`
+	Target  Target        `json:"target"`
+}
+
+// TextualBody is the body of a WebAnnotation: the POS tag or entity type
+// (purpose "tagging") or the romanization and IPA (purpose "describing")
+type TextualBody struct {
+	Type    string `json:"type"`
+	Purpose string `json:"purpose"`
+	Value   string `json:"value"`
+	Format  string `json:"format,omitempty"`
+}
+
+// Target points a WebAnnotation at a span of the source document
+type Target struct {
+	Source   string     `json:"source"`
+	Selector []Selector `json:"selector"`
+}
+
+// Selector is a TextPositionSelector or TextQuoteSelector
+type Selector struct {
+	Type  string `json:"type"`
+	Start *int   `json:"start,omitempty"`
+	End   *int   `json:"end,omitempty"`
+	Exact string `json:"exact,omitempty"`
+}
+
+// WebAnnotations returns a W3C Web Annotation per token and per named entity
+// (see NER, may be nil) of text, which is the content of the document at
+// source (an IRI). Each targets its span with both a position and a quote
+// selector; encode the result with encoding/json.
+func WebAnnotations(source, text string, tokens []Token, entities []Entity) ([]WebAnnotation, error) {
+	spans, err := TokenSpans(text, tokens)
+	if err != nil {
+		return nil, err
+	}
+	annotations := make([]WebAnnotation, len(spans))
+	for i, span := range spans {
+		var body []TextualBody
+		if span.Token.POS != "" {
+			body = append(body, TextualBody{Type: "TextualBody", Purpose: "tagging", Value: span.Token.POS})
+		}
+		if span.Token.Romanization != "" {
+			body = append(body, TextualBody{Type: "TextualBody", Purpose: "describing", Value: span.Token.Romanization, Format: "text/plain"})
+		}
+		if span.Token.IPA != "" {
+			body = append(body, TextualBody{Type: "TextualBody", Purpose: "describing", Value: span.Token.IPA, Format: "text/plain"})
+		}
+		annotations[i] = WebAnnotation{
+			Context: "http://www.w3.org/ns/anno.jsonld",
+			ID:      fmt.Sprintf("%s#token-%d", source, i+1),
+			Type:    "Annotation",
+			Body:    body,
+			Target: Target{
+				Source: source,
+				Selector: []Selector{
+					{Type: "TextPositionSelector", Start: &span.Start, End: &span.End},
+					{Type: "TextQuoteSelector", Exact: span.Token.Surface},
+				},
+			},
+		}
+	}
+	for i, e := range entityChars(text, entities) {
+		annotations = append(annotations, WebAnnotation{
+			Context: "http://www.w3.org/ns/anno.jsonld",
+			ID:      fmt.Sprintf("%s#entity-%d", source, i+1),
+			Type:    "Annotation",
+			Body:    []TextualBody{{Type: "TextualBody", Purpose: "tagging", Value: e.Type}},
+			Target: Target{
+				Source: source,
+				Selector: []Selector{
+					{Type: "TextPositionSelector", Start: &e.Start, End: &e.End},
+					{Type: "TextQuoteSelector", Exact: e.Text},
+				},
+			},
+		})
+	}
+	return annotations, nil
+}
@@ -0,0 +1,185 @@
+package pythainlp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// StartupPhase identifies the stage of starting the service
+type StartupPhase string
+
+const (
+	StartupPull           StartupPhase = "pull"            // Pulling the image
+	StartupBuild          StartupPhase = "build"           // Building the image from source
+	StartupCreate         StartupPhase = "create"          // Creating and starting the container, pulling the image if missing
+	StartupPipInstall     StartupPhase = "pip_install"     // Installing packages in the container
+	StartupStart          StartupPhase = "start"           // Starting the Python service
+	StartupModelLoad      StartupPhase = "model_load"      // Importing PyThaiNLP and loading engines
+	StartupCorpusDownload StartupPhase = "corpus_download" // Downloading a corpus or model
+	StartupReady          StartupPhase = "ready"
+	StartupFailed         StartupPhase = "failed"
+)
+
+// startupPhases lists the phases in the order a startup goes through them;
+// the download progress callback gets the index of each phase entered
+var startupPhases = []StartupPhase{
+	StartupPull, StartupBuild, StartupCreate, StartupPipInstall,
+	StartupStart, StartupModelLoad, StartupCorpusDownload, StartupReady,
+}
+
+// startupTimesFile records in the data directory how long each phase of the
+// last successful startup took, for ETAs of phases without measurable progress
+const startupTimesFile = "startup-times.json"
+
+// StartupStatus is a snapshot of the startup of the service
+type StartupStatus struct {
+	Phase   StartupPhase `json:"phase"`   // Empty before the first startup
+	Message string       `json:"message"` // e.g. the corpus or engine being loaded
+
+	// Current and Total measure the progress of the phase: bytes when
+	// pulling, steps when building or installing packages, engines when
+	// preloading. Both are 0 when it is not measured.
+	Current int64 `json:"current"`
+	Total   int64 `json:"total"`
+
+	Started      time.Time `json:"started"`       // When the startup began
+	PhaseStarted time.Time `json:"phase_started"` // When the current phase began
+
+	// ETA estimates the time left in the current phase: from the rate of
+	// progress when measured, otherwise from how long the phase took the last
+	// time the service started. 0 when unknown or done.
+	ETA time.Duration `json:"eta"`
+
+	Err error `json:"-"` // Why the startup failed, in StartupFailed
+}
+
+// StartupStatus returns the state of the startup in progress, or of the last
+// one. Poll it to show users what Init, or a lazy start, is waiting for.
+func (pm *PyThaiNLPManager) StartupStatus() StartupStatus {
+	pm.startupMu.Lock()
+	defer pm.startupMu.Unlock()
+
+	status := pm.startup
+	if !pm.startupActive {
+		return status
+	}
+	elapsed := time.Since(status.PhaseStarted)
+	switch {
+	case status.Total > 0 && status.Current > 0:
+		status.ETA = time.Duration(float64(elapsed) * float64(status.Total-status.Current) / float64(status.Current))
+	case pm.lastPhaseTimes[status.Phase] > elapsed:
+		status.ETA = pm.lastPhaseTimes[status.Phase] - elapsed
+	}
+	return status
+}
+
+// beginStartup starts tracking a startup, which recovers a service that
+// was already started
+func (pm *PyThaiNLPManager) beginStartup() {
+	if state := pm.Health(); state == HealthStopped || state == HealthStarting {
+		pm.setHealth(HealthStarting)
+	} else {
+		pm.setHealth(HealthRecovering)
+	}
+
+	now := time.Now()
+	last := make(map[StartupPhase]time.Duration)
+	if data, err := os.ReadFile(filepath.Join(pm.dataDir, startupTimesFile)); err == nil {
+		json.Unmarshal(data, &last)
+	}
+
+	pm.startupMu.Lock()
+	defer pm.startupMu.Unlock()
+	pm.startup = StartupStatus{Started: now, PhaseStarted: now}
+	pm.startupActive = true
+	pm.phaseTimes = make(map[StartupPhase]time.Duration)
+	pm.lastPhaseTimes = last
+}
+
+// startupProgress records the phase of the startup in progress and its
+// progress, if any. Entering a phase is reported to the download progress
+// callback as the index of the phase out of the number of phases; a failure
+// is not, as it is not one of startupPhases and ends the startup anyway.
+func (pm *PyThaiNLPManager) startupProgress(phase StartupPhase, message string, current, total int64) {
+	pm.startupMu.Lock()
+	if !pm.startupActive {
+		pm.startupMu.Unlock()
+		return
+	}
+	entered := phase != pm.startup.Phase
+	if entered {
+		now := time.Now()
+		if pm.startup.Phase != "" {
+			pm.phaseTimes[pm.startup.Phase] += now.Sub(pm.startup.PhaseStarted)
+		}
+		pm.startup.Phase = phase
+		pm.startup.PhaseStarted = now
+	}
+	pm.startup.Message = message
+	pm.startup.Current = current
+	pm.startup.Total = total
+	pm.startupMu.Unlock()
+
+	if !entered {
+		return
+	}
+	Logger.Debug().Str("phase", string(phase)).Str("message", message).Msg("Startup phase")
+	i := slices.Index(startupPhases, phase)
+	if pm.downloadProgressCallback != nil && i >= 0 {
+		status := string(phase)
+		if message != "" {
+			status += ": " + message
+		}
+		pm.downloadProgressCallback(int64(i), int64(len(startupPhases)), status)
+	}
+}
+
+// endStartup records the outcome of the startup in progress, and on success
+// how long its phases took
+func (pm *PyThaiNLPManager) endStartup(err error) {
+	if err != nil {
+		if pm.Health() == HealthRecovering {
+			pm.setHealth(HealthUnhealthy)
+		} else {
+			pm.setHealth(HealthStopped)
+		}
+		pm.startupProgress(StartupFailed, err.Error(), 0, 0)
+		pm.startupMu.Lock()
+		pm.startup.Err = err
+		pm.startupActive = false
+		pm.startupMu.Unlock()
+		return
+	}
+
+	pm.setHealth(HealthReady)
+	pm.startupProgress(StartupReady, "", 0, 0)
+	pm.startupMu.Lock()
+	pm.startupActive = false
+	times := pm.phaseTimes
+	pm.startupMu.Unlock()
+	if data, err := json.Marshal(times); err == nil {
+		if err := os.WriteFile(filepath.Join(pm.dataDir, startupTimesFile), data, 0644); err != nil {
+			Logger.Debug().Err(err).Msg("Failed to record startup times")
+		}
+	}
+}
+
+// onPullProgress records the image pull dockerutil makes, then passes its
+// progress on to the download progress callback, if any
+func (pm *PyThaiNLPManager) onPullProgress(current, total int64, status string) {
+	pm.startupProgress(StartupPull, "", current, total)
+	if pm.downloadProgressCallback != nil {
+		pm.downloadProgressCallback(current, total, status)
+	}
+}
+
+// serviceStartup is the startup status server.py writes to startupStatusPath
+type serviceStartup struct {
+	Phase   StartupPhase `json:"phase"`
+	Message string       `json:"message"`
+	Current int64        `json:"current"`
+	Total   int64        `json:"total"`
+}
@@ -0,0 +1,124 @@
+package subtitle
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp"
+)
+
+const defaultConcurrency = 4
+
+// Options configures Annotate and AddRomanization
+type Options struct {
+	Analyze     pythainlp.AnalyzeOptions // Used by Annotate (default: tokenize and romanize)
+	Engine      pythainlp.RomanizeEngine // Used by AddRomanization (default royin)
+	Concurrency int                      // Cues processed at once (default 4)
+}
+
+// AnnotatedCue pairs a cue with the analysis of its text. Analysis is nil for
+// cues without Thai text.
+type AnnotatedCue struct {
+	*Cue
+	Analysis *pythainlp.AnalyzeResult
+}
+
+// Annotate analyzes the plain text of every cue
+func Annotate(ctx context.Context, nlp pythainlp.ThaiNLP, f *File, opts Options) ([]AnnotatedCue, error) {
+	annotated := make([]AnnotatedCue, len(f.Cues))
+	err := forEachThaiCue(f, opts, func(i int, text string) error {
+		res, err := nlp.AnalyzeWithOptions(ctx, text, opts.Analyze)
+		annotated[i].Analysis = res
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, cue := range f.Cues {
+		annotated[i].Cue = cue
+	}
+	return annotated, nil
+}
+
+// AddRomanization appends the romanization of each Thai cue as a new last
+// line, with words separated by spaces. Cues without Thai text are left
+// unchanged.
+func AddRomanization(ctx context.Context, nlp pythainlp.ThaiNLP, f *File, opts Options) error {
+	romanized := make([]string, len(f.Cues))
+	err := forEachThaiCue(f, opts, func(i int, text string) error {
+		res, err := nlp.RomanizeWithOptions(ctx, text, pythainlp.RomanizeOptions{
+			Engine:        opts.Engine,
+			TokenizeFirst: true,
+		})
+		if err != nil {
+			return err
+		}
+		romanized[i] = res.Text
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i, cue := range f.Cues {
+		if romanized[i] != "" {
+			cue.Text += "\n" + romanized[i]
+		}
+	}
+	return nil
+}
+
+// forEachThaiCue calls fn concurrently with the index and plain text of every
+// cue containing Thai, returning the first error
+func forEachThaiCue(f *File, opts Options, fn func(i int, text string) error) error {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	errs := make([]error, len(f.Cues))
+	var wg sync.WaitGroup
+	for i, cue := range f.Cues {
+		text := PlainText(cue.Text)
+		if !strings.ContainsFunc(text, isThai) {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(i, text); err != nil {
+				errs[i] = fmt.Errorf("cue %d: %w", i+1, err)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	htmlTags     = regexp.MustCompile(`<[^>]*>`)
+	assOverrides = regexp.MustCompile(`\{[^}]*\}`)
+)
+
+// PlainText strips SRT and ASS formatting from cue text and joins its lines
+// with spaces, giving the text to analyze
+func PlainText(text string) string {
+	text = htmlTags.ReplaceAllString(text, "")
+	text = assOverrides.ReplaceAllString(text, "")
+	text = strings.NewReplacer(`\N`, " ", `\n`, " ", `\h`, " ", "\n", " ").Replace(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func isThai(r rune) bool {
+	return unicode.Is(unicode.Thai, r)
+}
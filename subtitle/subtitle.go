@@ -0,0 +1,229 @@
+// Package subtitle reads and writes SRT and ASS subtitles and runs their cue
+// text through PyThaiNLP, to annotate Thai subtitles or add a romanized line
+// under each cue.
+//
+//	f, err := subtitle.Parse(in)
+//	err = subtitle.AddRomanization(ctx, manager, f, subtitle.Options{})
+//	err = f.Write(out)
+package subtitle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is a subtitle file format
+type Format int
+
+const (
+	SRT Format = iota
+	ASS        // Also covers SSA
+)
+
+// Cue is one subtitle event
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string // Lines separated by "\n", formatting tags included
+
+	fields []string // ASS: the Dialogue fields before Text
+	line   int      // ASS: index of the Dialogue line in File.lines
+}
+
+// File is a parsed subtitle file. Writing it back preserves everything but
+// the cue text and timings, which callers may change.
+type File struct {
+	Format Format
+	Cues   []*Cue
+
+	lines      []string // ASS: every line of the file
+	format     []string // ASS: field names of the Dialogue lines
+	textField  int      // ASS: position of Text in the Dialogue format
+	lineEnding string
+}
+
+// Parse reads an SRT or ASS file, telling them apart by content
+func Parse(r io.Reader) (*File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	lineEnding := "\n"
+	if strings.Contains(text, "\r\n") {
+		lineEnding = "\r\n"
+	}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var f *File
+	if strings.HasPrefix(strings.TrimSpace(text), "[Script Info]") {
+		f, err = parseASS(lines)
+	} else {
+		f, err = parseSRT(lines)
+	}
+	if err != nil {
+		return nil, err
+	}
+	f.lineEnding = lineEnding
+	return f, nil
+}
+
+// Write writes the file in its original format
+func (f *File) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	var lines []string
+	if f.Format == ASS {
+		lines = f.assLines()
+	} else {
+		lines = f.srtLines()
+	}
+	for _, line := range lines {
+		bw.WriteString(line)
+		bw.WriteString(f.lineEnding)
+	}
+	return bw.Flush()
+}
+
+var srtTiming = regexp.MustCompile(`^(\d+):(\d{2}):(\d{2})[,.](\d{3})\s*-->\s*(\d+):(\d{2}):(\d{2})[,.](\d{3})`)
+
+func parseSRT(lines []string) (*File, error) {
+	f := &File{Format: SRT}
+	for i := 0; i < len(lines); i++ {
+		m := srtTiming.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if m == nil {
+			continue // cue numbers and blank lines
+		}
+		cue := &Cue{Start: srtTime(m[1:5]), End: srtTime(m[5:9])}
+		var text []string
+		for i++; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+			text = append(text, lines[i])
+		}
+		cue.Text = strings.Join(text, "\n")
+		f.Cues = append(f.Cues, cue)
+	}
+	if len(f.Cues) == 0 {
+		return nil, fmt.Errorf("no subtitle cues found")
+	}
+	return f, nil
+}
+
+func srtTime(parts []string) time.Duration {
+	var n [4]int
+	for i, p := range parts {
+		n[i], _ = strconv.Atoi(p)
+	}
+	return time.Duration(n[0])*time.Hour + time.Duration(n[1])*time.Minute +
+		time.Duration(n[2])*time.Second + time.Duration(n[3])*time.Millisecond
+}
+
+func (f *File) srtLines() []string {
+	var lines []string
+	for i, cue := range f.Cues {
+		lines = append(lines,
+			strconv.Itoa(i+1),
+			formatSRTTime(cue.Start)+" --> "+formatSRTTime(cue.End))
+		lines = append(lines, strings.Split(cue.Text, "\n")...)
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+func formatSRTTime(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+func parseASS(lines []string) (*File, error) {
+	f := &File{Format: ASS, lines: lines, textField: -1}
+	inEvents := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			inEvents = strings.EqualFold(trimmed, "[Events]")
+			continue
+		}
+		if !inEvents {
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Format":
+			f.format = strings.Split(value, ",")
+			for j := range f.format {
+				f.format[j] = strings.TrimSpace(f.format[j])
+				if f.format[j] == "Text" {
+					f.textField = j
+				}
+			}
+		case "Dialogue":
+			if f.textField < 0 || f.textField != len(f.format)-1 {
+				return nil, fmt.Errorf("line %d: the Events format must end with Text", i+1)
+			}
+			fields := strings.SplitN(strings.TrimLeft(value, " "), ",", len(f.format))
+			if len(fields) != len(f.format) {
+				return nil, fmt.Errorf("line %d: expected %d fields", i+1, len(f.format))
+			}
+			cue := &Cue{
+				Text:   strings.ReplaceAll(fields[f.textField], `\N`, "\n"),
+				fields: fields[:f.textField],
+				line:   i,
+			}
+			for j, name := range f.format {
+				switch name {
+				case "Start":
+					cue.Start = assTime(fields[j])
+				case "End":
+					cue.End = assTime(fields[j])
+				}
+			}
+			f.Cues = append(f.Cues, cue)
+		}
+	}
+	if len(f.Cues) == 0 {
+		return nil, fmt.Errorf("no Dialogue events found")
+	}
+	return f, nil
+}
+
+// assTime parses H:MM:SS.CC
+func assTime(s string) time.Duration {
+	var h, m, sec, cs int
+	fmt.Sscanf(strings.TrimSpace(s), "%d:%d:%d.%d", &h, &m, &sec, &cs)
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(cs)*10*time.Millisecond
+}
+
+func (f *File) assLines() []string {
+	lines := make([]string, len(f.lines))
+	copy(lines, f.lines)
+	// Trailing empty element from the final line break, written back by Write
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+	for _, cue := range f.Cues {
+		fields := append(append([]string(nil), cue.fields...), strings.ReplaceAll(cue.Text, "\n", `\N`))
+		for j, name := range f.format {
+			switch name {
+			case "Start":
+				fields[j] = formatASSTime(cue.Start)
+			case "End":
+				fields[j] = formatASSTime(cue.End)
+			}
+		}
+		lines[cue.line] = "Dialogue: " + strings.Join(fields, ",")
+	}
+	return lines
+}
+
+func formatASSTime(d time.Duration) string {
+	cs := d.Milliseconds() / 10
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
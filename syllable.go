@@ -5,13 +5,16 @@ import (
 	"fmt"
 )
 
-// SyllableTokenize performs syllable tokenization using the default engine (han_solo)
-func (pm *PyThaiNLPManager) SyllableTokenize(ctx context.Context, text string) (*SyllableTokenizeResult, error) {
-	return pm.SyllableTokenizeWithEngine(ctx, text, EngineSyllableHanSolo)
+// SyllableTokenize performs syllable tokenization using the default engine (han_solo) unless
+// opts say otherwise, e.g. WithEngine
+func (pm *PyThaiNLPManager) SyllableTokenize(ctx context.Context, text string, opts ...CallOption) (*SyllableTokenizeResult, error) {
+	return pm.SyllableTokenizeWithOptions(ctx, text, NewSyllableTokenizeOptions(opts...))
 }
 
 // SyllableTokenizeWithEngine performs syllable tokenization with a specified engine
-func (pm *PyThaiNLPManager) SyllableTokenizeWithEngine(ctx context.Context, text string, engine string) (*SyllableTokenizeResult, error) {
+//
+// Deprecated: use SyllableTokenize with WithEngine.
+func (pm *PyThaiNLPManager) SyllableTokenizeWithEngine(ctx context.Context, text string, engine SyllableEngine) (*SyllableTokenizeResult, error) {
 	opts := SyllableTokenizeOptions{
 		Engine: engine,
 	}
@@ -20,20 +23,34 @@ func (pm *PyThaiNLPManager) SyllableTokenizeWithEngine(ctx context.Context, text
 
 // SyllableTokenizeWithOptions performs syllable tokenization with full options
 func (pm *PyThaiNLPManager) SyllableTokenizeWithOptions(ctx context.Context, text string, opts SyllableTokenizeOptions) (*SyllableTokenizeResult, error) {
-	if !pm.IsReady() {
-		return nil, fmt.Errorf("service not ready")
+	return cached(pm, "syllable_tokenize", text, opts, func() (*SyllableTokenizeResult, error) {
+		return pm.syllableTokenize(ctx, text, opts)
+	})
+}
+
+// syllableTokenize is SyllableTokenizeWithOptions without the cache
+func (pm *PyThaiNLPManager) syllableTokenize(ctx context.Context, text string, opts SyllableTokenizeOptions) (*SyllableTokenizeResult, error) {
+	if err := opts.Engine.Validate(); err != nil {
+		return nil, err
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+	if err := pm.client.checkText(text); err != nil {
+		return nil, err
 	}
 
 	// Prepare request
 	req := &SyllableTokenizeRequest{
 		Text:           text,
-		Engine:         opts.Engine,
+		Engine:         string(opts.Engine),
 		KeepWhitespace: opts.KeepWhitespace,
+		Preprocess:     opts.Preprocess,
 	}
 
 	// Set default engine if not specified
 	if req.Engine == "" {
-		req.Engine = EngineSyllableHanSolo
+		req.Engine = string(EngineSyllableHanSolo)
 	}
 
 	// Make API call
@@ -42,17 +59,11 @@ func (pm *PyThaiNLPManager) SyllableTokenizeWithOptions(ctx context.Context, tex
 		return nil, fmt.Errorf("syllable tokenization failed: %w", err)
 	}
 
-	// Extract processing time
-	var processingTime float64
-	if v, ok := resp.Metadata["processing_time_ms"].(float64); ok {
-		processingTime = v
-	}
-
 	// Build result
 	result := &SyllableTokenizeResult{
-		Syllables:      resp.Syllables,
-		Engine:         req.Engine,
-		ProcessingTime: processingTime,
+		Syllables: resp.Syllables,
+		Text:      resp.Text,
+		Meta:      newMetadata(resp.Metadata, req.Engine),
 	}
 
 	return result, nil
@@ -61,17 +72,19 @@ func (pm *PyThaiNLPManager) SyllableTokenizeWithOptions(ctx context.Context, tex
 // Package-level functions for backward compatibility
 
 // SyllableTokenize performs syllable tokenization using the default engine
-func SyllableTokenize(text string) (*SyllableTokenizeResult, error) {
+func SyllableTokenize(text string, opts ...CallOption) (*SyllableTokenizeResult, error) {
 	ctx := context.Background()
 	mgr, err := getOrCreateDefaultManager(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return mgr.SyllableTokenize(ctx, text)
+	return mgr.SyllableTokenize(ctx, text, opts...)
 }
 
 // SyllableTokenizeWithEngine performs syllable tokenization with a specified engine
-func SyllableTokenizeWithEngine(text string, engine string) (*SyllableTokenizeResult, error) {
+//
+// Deprecated: use SyllableTokenize with WithEngine.
+func SyllableTokenizeWithEngine(text string, engine SyllableEngine) (*SyllableTokenizeResult, error) {
 	ctx := context.Background()
 	mgr, err := getOrCreateDefaultManager(ctx)
 	if err != nil {
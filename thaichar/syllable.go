@@ -0,0 +1,105 @@
+package thaichar
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Errors returned by CheckSyllable
+var (
+	ErrEmptySyllable       = errors.New("empty syllable")
+	ErrNotThaiLetter       = errors.New("not a Thai letter, vowel or sign")
+	ErrNoConsonant         = errors.New("no consonant")
+	ErrMisplacedLeading    = errors.New("leading vowel not at the start or not before a consonant")
+	ErrMisplacedFollowing  = errors.New("following vowel not after a consonant or its marks")
+	ErrMisplacedCombining  = errors.New("vowel or mark not above or below a consonant")
+	ErrStackedVowels       = errors.New("two vowels above or below the same consonant")
+	ErrToneBeforeVowel     = errors.New("tone mark written before the vowel of its consonant")
+	ErrMultipleToneMarks   = errors.New("more than one tone mark")
+	ErrCharacterAfterSaraA = errors.New("characters after sara a")
+)
+
+// IsValidSyllable reports whether CheckSyllable accepts s
+func IsValidSyllable(s string) bool {
+	return CheckSyllable(s) == nil
+}
+
+// CheckSyllable checks that s is spelled like a single written Thai syllable:
+// Thai letters only, at least one consonant, a leading vowel only at the
+// start and before a consonant, following vowels after a consonant, vowels
+// and marks stacked on a consonant in the standard order (vowel before tone
+// mark), and at most one tone mark. These are orthographic rules only: s may
+// still not be a word, and several syllables without leading vowels pass
+// as one.
+//
+// The error wraps one of the Err values and gives the byte offset of the
+// offending character.
+func CheckSyllable(s string) error {
+	if s == "" {
+		return ErrEmptySyllable
+	}
+	var (
+		prev       rune // previous character, 0 at the start
+		consonants int
+		tones      int
+		stacked    VowelPosition // vowel above or below the last consonant
+		toned      bool          // the last consonant has a tone mark
+	)
+	fail := func(i int, r rune, err error) error {
+		return fmt.Errorf("%q at byte %d: %w", r, i, err)
+	}
+	for i, r := range s {
+		if prev == 'ะ' {
+			return fail(i, r, ErrCharacterAfterSaraA)
+		}
+		switch {
+		case IsConsonant(r) || r == 'ฤ' || r == 'ฦ':
+			consonants++
+			stacked, toned = NotVowel, false
+		case VowelPositionOf(r) == Leading:
+			next, _ := utf8.DecodeRuneInString(s[i+utf8.RuneLen(r):])
+			if i != 0 || !IsConsonant(next) {
+				return fail(i, r, ErrMisplacedLeading)
+			}
+		case VowelPositionOf(r) == Following:
+			if prev == 0 || VowelPositionOf(prev) == Leading {
+				return fail(i, r, ErrMisplacedFollowing)
+			}
+		case IsToneMark(r):
+			if tones++; tones > 1 {
+				return fail(i, r, ErrMultipleToneMarks)
+			}
+			if !onConsonant(prev) {
+				return fail(i, r, ErrMisplacedCombining)
+			}
+			toned = true
+		case IsCombining(r):
+			if !onConsonant(prev) {
+				return fail(i, r, ErrMisplacedCombining)
+			}
+			if pos := VowelPositionOf(r); pos != NotVowel {
+				if toned {
+					return fail(i, r, ErrToneBeforeVowel)
+				}
+				if stacked != NotVowel {
+					return fail(i, r, ErrStackedVowels)
+				}
+				stacked = pos
+			}
+		default:
+			return fail(i, r, ErrNotThaiLetter)
+		}
+		prev = r
+	}
+	if consonants == 0 {
+		return ErrNoConsonant
+	}
+	return nil
+}
+
+// onConsonant reports whether a combining character can follow prev, i.e.
+// prev is a consonant or a character already stacked on one
+func onConsonant(prev rune) bool {
+	return IsConsonant(prev) || prev == 'ฤ' || prev == 'ฦ' || IsCombining(prev)
+}
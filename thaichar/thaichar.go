@@ -0,0 +1,178 @@
+// Package thaichar classifies Thai characters: consonant classes, vowel
+// positions, tone marks, and the orthographic rules placing them around
+// consonants. It is pure Go and needs no service.
+//
+//	thaichar.Class('ข')             // thaichar.High
+//	thaichar.VowelPositionOf('เ')   // thaichar.Leading
+//	thaichar.IsValidSyllable("ไก่") // true
+package thaichar
+
+import "strings"
+
+// ConsonantClass is the class of a Thai consonant, which with the tone mark
+// and syllable type determines the tone of a syllable
+type ConsonantClass int
+
+const (
+	NotConsonant ConsonantClass = iota
+	Low
+	Mid
+	High
+)
+
+func (c ConsonantClass) String() string {
+	switch c {
+	case Low:
+		return "low"
+	case Mid:
+		return "mid"
+	case High:
+		return "high"
+	default:
+		return "none"
+	}
+}
+
+const (
+	midConsonants  = "กจฎฏดตบปอ"
+	highConsonants = "ขฃฉฐถผฝศษสห"
+)
+
+// IsConsonant reports whether r is one of the 44 Thai consonants, ก to ฮ
+func IsConsonant(r rune) bool {
+	return r >= 'ก' && r <= 'ฮ' && r != 'ฤ' && r != 'ฦ'
+}
+
+// Class returns the class of consonant r, or NotConsonant
+func Class(r rune) ConsonantClass {
+	switch {
+	case !IsConsonant(r):
+		return NotConsonant
+	case strings.ContainsRune(midConsonants, r):
+		return Mid
+	case strings.ContainsRune(highConsonants, r):
+		return High
+	default:
+		return Low
+	}
+}
+
+// VowelPosition is where a vowel sign is written relative to the consonant
+// it is pronounced after
+type VowelPosition int
+
+const (
+	NotVowel  VowelPosition = iota
+	Leading                 // Before the consonant: เ แ โ ใ ไ
+	Following               // After the consonant: ะ า ำ ๅ
+	Above                   // Above the consonant: ั ิ ี ึ ื ็ ํ
+	Below                   // Below the consonant: ุ ู
+)
+
+func (p VowelPosition) String() string {
+	switch p {
+	case Leading:
+		return "leading"
+	case Following:
+		return "following"
+	case Above:
+		return "above"
+	case Below:
+		return "below"
+	default:
+		return "none"
+	}
+}
+
+// VowelPositionOf returns the position of vowel sign r, or NotVowel. The
+// groups follow PyThaiNLP's thai_lead_vowels, thai_follow_vowels,
+// thai_above_vowels and thai_below_vowels.
+func VowelPositionOf(r rune) VowelPosition {
+	switch r {
+	case 'เ', 'แ', 'โ', 'ใ', 'ไ':
+		return Leading
+	case 'ะ', 'า', 'ำ', 'ๅ':
+		return Following
+	case 'ั', 'ิ', 'ี', 'ึ', 'ื', '็', 'ํ':
+		return Above
+	case 'ุ', 'ู':
+		return Below
+	default:
+		return NotVowel
+	}
+}
+
+// IsVowel reports whether r is a Thai vowel sign
+func IsVowel(r rune) bool {
+	return VowelPositionOf(r) != NotVowel
+}
+
+// ToneMark is one of the four Thai tone marks
+type ToneMark int
+
+const (
+	NoToneMark  ToneMark = iota
+	MaiEk                // ่
+	MaiTho               // ้
+	MaiTri               // ๊
+	MaiChattawa          // ๋
+)
+
+func (t ToneMark) String() string {
+	switch t {
+	case MaiEk:
+		return "mai ek"
+	case MaiTho:
+		return "mai tho"
+	case MaiTri:
+		return "mai tri"
+	case MaiChattawa:
+		return "mai chattawa"
+	default:
+		return "none"
+	}
+}
+
+// ToneMarkOf returns the tone mark r, or NoToneMark
+func ToneMarkOf(r rune) ToneMark {
+	if r >= '่' && r <= '๋' {
+		return ToneMark(r-'่') + MaiEk
+	}
+	return NoToneMark
+}
+
+// IsToneMark reports whether r is a Thai tone mark
+func IsToneMark(r rune) bool {
+	return ToneMarkOf(r) != NoToneMark
+}
+
+// SyllableToneMark returns the tone mark written in s, or NoToneMark
+func SyllableToneMark(s string) ToneMark {
+	for _, r := range s {
+		if t := ToneMarkOf(r); t != NoToneMark {
+			return t
+		}
+	}
+	return NoToneMark
+}
+
+// IsThai reports whether r is in the Thai Unicode block
+func IsThai(r rune) bool {
+	return r >= 'ก' && r <= '๛'
+}
+
+// IsDigit reports whether r is a Thai digit, ๐ to ๙
+func IsDigit(r rune) bool {
+	return r >= '๐' && r <= '๙'
+}
+
+// IsCombining reports whether r is written above or below the preceding
+// character: above and below vowels, tone marks, and the thanthakhat ์ and
+// yamakkan ๎ signs
+func IsCombining(r rune) bool {
+	switch VowelPositionOf(r) {
+	case Above, Below:
+		return true
+	}
+	return IsToneMark(r) || r == '์' || r == '๎' || r == 'ฺ'
+}
@@ -0,0 +1,89 @@
+package thaichar
+
+import "testing"
+
+func TestClass(t *testing.T) {
+	for _, c := range []struct {
+		r    rune
+		want ConsonantClass
+	}{
+		{'ก', Mid},
+		{'อ', Mid},
+		{'ข', High},
+		{'ห', High},
+		{'ค', Low},
+		{'ฮ', Low},
+		{'ฤ', NotConsonant}, // A vowel letter within the consonant range
+		{'ฦ', NotConsonant},
+		{'า', NotConsonant},
+		{'a', NotConsonant},
+	} {
+		if got := Class(c.r); got != c.want {
+			t.Errorf("Class(%q) = %v, want %v", c.r, got, c.want)
+		}
+	}
+}
+
+func TestVowelPositionOf(t *testing.T) {
+	for _, c := range []struct {
+		r    rune
+		want VowelPosition
+	}{
+		{'เ', Leading},
+		{'ไ', Leading},
+		{'ะ', Following},
+		{'ำ', Following},
+		{'ั', Above},
+		{'็', Above},
+		{'ุ', Below},
+		{'ู', Below},
+		{'่', NotVowel}, // Tone mark
+		{'์', NotVowel},
+		{'ก', NotVowel},
+	} {
+		if got := VowelPositionOf(c.r); got != c.want {
+			t.Errorf("VowelPositionOf(%q) = %v, want %v", c.r, got, c.want)
+		}
+	}
+}
+
+func TestSyllableToneMark(t *testing.T) {
+	for _, c := range []struct {
+		s    string
+		want ToneMark
+	}{
+		{"ไก่", MaiEk},
+		{"บ้าน", MaiTho},
+		{"โต๊ะ", MaiTri},
+		{"จ๋า", MaiChattawa},
+		{"กิน", NoToneMark},
+		{"", NoToneMark},
+	} {
+		if got := SyllableToneMark(c.s); got != c.want {
+			t.Errorf("SyllableToneMark(%q) = %v, want %v", c.s, got, c.want)
+		}
+	}
+}
+
+func TestIsCombining(t *testing.T) {
+	for _, c := range []struct {
+		r    rune
+		want bool
+	}{
+		{'ิ', true},
+		{'ุ', true},
+		{'่', true},
+		{'๋', true},
+		{'์', true},
+		{'๎', true},
+		{'ฺ', true},
+		{'เ', false}, // Leading vowel
+		{'า', false}, // Following vowel
+		{'ก', false},
+		{'๑', false},
+	} {
+		if got := IsCombining(c.r); got != c.want {
+			t.Errorf("IsCombining(%q) = %v, want %v", c.r, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package thaivalid
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp/internal/provinces"
+)
+
+// Provinces are the 77 Thai provinces as written on license plates, with
+// Bangkok as กรุงเทพมหานคร, in Thai alphabetical order. They are those of
+// pythainlp.LocalProvinces.
+var Provinces = provinces.Names()
+
+// plateRegions are written on plates in place of a province: เบตง district
+// has plates of its own
+var plateRegions = []string{"เบตง"}
+
+// Plate is a Thai vehicle license plate
+type Plate struct {
+	Series   string // One or two Thai consonants, after a digit on newer plates, e.g. กข or 1กข
+	Number   string // One to four digits
+	Province string // Empty when the plate was written without it
+}
+
+// String writes the plate as it is usually written, e.g. 1กข 1234 เชียงใหม่
+func (p Plate) String() string {
+	if p.Province == "" {
+		return p.Series + " " + p.Number
+	}
+	return p.Series + " " + p.Number + " " + p.Province
+}
+
+var (
+	plateRe = regexp.MustCompile(`^([0-9๐-๙]?[ก-ฮ]{1,2})[ -]?([0-9๐-๙]{1,4})(?:\s+(\S+))?$`)
+	// platesRe requires the province so that words followed by a number, as
+	// in "คน 5", are not taken for plates
+	platesRe = regexp.MustCompile(`([0-9๐-๙]?[ก-ฮ]{1,2})[ -]?([0-9๐-๙]{1,4})\s*(` + strings.Join(append(slices.Clone(Provinces), plateRegions...), "|") + `)`)
+)
+
+// IsProvince reports whether name is a Thai province, as written on plates
+func IsProvince(name string) bool {
+	return slices.Contains(Provinces, name)
+}
+
+// ParsePlate reads a private vehicle license plate, such as "กข 1234",
+// "1กข-1234" or "1กข 1234 กรุงเทพมหานคร". The province is optional, but when
+// present it must be one of Provinces or เบตง.
+func ParsePlate(s string) (Plate, bool) {
+	m := plateRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Plate{}, false
+	}
+	if m[3] != "" && !IsProvince(m[3]) && !slices.Contains(plateRegions, m[3]) {
+		return Plate{}, false
+	}
+	return Plate{Series: m[1], Number: digits(m[2]), Province: m[3]}, true
+}
+
+// ValidPlate reports whether s is a Thai vehicle license plate
+func ValidPlate(s string) bool {
+	_, ok := ParsePlate(s)
+	return ok
+}
+
+// Plates returns the license plates of text written with their province. A
+// plate must not follow a Thai letter, as the series would then be the end
+// of a word, nor a digit; nor may a Thai letter follow the province, as in
+// ตากใบ, a district whose name starts with that of ตาก.
+func Plates(text string) []Match {
+	var matches []Match
+	for _, loc := range platesRe.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if prev, _ := utf8.DecodeLastRuneInString(text[:start]); isThai(prev) || isDigit(prev) {
+			continue
+		}
+		if next, _ := utf8.DecodeRuneInString(text[end:]); isThai(next) {
+			continue
+		}
+		matches = append(matches, Match{Text: text[start:end], Start: start, End: end})
+	}
+	return matches
+}
+
+func isThai(r rune) bool {
+	return r >= 'ก' && r <= '๛'
+}
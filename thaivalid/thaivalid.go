@@ -0,0 +1,151 @@
+// Package thaivalid validates and extracts Thai structured data: citizen ID
+// numbers, phone numbers and vehicle license plates. It is pure Go and needs
+// no service. Thai digits (๐ to ๙) are accepted wherever ASCII ones are.
+//
+//	thaivalid.ValidNationalID("1-1037-02071-81-1") // true
+//	p, ok := thaivalid.ParsePhone("+66 81 234 5678")
+//	p.String()                                     // "081-234-5678"
+//	thaivalid.Plates("รถ กข 1234 กรุงเทพมหานคร ชน") // [{กข 1234 กรุงเทพมหานคร 7 58}]
+package thaivalid
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Match is a piece of structured data found in a text. Start and End are byte
+// offsets into the text.
+type Match struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// digits returns the digits of s as ASCII, dropping everything else
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r >= '๐' && r <= '๙':
+			b.WriteRune(r - '๐' + '0')
+		}
+	}
+	return b.String()
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9' || r >= '๐' && r <= '๙'
+}
+
+// find returns the matches of re in text that valid accepts and that are not
+// part of a longer number
+func find(re *regexp.Regexp, text string, valid func(string) bool) []Match {
+	var matches []Match
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if isDigit(before) || isDigit(after) || !valid(text[start:end]) {
+			continue
+		}
+		matches = append(matches, Match{Text: text[start:end], Start: start, End: end})
+	}
+	return matches
+}
+
+// nationalIDRe matches 13 digits, optionally grouped 1-4-5-2-1
+var nationalIDRe = regexp.MustCompile(`[0-9๐-๙][ -]?[0-9๐-๙]{4}[ -]?[0-9๐-๙]{5}[ -]?[0-9๐-๙]{2}[ -]?[0-9๐-๙]`)
+
+// ValidNationalID reports whether id is a Thai citizen ID number: 13 digits,
+// optionally separated by spaces or dashes, whose last digit is the check
+// digit of the first twelve
+func ValidNationalID(id string) bool {
+	d := digits(id)
+	if len(d) != 13 || strings.TrimLeft(id, "0123456789๐๑๒๓๔๕๖๗๘๙ -") != "" {
+		return false
+	}
+	sum := 0
+	for i := range 12 {
+		sum += int(d[i]-'0') * (13 - i)
+	}
+	return int(d[12]-'0') == (11-sum%11)%10
+}
+
+// FormatNationalID writes a valid citizen ID as it is printed on the card,
+// 1-2345-67890-12-3, or returns "" if it is not valid
+func FormatNationalID(id string) string {
+	if !ValidNationalID(id) {
+		return ""
+	}
+	d := digits(id)
+	return d[:1] + "-" + d[1:5] + "-" + d[5:10] + "-" + d[10:12] + "-" + d[12:]
+}
+
+// NationalIDs returns the valid citizen ID numbers of text
+func NationalIDs(text string) []Match {
+	return find(nationalIDRe, text, ValidNationalID)
+}
+
+// Phone is a Thai phone number
+type Phone struct {
+	Number string // In national format, digits only, e.g. 0812345678
+	Mobile bool   // 06, 08 and 09 numbers; others are landlines
+}
+
+// phoneRe matches 9 or 10 digits after a leading 0 or the +66 country code,
+// grouped in any way; ParsePhone checks the length against the prefix
+var phoneRe = regexp.MustCompile(`(?:\+66[ -]?|[0๐])[2-9๒-๙](?:[ -]?[0-9๐-๙]){7,8}`)
+
+// ParsePhone reads a Thai mobile or landline number written with a leading 0
+// or with the +66 country code, with or without spaces and dashes
+func ParsePhone(s string) (Phone, bool) {
+	s = strings.TrimSpace(s)
+	if loc := phoneRe.FindStringIndex(s); loc == nil || loc[0] != 0 || loc[1] != len(s) {
+		return Phone{}, false
+	}
+	d := digits(s)
+	if strings.HasPrefix(s, "+66") {
+		d = "0" + d[2:]
+	}
+	mobile := strings.ContainsRune("689", rune(d[1]))
+	landline := strings.ContainsRune("23457", rune(d[1]))
+	if !(mobile && len(d) == 10 || landline && len(d) == 9) {
+		return Phone{}, false
+	}
+	return Phone{Number: d, Mobile: mobile}, true
+}
+
+// ValidPhone reports whether s is a Thai mobile or landline number
+func ValidPhone(s string) bool {
+	_, ok := ParsePhone(s)
+	return ok
+}
+
+// String writes the number as it is usually grouped: 081-234-5678 for a
+// mobile, 02-123-4567 in Bangkok and 053-123-456 elsewhere
+func (p Phone) String() string {
+	n := p.Number
+	switch {
+	case len(n) < 9:
+		return n
+	case p.Mobile:
+		return n[:3] + "-" + n[3:6] + "-" + n[6:]
+	case n[1] == '2':
+		return n[:2] + "-" + n[2:5] + "-" + n[5:]
+	default:
+		return n[:3] + "-" + n[3:6] + "-" + n[6:]
+	}
+}
+
+// E164 writes the number in international format, e.g. +66812345678
+func (p Phone) E164() string {
+	return "+66" + p.Number[1:]
+}
+
+// Phones returns the Thai phone numbers of text
+func Phones(text string) []Match {
+	return find(phoneRe, text, ValidPhone)
+}
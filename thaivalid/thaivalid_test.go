@@ -0,0 +1,126 @@
+package thaivalid
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidNationalID(t *testing.T) {
+	for _, c := range []struct {
+		id   string
+		want bool
+	}{
+		{"1-1037-02071-81-1", true},
+		{"1103702071811", true},
+		{"1 1037 02071 81 1", true},
+		{"๑๑๐๓๗๐๒๐๗๑๘๑๑", true},
+		{"3101200123453", true},
+		{"1103702071812", false}, // Wrong check digit
+		{"110370207181", false},  // Twelve digits
+		{"11037020718111", false},
+		{"1-1037-02071-81-1x", false},
+		{"", false},
+	} {
+		if got := ValidNationalID(c.id); got != c.want {
+			t.Errorf("ValidNationalID(%q) = %v, want %v", c.id, got, c.want)
+		}
+	}
+
+	if got := FormatNationalID("1103702071811"); got != "1-1037-02071-81-1" {
+		t.Errorf("FormatNationalID = %q", got)
+	}
+	if got := FormatNationalID("1103702071812"); got != "" {
+		t.Errorf("FormatNationalID of an invalid ID = %q, want empty", got)
+	}
+}
+
+func TestParsePhone(t *testing.T) {
+	for _, c := range []struct {
+		in     string
+		number string // Empty when the number is not valid
+		mobile bool
+		format string
+	}{
+		{"+66 81 234 5678", "0812345678", true, "081-234-5678"},
+		{"081-234-5678", "0812345678", true, "081-234-5678"},
+		{"0612345678", "0612345678", true, "061-234-5678"},
+		{"๐๘๑๒๓๔๕๖๗๘", "0812345678", true, "081-234-5678"},
+		{"02-123-4567", "021234567", false, "02-123-4567"},
+		{"+6621234567", "021234567", false, "02-123-4567"},
+		{"053 123 456", "053123456", false, "053-123-456"},
+		{"081234567", "", false, ""},  // Mobile with nine digits
+		{"0212345678", "", false, ""}, // Landline with ten digits
+		{"0112345678", "", false, ""},
+		{"81 234 5678", "", false, ""},
+		{"tel 0812345678", "", false, ""},
+	} {
+		p, ok := ParsePhone(c.in)
+		if ok != (c.number != "") {
+			t.Errorf("ParsePhone(%q) ok = %v", c.in, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if p.Number != c.number || p.Mobile != c.mobile || p.String() != c.format {
+			t.Errorf("ParsePhone(%q) = %+v %q, want %s %v %q", c.in, p, p, c.number, c.mobile, c.format)
+		}
+	}
+
+	if got := (Phone{Number: "0812345678", Mobile: true}).E164(); got != "+66812345678" {
+		t.Errorf("E164 = %q", got)
+	}
+}
+
+func TestParsePlate(t *testing.T) {
+	if len(Provinces) != 77 || !IsProvince("กรุงเทพมหานคร") {
+		t.Fatalf("Provinces = %d names, want the 77 provinces", len(Provinces))
+	}
+
+	for _, c := range []struct {
+		in   string
+		want Plate
+		ok   bool
+	}{
+		{"กข 1234", Plate{Series: "กข", Number: "1234"}, true},
+		{"1กข-1234", Plate{Series: "1กข", Number: "1234"}, true},
+		{"1กข 1234 กรุงเทพมหานคร", Plate{Series: "1กข", Number: "1234", Province: "กรุงเทพมหานคร"}, true},
+		{"ก ๑๒ เชียงใหม่", Plate{Series: "ก", Number: "12", Province: "เชียงใหม่"}, true},
+		{"กข 12 เบตง", Plate{Series: "กข", Number: "12", Province: "เบตง"}, true},
+		{"กข 12 ตากใบ", Plate{}, false}, // A district, not a province
+		{"กขค 1234", Plate{}, false},
+		{"กข 12345", Plate{}, false},
+		{"1234", Plate{}, false},
+	} {
+		p, ok := ParsePlate(c.in)
+		if ok != c.ok || p != c.want {
+			t.Errorf("ParsePlate(%q) = %+v, %v, want %+v, %v", c.in, p, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		find func(string) []Match
+		text string
+		want []Match
+	}{
+		{"plate", Plates, "รถ กข 1234 กรุงเทพมหานคร ชน", []Match{{"กข 1234 กรุงเทพมหานคร", 7, 58}}},
+		{"plate at end", Plates, "กข 12 ตาก", []Match{{"กข 12 ตาก", 0, 19}}},
+		{"plate before punctuation", Plates, "(กข 12 ตาก)", []Match{{"กข 12 ตาก", 1, 20}}},
+		{"province prefix of a district", Plates, "กข 12 ตากใบ", nil},
+		{"series ending a word", Plates, "รถกข 12 ตาก", nil},
+		{"plate after a digit", Plates, "1กข 12 ตาก", []Match{{"1กข 12 ตาก", 0, 20}}},
+		{"word followed by a number", Plates, "คน 5 คน", nil},
+		{"national ID", NationalIDs, "เลข 1-1037-02071-81-1 ครับ", []Match{{"1-1037-02071-81-1", 10, 27}}},
+		{"national ID with wrong check digit", NationalIDs, "1103702071812", nil},
+		{"national ID in a longer number", NationalIDs, "11037020718110", nil},
+		{"phone", Phones, "โทร 081-234-5678 นะ", []Match{{"081-234-5678", 10, 22}}},
+		{"phone in a longer number", Phones, "0812345678901", nil},
+	} {
+		if got := c.find(c.text); !slices.Equal(got, c.want) {
+			t.Errorf("%s: %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
@@ -1,17 +1,23 @@
 package pythainlp
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 )
 
-// Tokenize performs word tokenization using the default engine (newmm)
-func (pm *PyThaiNLPManager) Tokenize(ctx context.Context, text string) (*TokenizeResult, error) {
-	return pm.TokenizeWithEngine(ctx, text, EngineNewMM)
+// Tokenize performs word tokenization using the default engine (newmm) unless
+// opts say otherwise, e.g. WithEngine
+func (pm *PyThaiNLPManager) Tokenize(ctx context.Context, text string, opts ...CallOption) (*TokenizeResult, error) {
+	return pm.TokenizeWithOptions(ctx, text, NewTokenizeOptions(opts...))
 }
 
 // TokenizeWithEngine performs word tokenization with a specified engine
-func (pm *PyThaiNLPManager) TokenizeWithEngine(ctx context.Context, text string, engine string) (*TokenizeResult, error) {
+//
+// Deprecated: use Tokenize with WithEngine.
+func (pm *PyThaiNLPManager) TokenizeWithEngine(ctx context.Context, text string, engine TokenizeEngine) (*TokenizeResult, error) {
 	opts := TokenizeOptions{
 		Engine: engine,
 	}
@@ -20,68 +26,174 @@ func (pm *PyThaiNLPManager) TokenizeWithEngine(ctx context.Context, text string,
 
 // TokenizeWithOptions performs word tokenization with full options
 func (pm *PyThaiNLPManager) TokenizeWithOptions(ctx context.Context, text string, opts TokenizeOptions) (*TokenizeResult, error) {
-	if !pm.IsReady() {
-		return nil, fmt.Errorf("service not ready")
+	return cached(pm, "tokenize", text, opts, func() (*TokenizeResult, error) {
+		return pm.tokenize(ctx, text, opts)
+	})
+}
+
+// tokenize is TokenizeWithOptions without the cache
+func (pm *PyThaiNLPManager) tokenize(ctx context.Context, text string, opts TokenizeOptions) (*TokenizeResult, error) {
+	if err := errors.Join(opts.Engine.Validate(), opts.Granularity.Validate()); err != nil {
+		return nil, err
+	}
+	if pm.client.checkText(text) == nil && pm.tokenizeCoalescible(text, opts) {
+		return pm.coalescedTokenize(ctx, text, opts)
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+	if err := pm.client.checkText(text); err != nil {
+		return pm.tokenizeChunks(ctx, text, opts, int(pm.Limits().MaxTextLength))
+	}
+
+	req := pm.tokenizeRequest(text, opts)
+	resp, err := pm.client.Tokenize(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("tokenization failed: %w", err)
 	}
 
-	// Prepare request
+	result := newTokenizeResult(cmp.Or(resp.Text, text), resp.Tokens, newMetadata(resp.Metadata, req.Engine))
+	result.Text = resp.Text
+	return result, nil
+}
+
+// TokenizeInto tokenizes text like Tokenize and stores the result in dst,
+// reusing the memory of its Tokens and Raw slices. Consumers tokenizing
+// millions of texts can keep one TokenizeResult per goroutine, or pool them,
+// to spare the garbage collector; the tokens of a previous call must no
+// longer be in use. TokenizeInto always calls the service, bypassing request
+// coalescing and the shared cache.
+func (pm *PyThaiNLPManager) TokenizeInto(ctx context.Context, text string, dst *TokenizeResult, opts ...CallOption) error {
+	options := NewTokenizeOptions(opts...)
+	if err := errors.Join(options.Engine.Validate(), options.Granularity.Validate()); err != nil {
+		return err
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return err
+	}
+	if err := pm.client.checkText(text); err != nil {
+		return err
+	}
+
+	req := pm.tokenizeRequest(text, options)
+	resp, err := pm.client.tokenizeInto(ctx, req, dst.Raw)
+	if err != nil {
+		return fmt.Errorf("tokenization failed: %w", err)
+	}
+
+	fillTokenizeResult(dst, cmp.Or(resp.Text, text), resp.Tokens, newMetadata(resp.Metadata, req.Engine))
+	dst.Text = resp.Text
+	return nil
+}
+
+// tokenizeRequest builds the request tokenizing text with opts
+func (pm *PyThaiNLPManager) tokenizeRequest(text string, opts TokenizeOptions) *TokenizeRequest {
 	req := &TokenizeRequest{
 		Text:    text,
-		Engine:  opts.Engine,
+		Engine:  string(opts.Engine),
 		Options: opts.Extra,
+		Protect: opts.Protect,
+
+		KeepWhitespace: unlessSet(opts.DropWhitespace),
+		JoinBrokenNum:  unlessSet(opts.SplitBrokenNum),
+		Granularity:    string(opts.Granularity),
+		Preprocess:     opts.Preprocess,
 	}
 
 	// Set default engine if not specified
 	if req.Engine == "" {
-		req.Engine = EngineNewMM
+		req.Engine = string(EngineNewMM)
 	}
-
-	// Make API call
-	resp, err := pm.client.Tokenize(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("tokenization failed: %w", err)
+	if budget := pm.loadBudgetMs(); budget > 0 {
+		req.LoadBudgetMs = budget
+		req.FallbackEngine = string(pm.fallback.Tokenize)
 	}
+	return req
+}
 
-	// Extract processing time
-	var processingTime float64
-	if v, ok := resp.Metadata["processing_time_ms"].(float64); ok {
-		processingTime = v
+// unlessSet returns a pointer to false if off is set, or nil to leave the
+// word_tokenize flag at PyThaiNLP's default
+func unlessSet(off bool) *bool {
+	if !off {
+		return nil
 	}
+	return new(bool)
+}
 
-	// Build result
-	result := &TokenizeResult{
-		Raw:            resp.Tokens,
-		Engine:         req.Engine,
-		ProcessingTime: processingTime,
-	}
+// newTokenizeResult builds the result for the tokens of text
+func newTokenizeResult(text string, tokens []string, meta Metadata) *TokenizeResult {
+	result := &TokenizeResult{}
+	fillTokenizeResult(result, text, tokens, meta)
+	return result
+}
+
+// fillTokenizeResult stores the tokens of text in result, reusing the memory
+// of result.Tokens
+func fillTokenizeResult(result *TokenizeResult, text string, tokens []string, meta Metadata) {
+	result.Raw = tokens
+	result.Meta = meta
 
 	// Create Token objects with just the surface text for now
 	// Future versions can add more linguistic information
-	result.Tokens = make([]Token, len(resp.Tokens))
-	for i, token := range resp.Tokens {
-		result.Tokens[i] = Token{
+	result.Tokens = slices.Grow(result.Tokens[:0], len(tokens))
+	for _, token := range tokens {
+		result.Tokens = append(result.Tokens, Token{
 			Surface:   token,
 			IsLexical: isThaiText(token),
-		}
+		})
 	}
+	alignTokens(ScriptSpan{End: len(text), Text: text}, result.Tokens)
+}
 
-	return result, nil
+// TokenizeBatch tokenizes many texts in a single request and returns their
+// tokens in order. Empty texts get no tokens.
+func (pm *PyThaiNLPManager) TokenizeBatch(ctx context.Context, texts []string, opts TokenizeOptions) ([][]string, error) {
+	if err := errors.Join(opts.Engine.Validate(), opts.Granularity.Validate()); err != nil {
+		return nil, err
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+
+	engine := string(opts.Engine)
+	if engine == "" {
+		engine = string(EngineNewMM)
+	}
+	tokens, err := inBatches(pm, texts, func(batch []string) ([][]string, error) {
+		return pm.client.TokenizeBatch(ctx, &TokenizeBatchRequest{
+			Texts:          batch,
+			Engine:         engine,
+			Options:        opts.Extra,
+			KeepWhitespace: unlessSet(opts.DropWhitespace),
+			JoinBrokenNum:  unlessSet(opts.SplitBrokenNum),
+			Granularity:    string(opts.Granularity),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("batch tokenization failed: %w", err)
+	}
+	if len(tokens) != len(texts) {
+		return nil, fmt.Errorf("batch tokenization failed: got %d results for %d texts", len(tokens), len(texts))
+	}
+	return tokens, nil
 }
 
 // Package-level functions for backward compatibility
 
 // Tokenize performs word tokenization using the default engine
-func Tokenize(text string) (*TokenizeResult, error) {
+func Tokenize(text string, opts ...CallOption) (*TokenizeResult, error) {
 	ctx := context.Background()
 	mgr, err := getOrCreateDefaultManager(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return mgr.Tokenize(ctx, text)
+	return mgr.Tokenize(ctx, text, opts...)
 }
 
 // TokenizeWithEngine performs word tokenization with a specified engine
-func TokenizeWithEngine(text string, engine string) (*TokenizeResult, error) {
+//
+// Deprecated: use Tokenize with WithEngine.
+func TokenizeWithEngine(text string, engine TokenizeEngine) (*TokenizeResult, error) {
 	ctx := context.Background()
 	mgr, err := getOrCreateDefaultManager(ctx)
 	if err != nil {
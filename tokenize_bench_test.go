@@ -0,0 +1,58 @@
+package pythainlp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// benchManager returns a manager talking to a stub service that tokenizes
+// every text into the same thousand tokens
+func benchManager(b *testing.B) (*PyThaiNLPManager, string) {
+	tokens := make([]string, 1000)
+	for i := range tokens {
+		tokens[i] = []string{"สวัสดี", "ครับ", " ", "ภาษา", "ไทย"}[i%5]
+	}
+	body, err := json.Marshal(map[string]any{
+		"data":     map[string]any{"tokens": tokens},
+		"metadata": map[string]any{"engine": "newmm", "processing_time_ms": 1.0},
+		"error":    nil,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	b.Cleanup(srv.Close)
+
+	pm := &PyThaiNLPManager{client: NewClient(srv.URL, 10*time.Second), serviceReady: true}
+	return pm, strings.Join(tokens, "")
+}
+
+func BenchmarkTokenize(b *testing.B) {
+	pm, text := benchManager(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := pm.Tokenize(ctx, text); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTokenizeInto(b *testing.B) {
+	pm, text := benchManager(b)
+	ctx := context.Background()
+	var result TokenizeResult
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := pm.TokenizeInto(ctx, text, &result); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
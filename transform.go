@@ -0,0 +1,123 @@
+package pythainlp
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// maxPendingThai bounds how much Thai text without a break the transformer
+// buffers before romanizing it anyway
+const maxPendingThai = 1024
+
+// RomanizeTransformer romanizes the Thai runs of a UTF-8 stream and copies
+// everything else unchanged. It buffers input up to a safe boundary, the end
+// of a non-Thai character such as a space or line break, so that words are
+// not split across requests.
+type RomanizeTransformer struct {
+	ctx     context.Context
+	nlp     ThaiNLP
+	opts    RomanizeOptions
+	pending []byte // output of consumed input not yet written to dst
+}
+
+var _ transform.Transformer = (*RomanizeTransformer)(nil)
+
+// NewRomanizeTransformer returns a transformer romanizing with nlp, for use
+// with transform.NewReader, transform.NewWriter or transform.Chain. Requests
+// are made with ctx.
+//
+//	r := transform.NewReader(file, pythainlp.NewRomanizeTransformer(ctx, manager, pythainlp.RomanizeOptions{TokenizeFirst: true}))
+//	_, err := io.Copy(os.Stdout, r)
+func NewRomanizeTransformer(ctx context.Context, nlp ThaiNLP, opts RomanizeOptions) *RomanizeTransformer {
+	return &RomanizeTransformer{ctx: ctx, nlp: nlp, opts: opts}
+}
+
+// Transform implements transform.Transformer
+func (t *RomanizeTransformer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for {
+		n := copy(dst[nDst:], t.pending)
+		nDst += n
+		t.pending = t.pending[n:]
+		if len(t.pending) > 0 {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		if nSrc == len(src) {
+			return nDst, nSrc, nil
+		}
+		end := safeBoundary(src[nSrc:], atEOF)
+		if end == 0 {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		out, err := t.romanize(src[nSrc : nSrc+end])
+		if err != nil {
+			return nDst, nSrc, err
+		}
+		t.pending = out
+		nSrc += end
+	}
+}
+
+// Reset implements transform.Transformer
+func (t *RomanizeTransformer) Reset() {
+	t.pending = nil
+}
+
+// safeBoundary returns the length of the longest prefix of src that can be
+// romanized on its own, or 0 if more input is needed
+func safeBoundary(src []byte, atEOF bool) int {
+	if atEOF {
+		return len(src)
+	}
+	boundary, last := 0, 0
+	for i := 0; i < len(src) && utf8.FullRune(src[i:]); {
+		r, size := utf8.DecodeRune(src[i:])
+		i += size
+		last = i
+		if !isThaiRune(r) {
+			boundary = i
+		}
+	}
+	// A long run without any break, e.g. a Thai paragraph without spaces
+	if boundary == 0 && last >= maxPendingThai {
+		return last
+	}
+	return boundary
+}
+
+// romanize romanizes the Thai runs of chunk
+func (t *RomanizeTransformer) romanize(chunk []byte) ([]byte, error) {
+	var out []byte
+	for len(chunk) > 0 {
+		thai := isThaiRune(firstRune(chunk))
+		n := 0
+		for n < len(chunk) {
+			r, size := utf8.DecodeRune(chunk[n:])
+			if isThaiRune(r) != thai {
+				break
+			}
+			n += size
+		}
+		if !thai {
+			out = append(out, chunk[:n]...)
+		} else {
+			res, err := t.nlp.RomanizeWithOptions(t.ctx, string(chunk[:n]), t.opts)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, res.Text...)
+		}
+		chunk = chunk[n:]
+	}
+	return out, nil
+}
+
+func firstRune(b []byte) rune {
+	r, _ := utf8.DecodeRune(b)
+	return r
+}
+
+func isThaiRune(r rune) bool {
+	return r >= 0x0E00 && r <= 0x0E7F
+}
@@ -0,0 +1,107 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TranslitMode mirrors the processing modes of translitkit providers
+type TranslitMode string
+
+const (
+	TranslitTokenize      TranslitMode = "tokenization"    // Split text into tokens
+	TranslitTransliterate TranslitMode = "transliteration" // Romanize tokens already split
+	TranslitCombined      TranslitMode = "combined"        // Both in a single request
+)
+
+// TranslitOptions configures a TranslitProvider
+type TranslitOptions struct {
+	TokenizeEngine TokenizeEngine
+	RomanizeEngine RomanizeEngine
+	// IPA also fills Token.IPA in combined mode, at the cost of a
+	// transliteration per token
+	IPA bool
+}
+
+// TranslitProvider adapts a ThaiNLP (usually a *PyThaiNLPManager) to the
+// chunk-in, tokens-out model of translitkit providers. It holds the glue a
+// provider needs — mode dispatch, chunk iteration, token mapping — so that a
+// translitkit provider is a thin wrapper converting Token to its own type.
+type TranslitProvider struct {
+	nlp  ThaiNLP
+	opts TranslitOptions
+}
+
+// NewTranslitProvider returns a provider processing text with nlp
+func NewTranslitProvider(nlp ThaiNLP, opts TranslitOptions) *TranslitProvider {
+	return &TranslitProvider{nlp: nlp, opts: opts}
+}
+
+// Process runs mode over the input chunks and returns the tokens of all
+// chunks in order. In TranslitTransliterate mode each chunk is one token.
+func (p *TranslitProvider) Process(ctx context.Context, mode TranslitMode, chunks []string) ([]Token, error) {
+	var tokens []Token
+	for i, chunk := range chunks {
+		var chunkTokens []Token
+		var err error
+		switch mode {
+		case TranslitTokenize:
+			chunkTokens, err = p.tokenize(ctx, chunk)
+		case TranslitTransliterate:
+			chunkTokens, err = p.romanize(ctx, chunk)
+		case TranslitCombined:
+			chunkTokens, err = p.combined(ctx, chunk)
+		default:
+			return nil, fmt.Errorf("unsupported mode %q", mode)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d: %w", i, err)
+		}
+		tokens = append(tokens, chunkTokens...)
+	}
+	return tokens, nil
+}
+
+func (p *TranslitProvider) tokenize(ctx context.Context, chunk string) ([]Token, error) {
+	if strings.TrimSpace(chunk) == "" {
+		return []Token{{Surface: chunk}}, nil
+	}
+	res, err := p.nlp.TokenizeWithOptions(ctx, chunk, NewTokenizeOptions(WithEngine(p.opts.TokenizeEngine)))
+	if err != nil {
+		return nil, err
+	}
+	return res.Tokens, nil
+}
+
+func (p *TranslitProvider) romanize(ctx context.Context, token string) ([]Token, error) {
+	t := Token{Surface: token, IsLexical: isThaiText(token)}
+	if !t.IsLexical {
+		return []Token{t}, nil
+	}
+	res, err := p.nlp.RomanizeWithOptions(ctx, token, RomanizeOptions{Engine: p.opts.RomanizeEngine})
+	if err != nil {
+		return nil, err
+	}
+	t.Romanization = res.Text
+	return []Token{t}, nil
+}
+
+func (p *TranslitProvider) combined(ctx context.Context, chunk string) ([]Token, error) {
+	if strings.TrimSpace(chunk) == "" {
+		return []Token{{Surface: chunk}}, nil
+	}
+	features := []string{"tokenize", "romanize"}
+	if p.opts.IPA {
+		features = append(features, "transliterate")
+	}
+	res, err := p.nlp.AnalyzeWithOptions(ctx, chunk, AnalyzeOptions{
+		Features:       features,
+		TokenizeEngine: p.opts.TokenizeEngine,
+		RomanizeEngine: p.opts.RomanizeEngine,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Tokens, nil
+}
@@ -1,17 +1,23 @@
 package pythainlp
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 )
 
-// Romanize performs romanization using the default engine (royin)
-func (pm *PyThaiNLPManager) Romanize(ctx context.Context, text string) (*RomanizeResult, error) {
-	return pm.RomanizeWithEngine(ctx, text, EngineRoyin)
+// Romanize performs romanization using the default engine (royin) unless
+// opts say otherwise, e.g. WithEngine
+func (pm *PyThaiNLPManager) Romanize(ctx context.Context, text string, opts ...CallOption) (*RomanizeResult, error) {
+	return pm.RomanizeWithOptions(ctx, text, NewRomanizeOptions(opts...))
 }
 
 // RomanizeWithEngine performs romanization with a specified engine
-func (pm *PyThaiNLPManager) RomanizeWithEngine(ctx context.Context, text string, engine string) (*RomanizeResult, error) {
+//
+// Deprecated: use Romanize with WithEngine.
+func (pm *PyThaiNLPManager) RomanizeWithEngine(ctx context.Context, text string, engine RomanizeEngine) (*RomanizeResult, error) {
 	opts := RomanizeOptions{
 		Engine: engine,
 	}
@@ -20,20 +26,63 @@ func (pm *PyThaiNLPManager) RomanizeWithEngine(ctx context.Context, text string,
 
 // RomanizeWithOptions performs romanization with full options
 func (pm *PyThaiNLPManager) RomanizeWithOptions(ctx context.Context, text string, opts RomanizeOptions) (*RomanizeResult, error) {
-	if !pm.IsReady() {
-		return nil, fmt.Errorf("service not ready")
+	return cached(pm, "romanize", text, pm.romanizeCacheKey(opts), func() (*RomanizeResult, error) {
+		return pm.romanize(ctx, text, opts)
+	})
+}
+
+// romanize is RomanizeWithOptions without the cache
+func (pm *PyThaiNLPManager) romanize(ctx context.Context, text string, opts RomanizeOptions) (*RomanizeResult, error) {
+	if err := errors.Join(opts.Engine.Validate(), opts.FallbackEngine.Validate()); err != nil {
+		return nil, err
+	}
+	if opts.Consistent {
+		if opts.Preprocess == nil {
+			return pm.romanizeConsistent(ctx, text, opts)
+		}
+		// The table of words is built from the preprocessed text
+		processed, err := pm.Preprocess(ctx, text, *opts.Preprocess)
+		if err != nil {
+			return nil, err
+		}
+		opts.Preprocess = nil
+		result, err := pm.romanizeConsistent(ctx, processed, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.Input = processed
+		return result, nil
+	}
+	if err := pm.client.checkText(text); err != nil {
+		return nil, err
+	}
+	if pm.romanizeCoalescible(text, opts) {
+		return pm.coalescedRomanize(ctx, text, opts)
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
 	}
 
 	// Prepare request
 	req := &RomanizeRequest{
 		Text:     text,
-		Engine:   opts.Engine,
-		Tokenize: opts.TokenizeFirst,
+		Engine:   string(opts.Engine),
+		Tokenize: opts.TokenizeFirst || opts.Align,
+
+		LookupFallback: string(opts.FallbackEngine),
+		Overrides:      pm.romanizeOverrides(opts.Overrides),
+		ProperNouns:    opts.ProperNouns,
+		BatchSize:      pm.romanizeBatchSize,
+		Preprocess:     opts.Preprocess,
 	}
 
 	// Set default engine if not specified
 	if req.Engine == "" {
-		req.Engine = EngineRoyin
+		req.Engine = string(EngineRoyin)
+	}
+	if budget := pm.loadBudgetMs(); budget > 0 {
+		req.LoadBudgetMs = budget
+		req.FallbackEngine = string(pm.fallback.Romanize)
 	}
 
 	// Make API call
@@ -42,31 +91,103 @@ func (pm *PyThaiNLPManager) RomanizeWithOptions(ctx context.Context, text string
 		return nil, fmt.Errorf("romanization failed: %w", err)
 	}
 
-	// Extract processing time
-	var processingTime float64
-	if v, ok := resp.Metadata["processing_time_ms"].(float64); ok {
-		processingTime = v
-	}
-
 	// Build result
 	result := &RomanizeResult{
 		Text:           resp.Romanized,
 		Tokens:         resp.Tokens,
 		RomanizedParts: resp.RomanizedTokens,
-		Engine:         req.Engine,
-		ProcessingTime: processingTime,
+		Entities:       resp.Entities,
+		Input:          resp.Text,
+		Meta:           newMetadata(resp.Metadata, req.Engine),
+	}
+	if opts.Align {
+		result.Alignment = alignSegments(cmp.Or(resp.Text, text), resp.Tokens, resp.RomanizedTokens)
 	}
 
 	return result, nil
 }
 
-// Transliterate performs transliteration (phonetic conversion) using the default engine (thaig2p)
-func (pm *PyThaiNLPManager) Transliterate(ctx context.Context, text string) (*TransliterateResult, error) {
-	return pm.TransliterateWithEngine(ctx, text, EngineThaig2p)
+// RomanizeBatch romanizes many texts in a single request and returns their
+// romanizations in order. Each text is romanized whole, as without
+// TokenizeFirst; the other options besides Engine and FallbackEngine are
+// ignored. Empty texts romanize to "".
+func (pm *PyThaiNLPManager) RomanizeBatch(ctx context.Context, texts []string, opts RomanizeOptions) ([]string, error) {
+	if err := errors.Join(opts.Engine.Validate(), opts.FallbackEngine.Validate()); err != nil {
+		return nil, err
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+
+	engine := string(opts.Engine)
+	if engine == "" {
+		engine = string(EngineRoyin)
+	}
+	romanized, err := inBatches(pm, texts, func(batch []string) ([]string, error) {
+		return pm.client.RomanizeBatch(ctx, &RomanizeBatchRequest{
+			Texts:          batch,
+			Engine:         engine,
+			LookupFallback: string(opts.FallbackEngine),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("batch romanization failed: %w", err)
+	}
+	if len(romanized) != len(texts) {
+		return nil, fmt.Errorf("batch romanization failed: got %d results for %d texts", len(romanized), len(texts))
+	}
+	return romanized, nil
+}
+
+// WithRomanizeOverrides registers romanizations of words, e.g. names and
+// brands, that take precedence over the engine in every romanization. Each
+// word is kept a single token.
+func WithRomanizeOverrides(overrides map[string]string) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.overrides = maps.Clone(overrides)
+	}
+}
+
+// WithRomanizeBatchSize sets how many words thai2rom romanizes at once when
+// romanizing token by token (TokenizeFirst, AnalyzeText). By default the
+// service uses 32 on CPU and 256 when the model runs on a GPU; larger batches
+// are faster until they run out of memory.
+func WithRomanizeBatchSize(n int) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.romanizeBatchSize = n
+	}
+}
+
+// romanizeCacheKey returns opts with the manager's overrides, which change
+// the result, merged in
+func (pm *PyThaiNLPManager) romanizeCacheKey(opts RomanizeOptions) RomanizeOptions {
+	opts.Overrides = pm.romanizeOverrides(opts.Overrides)
+	return opts
+}
+
+// romanizeOverrides merges the overrides of a call into those of the manager
+func (pm *PyThaiNLPManager) romanizeOverrides(call map[string]string) map[string]string {
+	if len(call) == 0 {
+		return pm.overrides
+	}
+	merged := maps.Clone(pm.overrides)
+	if merged == nil {
+		merged = make(map[string]string, len(call))
+	}
+	maps.Copy(merged, call)
+	return merged
+}
+
+// Transliterate performs transliteration (phonetic conversion) using the default engine (thaig2p) unless
+// opts say otherwise, e.g. WithEngine
+func (pm *PyThaiNLPManager) Transliterate(ctx context.Context, text string, opts ...CallOption) (*TransliterateResult, error) {
+	return pm.TransliterateWithOptions(ctx, text, NewTransliterateOptions(opts...))
 }
 
 // TransliterateWithEngine performs transliteration with a specified engine
-func (pm *PyThaiNLPManager) TransliterateWithEngine(ctx context.Context, text string, engine string) (*TransliterateResult, error) {
+//
+// Deprecated: use Transliterate with WithEngine.
+func (pm *PyThaiNLPManager) TransliterateWithEngine(ctx context.Context, text string, engine TransliterateEngine) (*TransliterateResult, error) {
 	opts := TransliterateOptions{
 		Engine: engine,
 	}
@@ -75,19 +196,38 @@ func (pm *PyThaiNLPManager) TransliterateWithEngine(ctx context.Context, text st
 
 // TransliterateWithOptions performs transliteration with full options
 func (pm *PyThaiNLPManager) TransliterateWithOptions(ctx context.Context, text string, opts TransliterateOptions) (*TransliterateResult, error) {
-	if !pm.IsReady() {
-		return nil, fmt.Errorf("service not ready")
+	return cached(pm, "transliterate", text, opts, func() (*TransliterateResult, error) {
+		return pm.transliterate(ctx, text, opts)
+	})
+}
+
+// transliterate is TransliterateWithOptions without the cache
+func (pm *PyThaiNLPManager) transliterate(ctx context.Context, text string, opts TransliterateOptions) (*TransliterateResult, error) {
+	if err := opts.Engine.Validate(); err != nil {
+		return nil, err
+	}
+	if err := pm.ensureReady(ctx); err != nil {
+		return nil, err
+	}
+	if err := pm.client.checkText(text); err != nil {
+		return nil, err
 	}
 
 	// Prepare request
 	req := &TransliterateRequest{
-		Text:   text,
-		Engine: opts.Engine,
+		Text:       text,
+		Engine:     string(opts.Engine),
+		Tokenize:   opts.Align,
+		Preprocess: opts.Preprocess,
 	}
 
 	// Set default engine if not specified
 	if req.Engine == "" {
-		req.Engine = EngineThaig2p
+		req.Engine = string(EngineThaig2p)
+	}
+	if budget := pm.loadBudgetMs(); budget > 0 {
+		req.LoadBudgetMs = budget
+		req.FallbackEngine = string(pm.fallback.Transliterate)
 	}
 
 	// Make API call
@@ -96,41 +236,40 @@ func (pm *PyThaiNLPManager) TransliterateWithOptions(ctx context.Context, text s
 		return nil, fmt.Errorf("transliteration failed: %w", err)
 	}
 
-	// Extract processing time
-	var processingTime float64
-	if v, ok := resp.Metadata["processing_time_ms"].(float64); ok {
-		processingTime = v
-	}
-
 	// Build result
 	result := &TransliterateResult{
-		Phonetic:       resp.Phonetic,
-		Engine:         req.Engine,
-		ProcessingTime: processingTime,
+		Phonetic: resp.Phonetic,
+		Text:     resp.Text,
+		Meta:     newMetadata(resp.Metadata, req.Engine),
+	}
+	if opts.Align {
+		result.Alignment = alignSegments(cmp.Or(resp.Text, text), resp.Tokens, resp.PhoneticTokens)
 	}
 
 	return result, nil
 }
 
 // Pronunciate is an alias for Transliterate, following PyThaiNLP naming
-func (pm *PyThaiNLPManager) Pronunciate(ctx context.Context, text string) (*TransliterateResult, error) {
-	return pm.Transliterate(ctx, text)
+func (pm *PyThaiNLPManager) Pronunciate(ctx context.Context, text string, opts ...CallOption) (*TransliterateResult, error) {
+	return pm.Transliterate(ctx, text, opts...)
 }
 
 // Package-level functions for backward compatibility
 
 // Romanize performs romanization using the default engine
-func Romanize(text string) (*RomanizeResult, error) {
+func Romanize(text string, opts ...CallOption) (*RomanizeResult, error) {
 	ctx := context.Background()
 	mgr, err := getOrCreateDefaultManager(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return mgr.Romanize(ctx, text)
+	return mgr.Romanize(ctx, text, opts...)
 }
 
 // RomanizeWithEngine performs romanization with a specified engine
-func RomanizeWithEngine(text string, engine string) (*RomanizeResult, error) {
+//
+// Deprecated: use Romanize with WithEngine.
+func RomanizeWithEngine(text string, engine RomanizeEngine) (*RomanizeResult, error) {
 	ctx := context.Background()
 	mgr, err := getOrCreateDefaultManager(ctx)
 	if err != nil {
@@ -150,17 +289,19 @@ func RomanizeWithOptions(text string, opts RomanizeOptions) (*RomanizeResult, er
 }
 
 // Transliterate performs transliteration using the default engine
-func Transliterate(text string) (*TransliterateResult, error) {
+func Transliterate(text string, opts ...CallOption) (*TransliterateResult, error) {
 	ctx := context.Background()
 	mgr, err := getOrCreateDefaultManager(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return mgr.Transliterate(ctx, text)
+	return mgr.Transliterate(ctx, text, opts...)
 }
 
 // TransliterateWithEngine performs transliteration with a specified engine
-func TransliterateWithEngine(text string, engine string) (*TransliterateResult, error) {
+//
+// Deprecated: use Transliterate with WithEngine.
+func TransliterateWithEngine(text string, engine TransliterateEngine) (*TransliterateResult, error) {
 	ctx := context.Background()
 	mgr, err := getOrCreateDefaultManager(ctx)
 	if err != nil {
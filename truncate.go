@@ -0,0 +1,107 @@
+package pythainlp
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp/thaichar"
+)
+
+const (
+	// ellipsis marks text cut by TruncateAtBoundary
+	ellipsis = "…"
+
+	// truncateLookahead is how many runes past the cut are tokenized, so
+	// that the word crossing it is segmented in context
+	truncateLookahead = 32
+)
+
+// TruncateAtBoundary shortens text to at most maxRunes runes, ellipsis
+// included, for UI snippets or SMS-length limits. Text that fits is returned
+// unchanged. Otherwise it is cut at the last word boundary that fits, or
+// within a first word too long to fit at a syllable boundary, and "…" is
+// appended. Only if a single syllable is too long is it cut between
+// characters, and then never between a consonant and its vowels and marks.
+func (pm *PyThaiNLPManager) TruncateAtBoundary(ctx context.Context, text string, maxRunes int) (string, error) {
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text, nil
+	}
+	if maxRunes < 1 {
+		return "", nil
+	}
+	budget := maxRunes - utf8.RuneCountInString(ellipsis)
+
+	prefix := runePrefix(text, maxRunes+truncateLookahead)
+	words, err := pm.Tokenize(ctx, prefix)
+	if err != nil {
+		return "", err
+	}
+	cut, next := fitTokens(prefix, words.Raw, budget)
+	if strings.TrimSpace(cut) == "" && next != "" {
+		// The first word does not fit: cut it between syllables
+		syllables, err := pm.SyllableTokenize(ctx, next)
+		if err != nil {
+			return "", err
+		}
+		fit, _ := fitTokens(next, syllables.Syllables, budget-utf8.RuneCountInString(cut))
+		if fit == "" {
+			fit = clusterPrefix(next, budget-utf8.RuneCountInString(cut))
+		}
+		cut += fit
+	}
+	return strings.TrimRightFunc(cut, unicode.IsSpace) + ellipsis, nil
+}
+
+// fitTokens returns the longest prefix of text made of whole tokens that has
+// at most budget runes, and the token that did not fit
+func fitTokens(text string, tokens []string, budget int) (fit, next string) {
+	pos, runes := 0, 0
+	for _, token := range tokens {
+		if !strings.HasPrefix(text[pos:], token) {
+			// The tokenizer changed the text; stop at what is known
+			break
+		}
+		n := utf8.RuneCountInString(token)
+		if runes+n > budget {
+			return text[:pos], token
+		}
+		pos += len(token)
+		runes += n
+	}
+	return text[:pos], ""
+}
+
+// clusterPrefix returns at most n runes of s, cut before a consonant so that
+// no vowel or mark is separated from it
+func clusterPrefix(s string, n int) string {
+	runes := []rune(runePrefix(s, n+1))
+	cut := min(n, len(runes))
+	for cut > 0 && cut < len(runes) && !startsCluster(runes[cut]) {
+		cut--
+	}
+	for cut > 0 && thaichar.VowelPositionOf(runes[cut-1]) == thaichar.Leading {
+		cut--
+	}
+	return string(runes[:cut])
+}
+
+// startsCluster reports whether text can be cut before r
+func startsCluster(r rune) bool {
+	if thaichar.IsCombining(r) {
+		return false
+	}
+	return thaichar.VowelPositionOf(r) != thaichar.Following
+}
+
+// runePrefix returns the first n runes of s
+func runePrefix(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
@@ -0,0 +1,65 @@
+package pythainlp
+
+import "testing"
+
+func TestFitTokens(t *testing.T) {
+	for _, c := range []struct {
+		text      string
+		tokens    []string
+		budget    int
+		fit, next string
+	}{
+		{"ไปโรงเรียน", []string{"ไป", "โรงเรียน"}, 5, "ไป", "โรงเรียน"},
+		{"ไปโรงเรียน", []string{"ไป", "โรงเรียน"}, 10, "ไปโรงเรียน", ""},
+		{"ไปโรงเรียน", []string{"ไป", "โรงเรียน"}, 0, "", "ไป"},
+		{"ไป โรง", []string{"ไป", "โรง"}, 10, "ไป", ""}, // Whitespace dropped by the tokenizer
+		{"ไป", nil, 10, "", ""},
+	} {
+		fit, next := fitTokens(c.text, c.tokens, c.budget)
+		if fit != c.fit || next != c.next {
+			t.Errorf("fitTokens(%q, %q, %d) = %q, %q, want %q, %q", c.text, c.tokens, c.budget, fit, next, c.fit, c.next)
+		}
+	}
+}
+
+func TestClusterPrefix(t *testing.T) {
+	for _, c := range []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"กินข้าว", 3, "กิน"},
+		{"กินข้าว", 2, "กิ"},
+		{"ข้าว", 3, "ข้า"},
+		{"ข้าว", 2, ""}, // Not before the following vowel, nor the tone mark
+		{"ข้าว", 1, ""},
+		{"กาเก", 3, "กา"}, // Not after a leading vowel
+		{"เกเร", 3, "เก"},
+		{"แม่", 1, ""},
+		{"โต๊ะ", 0, ""},
+		{"ก", 1, "ก"},
+		{"abc", 5, "abc"},
+	} {
+		if got := clusterPrefix(c.s, c.n); got != c.want {
+			t.Errorf("clusterPrefix(%q, %d) = %q, want %q", c.s, c.n, got, c.want)
+		}
+	}
+}
+
+func TestRunePrefix(t *testing.T) {
+	for _, c := range []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"ไทย", 0, ""},
+		{"ไทย", 1, "ไ"},
+		{"ไทย", 3, "ไทย"},
+		{"ไทย", 5, "ไทย"},
+		{"", 1, ""},
+	} {
+		if got := runePrefix(c.s, c.n); got != c.want {
+			t.Errorf("runePrefix(%q, %d) = %q, want %q", c.s, c.n, got, c.want)
+		}
+	}
+}
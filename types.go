@@ -1,5 +1,10 @@
 package pythainlp
 
+import (
+	"context"
+	"fmt"
+)
+
 // Token represents a single token with linguistic information
 // This is a subset of tha.Tkn from translitkit, focused on essential fields
 type Token struct {
@@ -11,6 +16,18 @@ type Token struct {
 	// Linguistic properties
 	POS       string `json:"pos,omitempty"`       // Part of speech tag
 	IsLexical bool   `json:"is_lexical"`          // Whether it's Thai text or punctuation/foreign
+	Lemma     string `json:"lemma,omitempty"`     // Base form, filled by the lemma feature of Analyze
+	
+	// Frequency rank in the Thai National Corpus, 1 the most frequent, and
+	// occurrences, filled by the frequency feature of Analyze; 0 if the token
+	// is not in the corpus
+	FrequencyRank int `json:"frequency_rank,omitempty"`
+	Frequency     int `json:"frequency,omitempty"`
+	
+	// Byte offsets of the token in the text, filled by tokenization and by
+	// ThaiOnly analysis
+	Start int `json:"start,omitempty"`
+	End   int `json:"end,omitempty"`
 	
 	// Additional metadata
 	Metadata map[string]interface{} `json:"metadata,omitempty"` // Engine-specific data
@@ -20,39 +37,46 @@ type Token struct {
 type TokenizeResult struct {
 	Tokens []Token  // Structured tokens with linguistic info
 	Raw    []string // Simple tokenized strings
+	Text   string   // Tokenized text, set when TokenizeOptions.Preprocess is used
 	
-	// Metadata
-	Engine         string  `json:"engine"`
-	ProcessingTime float64 `json:"processing_time_ms"`
+	Meta Metadata `json:"metadata"`
 }
 
 // RomanizeResult contains the results of romanization
 type RomanizeResult struct {
-	Text           string   // Full romanized text
-	Tokens         []string // Original tokens (if tokenized first)
-	RomanizedParts []string // Per-token romanization
+	Text           string            // Full romanized text
+	Tokens         []string          // Original tokens (if tokenized first)
+	RomanizedParts []string          // Per-token romanization
+	Alignment      []AlignedSegment  // Set with RomanizeOptions.Align
+	Entities       []RomanizedEntity // Set with RomanizeOptions.ProperNouns
+	Input          string            // Romanized text, set when RomanizeOptions.Preprocess is used
 	
-	// Metadata
-	Engine         string  `json:"engine"`
-	ProcessingTime float64 `json:"processing_time_ms"`
+	Meta Metadata `json:"metadata"`
+}
+
+// RomanizedEntity is a person or place name found by RomanizeOptions.ProperNouns
+type RomanizedEntity struct {
+	Text      string `json:"text"`      // The name as written
+	Type      string `json:"type"`      // "PERSON" or "LOCATION"
+	Romanized string `json:"romanized"` // Its capitalized romanization
+	Token     int    `json:"token"`     // Its index in RomanizeResult.Tokens
 }
 
 // TransliterateResult contains the results of transliteration (phonetic)
 type TransliterateResult struct {
-	Phonetic string // IPA or other phonetic representation
+	Phonetic  string           // IPA or other phonetic representation
+	Alignment []AlignedSegment // Set with TransliterateOptions.Align
+	Text      string           // Transliterated text, set when TransliterateOptions.Preprocess is used
 	
-	// Metadata
-	Engine         string  `json:"engine"`
-	ProcessingTime float64 `json:"processing_time_ms"`
+	Meta Metadata `json:"metadata"`
 }
 
 // SyllableTokenizeResult contains the results of syllable tokenization
 type SyllableTokenizeResult struct {
 	Syllables []string // Syllable segments
+	Text      string   // Segmented text, set when SyllableTokenizeOptions.Preprocess is used
 	
-	// Metadata
-	Engine         string  `json:"engine"`
-	ProcessingTime float64 `json:"processing_time_ms"`
+	Meta Metadata `json:"metadata"`
 }
 
 // AnalyzeResult contains combined analysis results
@@ -62,86 +86,205 @@ type AnalyzeResult struct {
 	Romanized      string   // Full romanized text
 	RomanizedParts []string // Per-token romanization
 	Phonetic       string   // IPA representation
+	PhoneticParts  []string // Per-token IPA
 	Syllables      []string // Syllable segments
+	Text           string   // Analyzed text, set when AnalyzeOptions.Preprocess is used
 	
-	// Metadata
-	Features       []string `json:"features"`
-	ProcessingTime float64  `json:"processing_time_ms"`
+	Features []string `json:"features"`
+	Meta     Metadata `json:"metadata"`
 }
 
 // Engine constants for tokenization
 const (
-	EngineNewMM    = "newmm"    // Default, dictionary-based with TCC
-	EngineLongest  = "longest"  // Dictionary-based, longest matching
-	EngineICU      = "icu"      // ICU-based tokenizer
-	EngineAttaCut  = "attacut"  // Deep learning based
-	EngineDeepCut  = "deepcut"  // Deep learning based
-	EngineNerCut   = "nercut"   // NER-aware tokenizer
-	EngineNLPO3    = "nlpo3"    // Rust-based, fast
-	EngineOSKut    = "oskut"    // Out-of-domain stacked cut
-	EngineSefrCut  = "sefr_cut" // Stacked ensemble
-	EngineTLTK     = "tltk"     // Maximum collocation
+	EngineNewMM   TokenizeEngine = "newmm"    // Default, dictionary-based with TCC
+	EngineLongest TokenizeEngine = "longest"  // Dictionary-based, longest matching
+	EngineICU     TokenizeEngine = "icu"      // ICU-based tokenizer
+	EngineAttaCut TokenizeEngine = "attacut"  // Deep learning based
+	EngineDeepCut TokenizeEngine = "deepcut"  // Deep learning based
+	EngineNerCut  TokenizeEngine = "nercut"   // NER-aware tokenizer
+	EngineNLPO3   TokenizeEngine = "nlpo3"    // Rust-based, fast
+	EngineOSKut   TokenizeEngine = "oskut"    // Out-of-domain stacked cut
+	EngineSefrCut TokenizeEngine = "sefr_cut" // Stacked ensemble
+	EngineTLTK    TokenizeEngine = "tltk"     // Maximum collocation
+
+	// EngineAuto lets the service pick: nlpo3 or newmm for long text, a neural
+	// engine (attacut, deepcut) for social media text or text largely outside
+	// the dictionary, newmm otherwise. TokenizeResult.Engine() reports the choice.
+	EngineAuto TokenizeEngine = "auto"
 )
 
 // Engine constants for romanization
 const (
-	EngineRoyin    = "royin"    // Default, Royal Institute standard
-	EngineThai2Rom = "thai2rom" // Deep learning based
-	EngineTLTKRom  = "tltk"     // TLTK romanization
-	EngineLookup   = "lookup"   // Dictionary lookup
+	EngineRoyin        RomanizeEngine = "royin"         // Default, Royal Institute standard
+	EngineThai2Rom     RomanizeEngine = "thai2rom"      // Deep learning based
+	EngineThai2RomONNX RomanizeEngine = "thai2rom_onnx" // thai2rom on ONNX Runtime
+	EngineTLTKRom      RomanizeEngine = "tltk"          // TLTK romanization
+	EngineLookup       RomanizeEngine = "lookup"        // Dictionary lookup
 )
 
 // Engine constants for transliteration
 const (
-	EngineThaig2p   = "thaig2p"   // Default, Thai grapheme-to-phoneme
-	EngineICUTrans  = "icu"       // ICU transliteration
-	EngineIPA       = "ipa"       // Epitran IPA
-	EngineTLTKG2P   = "tltk_g2p"  // TLTK grapheme-to-phoneme
-	EngineISO11940  = "iso_11940" // ISO 11940 standard
-	EngineTLTKIPA   = "tltk_ipa"  // TLTK IPA
-	EngineThaig2pV2 = "thaig2p_v2" // Version 2 of thaig2p
+	EngineThaig2p   TransliterateEngine = "thaig2p"    // Default, Thai grapheme-to-phoneme
+	EngineICUTrans  TransliterateEngine = "icu"        // ICU transliteration
+	EngineIPA       TransliterateEngine = "ipa"        // Epitran IPA
+	EngineTLTKG2P   TransliterateEngine = "tltk_g2p"   // TLTK grapheme-to-phoneme
+	EngineISO11940  TransliterateEngine = "iso_11940"  // ISO 11940 standard
+	EngineTLTKIPA   TransliterateEngine = "tltk_ipa"   // TLTK IPA
+	EngineThaig2pV2 TransliterateEngine = "thaig2p_v2" // Version 2 of thaig2p
 )
 
 // Engine constants for syllable tokenization
 const (
-	EngineSyllableDict    = "dict"     // Dictionary-based syllable tokenization
-	EngineSyllableHanSolo = "han_solo" // Default, CRF syllable segmenter for social media
-	EngineSyllableSSG     = "ssg"      // CRF syllable segmenter
-	EngineSyllableTLTK    = "tltk"     // Thai Language Toolkit syllable tokenizer
+	EngineSyllableDict    SyllableEngine = "dict"     // Dictionary-based syllable tokenization
+	EngineSyllableHanSolo SyllableEngine = "han_solo" // Default, CRF syllable segmenter for social media
+	EngineSyllableSSG     SyllableEngine = "ssg"      // CRF syllable segmenter
+	EngineSyllableTLTK    SyllableEngine = "tltk"     // Thai Language Toolkit syllable tokenizer
+)
+
+// Engine constants for sentence segmentation
+const (
+	EngineSentenceCRFCut            SentenceEngine = "crfcut"             // Default, CRF trained on Thai sentence boundaries
+	EngineSentenceWhitespaceNewline SentenceEngine = "whitespace+newline" // Split at spaces and line breaks
+	EngineSentenceWhitespace        SentenceEngine = "whitespace"         // Split at spaces
+	EngineSentenceTLTK              SentenceEngine = "tltk"               // Thai Language Toolkit sentence segmenter
+	EngineSentenceWtP               SentenceEngine = "wtp"                // Where's the Point neural segmenter, full mode only
+)
+
+// Engine constants for lemmas, dependency parsers in full mode only
+const (
+	EngineLemmaEsupar    LemmaEngine = "esupar"     // Transformer-based parser
+	EngineLemmaSpacyThai LemmaEngine = "spacy_thai" // spaCy pipeline with a UD parser
 )
 
 // Options for various operations
 type TokenizeOptions struct {
-	Engine         string                 // Tokenization engine to use
+	Engine         TokenizeEngine         // Tokenization engine to use
 	CustomDict     []string               // Custom dictionary entries
-	KeepWhitespace bool                   // Whether to keep whitespace tokens
-	JoinBrokenNum  bool                   // Join broken numbers
 	Extra          map[string]interface{} // Engine-specific options
+
+	// Like PyThaiNLP, tokenization keeps whitespace tokens and joins numbers
+	// the engine split, e.g. at a decimal point. DropWhitespace and
+	// SplitBrokenNum turn that off. Without whitespace tokens, the tokens no
+	// longer add up to the text: Token.Start and Token.End locate them.
+	DropWhitespace bool
+	SplitBrokenNum bool
+
+	// Protect keeps every match of these patterns a single token. Each is one
+	// of the Protect* names or a Python regular expression.
+	Protect []string
+
+	// Granularity merges or splits the compounds the engine found (default:
+	// keep the engine's segmentation)
+	Granularity Granularity
+
+	// Preprocess cleans up the text on the service side before tokenization;
+	// TokenizeResult.Text then holds the tokenized text
+	Preprocess *PreprocessOptions
+}
+
+// Named patterns for TokenizeOptions.Protect
+const (
+	ProtectURL     = "url"
+	ProtectEmail   = "email"
+	ProtectHashtag = "hashtag" // #tag, Thai tags included
+	ProtectMention = "mention" // @user
+	ProtectEmoji   = "emoji"   // Emoji sequences: ZWJ, flags, keycaps, skin tones
+)
+
+// Granularity is how finely compounds are segmented, after the engine, along
+// PyThaiNLP's dictionary. Search indexing may want both รถไฟฟ้า and its parts,
+// while a reading aid may want the whole word.
+type Granularity string
+
+const (
+	// GranularityCoarse joins adjacent tokens, up to four, that together form
+	// a dictionary word, preferring the longest: รถ ไฟ ฟ้า becomes รถไฟฟ้า
+	GranularityCoarse Granularity = "coarse"
+	// GranularityFine splits each Thai token into as many dictionary words
+	// of two characters or more as cover it exactly: รถไฟฟ้า becomes รถ ไฟ
+	// ฟ้า. Tokens without such a split are kept whole.
+	GranularityFine Granularity = "fine"
+)
+
+// Validate returns an error if the granularity is unknown. The empty
+// granularity keeps the engine's segmentation and is valid.
+func (g Granularity) Validate() error {
+	switch g {
+	case "", GranularityCoarse, GranularityFine:
+		return nil
+	}
+	return fmt.Errorf("unknown granularity %q, expected %q or %q", g, GranularityCoarse, GranularityFine)
 }
 
 type RomanizeOptions struct {
-	Engine          string // Romanization engine to use
-	TokenizeFirst   bool   // Whether to tokenize before romanizing
-	FallbackEngine  string // Fallback for lookup engine
+	Engine          RomanizeEngine // Romanization engine to use
+	TokenizeFirst   bool           // Whether to tokenize before romanizing
+	FallbackEngine  RomanizeEngine // Engine for words missing from the lookup engine's table (PyThaiNLP default: thai2rom)
+	Align           bool           // Fill RomanizeResult.Alignment (implies TokenizeFirst)
+
+	// Overrides maps words, e.g. names and brands, to romanizations that take
+	// precedence over the engine. Each word is kept a single token. They add
+	// to the overrides given to WithRomanizeOverrides, replacing those for the
+	// same words, and imply TokenizeFirst.
+	Overrides map[string]string
+
+	// ProperNouns runs named entity recognition (thainer) first and
+	// romanizes each person and place name as one token: with the lookup
+	// engine's table of names where it has them, the words of the name run
+	// together and capitalized, e.g. "Somchai Chaidi" rather than "somchai
+	// chai di". RomanizeResult.Entities lists the names. Implies TokenizeFirst.
+	ProperNouns bool
+
+	// Consistent builds a table of the romanization of each distinct word of
+	// the text first and romanizes every occurrence of a word the same way,
+	// see RomanizeDocument. Implies TokenizeFirst; not with ProperNouns.
+	Consistent bool
+
+	// Preprocess cleans up the text on the service side before
+	// romanization; RomanizeResult.Input then holds the romanized text
+	Preprocess *PreprocessOptions
 }
 
 type TransliterateOptions struct {
-	Engine string // Transliteration engine to use
+	Engine     TransliterateEngine // Transliteration engine to use
+	Align      bool                // Also transliterate word by word to fill TransliterateResult.Alignment
+	Preprocess *PreprocessOptions  // Clean up the text first; TransliterateResult.Text then holds the transliterated text
 }
 
 type SyllableTokenizeOptions struct {
-	Engine         string // Syllable tokenization engine to use
-	KeepWhitespace bool   // Whether to keep whitespace tokens
+	Engine         SyllableEngine     // Syllable tokenization engine to use
+	KeepWhitespace bool               // Whether to keep whitespace tokens
+	Preprocess     *PreprocessOptions // Clean up the text first; SyllableTokenizeResult.Text then holds the segmented text
 }
 
 type AnalyzeOptions struct {
-	Features            []string // Features to extract: tokenize, romanize, transliterate, syllable
-	TokenizeEngine      string   // Engine for tokenization
-	RomanizeEngine      string   // Engine for romanization
-	TransliterateEngine string   // Engine for transliteration
-	SyllableEngine      string   // Engine for syllable tokenization
+	Features            []string // Features to extract: tokenize, romanize, transliterate, syllable, frequency, lemma
+	TokenizeEngine      TokenizeEngine      // Engine for tokenization
+	RomanizeEngine      RomanizeEngine      // Engine for romanization
+	TransliterateEngine TransliterateEngine // Engine for transliteration
+	SyllableEngine      SyllableEngine      // Engine for syllable tokenization
+	LemmaEngine         LemmaEngine         // Parser for lemmas (default none, normalized tokens only)
+	Granularity         Granularity         // Merge or split compounds after tokenization
+
+	// ThaiOnly sends only the Thai spans of the text (see ScriptSpans) to the
+	// service, one request each, and keeps every other run of non-space
+	// characters, such as a URL, an English word or a number, as a single
+	// non-lexical token left as is. Tokens get their offsets in text. Mixed
+	// Thai and English text tokenizes better this way.
+	ThaiOnly bool
+	// Preprocess cleans up the text on the service side before analysis;
+	// AnalyzeResult.Text then holds the text that was analyzed
+	Preprocess *PreprocessOptions
+	// NonThai, if set with ThaiOnly, produces the tokens of the non-Thai runs
+	// instead, e.g. to romanize English words with another library. Calls
+	// with a handler are not cached, see WithSharedCache.
+	NonThai SegmentHandler `json:"-"`
 }
 
+// SegmentHandler analyzes a run of text the Thai engines are not given. The
+// surfaces of the tokens it returns should appear in segment in order.
+type SegmentHandler func(ctx context.Context, segment string) ([]Token, error)
+
 // Error types
 type PyThaiNLPError struct {
 	Code    string
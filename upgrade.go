@@ -0,0 +1,121 @@
+package pythainlp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
+// UpgradeDependencies upgrades PyThaiNLP and the engine packages of the current
+// mode inside the running container, then restarts the service and waits for it
+// to pass a health check. This avoids a full image rebuild for small updates;
+// the upgrade is lost when the container is recreated.
+// Progress is reported per package through the download progress callback.
+func (pm *PyThaiNLPManager) UpgradeDependencies(ctx context.Context) error {
+	if err := pm.ensureReady(ctx); err != nil {
+		return err
+	}
+	if pm.offline {
+		return fmt.Errorf("dependency upgrade disabled in offline mode")
+	}
+
+	dockerClient, err := pm.docker.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to get Docker client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	requirements := lightRequirements
+	if !pm.lightweightMode {
+		requirements = fullRequirements
+	}
+	packages := requirementLines(requirements)
+	// A pinned version is reapplied by startService, only follow the latest otherwise
+	if pm.pythainlpVersion == "" {
+		packages = append(packages, "pythainlp")
+	}
+
+	if err := pm.installPackages(ctx, dockerClient, packages, true); err != nil {
+		return err
+	}
+
+	if err := pm.restartService(ctx, dockerClient); err != nil {
+		return fmt.Errorf("service failed to restart after upgrade: %w", err)
+	}
+
+	health, err := pm.client.Health(ctx)
+	if err != nil {
+		return fmt.Errorf("health check failed after upgrade: %w", err)
+	}
+	Logger.Info().Str("version", health.Version).Msg("Dependencies upgraded")
+	return nil
+}
+
+// installPackages pip installs packages one by one inside the container,
+// reporting progress per package through the download progress callback
+func (pm *PyThaiNLPManager) installPackages(ctx context.Context, dockerClient *client.Client, packages []string, upgrade bool) error {
+	verb := "Installing"
+	if upgrade {
+		verb = "Upgrading"
+	}
+
+	total := int64(len(packages))
+	for i, pkg := range packages {
+		Logger.Info().Str("package", pkg).Msg(verb + " dependency")
+		pm.startupProgress(StartupPipInstall, verb+" "+pkg, int64(i), total)
+		if pm.downloadProgressCallback != nil {
+			pm.downloadProgressCallback(int64(i), total, verb+" "+pkg)
+		}
+
+		cmd := []string{"pip", "install", "--no-cache-dir", "--quiet"}
+		if upgrade {
+			cmd = append(cmd, "--upgrade")
+		}
+		cmd = append(cmd, fmt.Sprintf("'%s'", pkg))
+		if _, err := pm.execCommand(ctx, dockerClient, cmd); err != nil {
+			return newInstallError(pkg, err)
+		}
+	}
+	if pm.downloadProgressCallback != nil {
+		pm.downloadProgressCallback(total, total, "Restarting service")
+	}
+	return nil
+}
+
+// restartService restarts the Python service so that it imports the newly
+// installed packages, and waits for it to be ready
+func (pm *PyThaiNLPManager) restartService(ctx context.Context, dockerClient *client.Client) (err error) {
+	pm.beginStartup()
+	defer func() { pm.endStartup(err) }()
+
+	// The running server keeps the old modules imported until restarted
+	pm.mu.Lock()
+	pm.serviceReady = false
+	pm.mu.Unlock()
+
+	if err := pm.killServiceProcess(ctx, dockerClient); err != nil {
+		return fmt.Errorf("failed to stop service: %w", err)
+	}
+	return pm.startService(ctx)
+}
+
+// requirementLines returns the requirement specifiers of a requirements file,
+// without comments and blank lines
+func requirementLines(requirements []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(requirements))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,125 @@
+package pythainlp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// WithPyThaiNLPVersion pins the PyThaiNLP version installed in the container.
+// Accepts an exact version ("5.0.4"), a wildcard ("5.0.x" or "5.0.*") or a pip
+// specifier (">=5.0,<5.1"); NewManager rejects anything else. The version is
+// installed with pip on Init when the image ships a different one, so results
+// stay stable across image updates.
+func WithPyThaiNLPVersion(version string) ManagerOption {
+	return func(pm *PyThaiNLPManager) {
+		pm.pythainlpVersion = version
+	}
+}
+
+// PyThaiNLPVersion returns the PyThaiNLP version resolved in the container
+// during Init, or an empty string before Init
+func (pm *PyThaiNLPManager) PyThaiNLPVersion() string {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.resolvedVersion
+}
+
+// versionClause matches one clause of a PEP 440 version specifier, or a bare
+// version. Versions are limited to the characters PEP 440 allows, so that the
+// requirement is safe to pass through a shell.
+var versionClause = regexp.MustCompile(`^\s*(~=|===|==|!=|<=|>=|<|>)?\s*[0-9A-Za-z][0-9A-Za-z.*+!_-]*\s*$`)
+
+// pipRequirement converts a version selector into a pip requirement for
+// pythainlp, or returns an error if it is not a PEP 440 version or specifier
+func pipRequirement(version string) (string, error) {
+	version = strings.TrimSpace(version)
+	for _, clause := range strings.Split(version, ",") {
+		if !versionClause.MatchString(clause) {
+			return "", fmt.Errorf("invalid PyThaiNLP version %q: expected a version such as 5.0.4 or 5.0.x, or a pip specifier such as >=5.0,<5.1", version)
+		}
+	}
+	if strings.ContainsAny(version[:1], "<>=!~") {
+		return "pythainlp" + version, nil
+	}
+	if strings.HasSuffix(version, ".x") {
+		version = strings.TrimSuffix(version, ".x") + ".*"
+	}
+	return "pythainlp==" + version, nil
+}
+
+// ensurePyThaiNLPVersion installs the pinned version if needed and records the
+// resolved version. Callers must hold pm.mu. Reports whether the version changed.
+func (pm *PyThaiNLPManager) ensurePyThaiNLPVersion(ctx context.Context, dockerClient *client.Client) (bool, error) {
+	before, err := pm.installedVersion(ctx, dockerClient)
+	if err != nil {
+		return false, err
+	}
+	pm.resolvedVersion = before
+
+	if pm.pythainlpVersion == "" {
+		return false, nil
+	}
+
+	// pip leaves a satisfied requirement untouched without contacting the index
+	requirement, err := pipRequirement(pm.pythainlpVersion)
+	if err != nil {
+		return false, err
+	}
+	Logger.Debug().Str("requirement", requirement).Str("installed", before).Msg("Ensuring PyThaiNLP version")
+	installCmd := []string{"pip", "install", "--no-cache-dir", "--quiet", fmt.Sprintf("'%s'", requirement)}
+	if _, err := pm.execCommand(ctx, dockerClient, installCmd); err != nil {
+		return false, newInstallError(requirement, err)
+	}
+
+	after, err := pm.installedVersion(ctx, dockerClient)
+	if err != nil {
+		return false, err
+	}
+	pm.resolvedVersion = after
+
+	if after != before {
+		Logger.Info().Str("from", before).Str("to", after).Msg("Installed pinned PyThaiNLP version")
+	}
+	return after != before, nil
+}
+
+// installedVersion returns the PyThaiNLP version installed in the container
+func (pm *PyThaiNLPManager) installedVersion(ctx context.Context, dockerClient *client.Client) (string, error) {
+	cmd := []string{"python", "-c", "'import importlib.metadata as m; print(m.version(\"pythainlp\"))'"}
+	output, err := pm.execCommand(ctx, dockerClient, cmd)
+	if err != nil {
+		return "", fmt.Errorf("failed to read installed PyThaiNLP version: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// killServiceProcess terminates the supervisor, then server.py, so the service
+// can be restarted. The patterns are written so that they do not match the
+// command line of the loop running them.
+func (pm *PyThaiNLPManager) killServiceProcess(ctx context.Context, dockerClient *client.Client) error {
+	for _, pattern := range []string{`pythainlp-superviso[r]`, `service/server\.py`} {
+		cmd := []string{
+			`for p in /proc/[0-9]*; do`,
+			fmt.Sprintf(`if grep -qa '%s' $p/cmdline 2>/dev/null; then kill ${p#/proc/} 2>/dev/null; fi;`, pattern),
+			`done; true`,
+		}
+		if _, err := pm.execCommand(ctx, dockerClient, cmd); err != nil {
+			return err
+		}
+	}
+
+	// Wait for the port to be released before the new server binds it
+	for i := 0; i < 20 && pm.isServiceRunning(ctx); i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pm.healthCheckInterval):
+		}
+	}
+	return nil
+}
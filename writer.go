@@ -0,0 +1,94 @@
+package pythainlp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+)
+
+// RomanizeWriter romanizes the Thai text written to it and writes the result
+// to an underlying writer, e.g. to pipe logs or transcripts through
+// romanization. Written text is buffered until a line is complete, and all
+// the lines completed by a Write are romanized in a single request; spaces,
+// line breaks and non-Thai text are copied unchanged.
+type RomanizeWriter struct {
+	ctx  context.Context
+	nlp  ThaiNLP
+	opts RomanizeOptions
+	w    io.Writer
+	buf  []byte
+}
+
+// NewRomanizeWriter returns a writer romanizing with nlp into w. Requests
+// are made with ctx. Call Close, or Flush, to write a final incomplete line.
+func NewRomanizeWriter(ctx context.Context, nlp ThaiNLP, w io.Writer, opts RomanizeOptions) *RomanizeWriter {
+	opts.Align = true
+	return &RomanizeWriter{ctx: ctx, nlp: nlp, opts: opts, w: w}
+}
+
+// Write implements io.Writer. It reports p as written once buffered, so an
+// error romanizing or writing may come from a previous call's text.
+func (rw *RomanizeWriter) Write(p []byte) (int, error) {
+	rw.buf = append(rw.buf, p...)
+	end := bytes.LastIndexByte(rw.buf, '\n') + 1
+	if end == 0 && len(rw.buf) >= scanChunkSize {
+		// A long line: cut it at a space rather than buffer it all
+		end = scanBoundary(rw.buf, false)
+	}
+	if end == 0 {
+		return len(p), nil
+	}
+	if err := rw.romanize(end); err != nil {
+		return len(p), err
+	}
+	return len(p), nil
+}
+
+// Flush romanizes and writes all buffered text
+func (rw *RomanizeWriter) Flush() error {
+	return rw.romanize(len(rw.buf))
+}
+
+// Close flushes the writer. It does not close the underlying writer.
+func (rw *RomanizeWriter) Close() error {
+	return rw.Flush()
+}
+
+// romanize writes the romanization of the first n buffered bytes
+func (rw *RomanizeWriter) romanize(n int) error {
+	if n == 0 {
+		return nil
+	}
+	chunk := string(rw.buf[:n])
+	rw.buf = rw.buf[n:]
+	if !isThaiText(chunk) {
+		_, err := io.WriteString(rw.w, chunk)
+		return err
+	}
+	res, err := rw.nlp.RomanizeWithOptions(rw.ctx, chunk, rw.opts)
+	if err != nil {
+		return err
+	}
+	if len(res.Alignment) == 0 {
+		// Implementations without alignment, e.g. test fakes
+		_, err = io.WriteString(rw.w, res.Text)
+		return err
+	}
+	var out strings.Builder
+	thai := false // the previous segment was romanized Thai
+	for _, seg := range res.Alignment {
+		if isThaiText(seg.Input) {
+			// Separate the romanizations of adjacent Thai words
+			if thai {
+				out.WriteByte(' ')
+			}
+			thai = true
+		} else {
+			thai = false
+		}
+		out.WriteString(seg.Output)
+	}
+	_, err = io.WriteString(rw.w, out.String())
+	return err
+}